@@ -0,0 +1,164 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+)
+
+// toolAlias is an additional tool registered with RegisterToolAlias for an existing route
+type toolAlias struct {
+	boundArgs   map[string]any
+	extraSchema any
+	name        string
+	description string
+	method      string
+	path        string
+}
+
+// RegisterToolAlias exposes a route as an additional, narrowed tool. Every call to the alias
+// is sent to the route with boundArgs merged into the arguments, overriding any argument of
+// the same name; the bound arguments are removed from the alias input schema. extraSchema,
+// if not nil, is merged into the alias input schema. The original tool keeps being listed
+// unless HideOriginal is called for the route.
+//
+// It returns an error if the alias name is empty or already used by another alias or custom tool.
+//
+// Example:
+//
+//	mcp.RegisterToolAlias("search_users", "Search users", "GET", "/search", map[string]any{"type": "users"}, nil)
+//	mcp.RegisterToolAlias("search_orders", "Search orders", "GET", "/search", map[string]any{"type": "orders"}, nil)
+//	mcp.HideOriginal("GET", "/search")
+func (e *EchoMCP) RegisterToolAlias(name, description, method, path string, boundArgs map[string]any, extraSchema any) error {
+	if name == "" {
+		return errors.New("alias name is required")
+	}
+	if _, isCustom := e.customToolHandler(name); isCustom {
+		return fmt.Errorf("alias '%s' conflicts with a custom tool", name)
+	}
+
+	e.aliasesMu.Lock()
+	defer e.aliasesMu.Unlock()
+
+	if slices.ContainsFunc(e.aliases, func(alias toolAlias) bool { return alias.name == name }) {
+		return fmt.Errorf("alias '%s' is already registered", name)
+	}
+
+	e.aliases = append(e.aliases, toolAlias{
+		name:        name,
+		description: description,
+		method:      method,
+		path:        path,
+		boundArgs:   maps.Clone(boundArgs),
+		extraSchema: extraSchema,
+	})
+
+	return nil
+}
+
+// HideOriginal removes the tool generated for a route from tools/list, leaving only its
+// aliases. The route stays callable through the aliases registered with RegisterToolAlias.
+func (e *EchoMCP) HideOriginal(method, path string) {
+	e.aliasesMu.Lock()
+	defer e.aliasesMu.Unlock()
+
+	key := fmt.Sprintf("%s %s", method, path)
+	if !slices.Contains(e.hiddenOriginals, key) {
+		e.hiddenOriginals = append(e.hiddenOriginals, key)
+	}
+}
+
+// applyAliases adds the alias tools and operations for the generated route tools and drops
+// hidden originals from the listing. Aliases whose route has no tool are reported as warnings.
+func (e *EchoMCP) applyAliases(tools []types.Tool, operations map[string]types.Operation, owners map[string]*echoInstance) []types.Tool {
+	e.aliasesMu.RLock()
+	aliases := slices.Clone(e.aliases)
+	hidden := slices.Clone(e.hiddenOriginals)
+	e.aliasesMu.RUnlock()
+
+	// Index the generated tools by route
+	toolsByRoute := make(map[string]types.Tool)
+	for _, tool := range tools {
+		if operation, exists := operations[tool.Name]; exists {
+			toolsByRoute[fmt.Sprintf("%s %s", operation.Method, operation.Path)] = tool
+		}
+	}
+
+	for _, alias := range aliases {
+		routeKey := fmt.Sprintf("%s %s", alias.method, alias.path)
+		original, exists := toolsByRoute[routeKey]
+		if !exists {
+			e.addWarning(fmt.Sprintf("alias '%s' targets %s, which is not exposed as a tool", alias.name, routeKey))
+			continue
+		}
+		if _, conflicts := operations[alias.name]; conflicts {
+			e.addWarning(fmt.Sprintf("alias '%s' conflicts with a route tool", alias.name))
+			continue
+		}
+
+		tool := original
+		tool.Name = alias.name
+		if alias.description != "" {
+			tool.Description = alias.description
+		}
+		tool.InputSchema = aliasInputSchema(original.InputSchema, alias)
+		tools = append(tools, tool)
+
+		operation := operations[original.Name]
+		operation.BoundArgs = alias.boundArgs
+		operations[alias.name] = operation
+		if owner, hasOwner := owners[original.Name]; hasOwner {
+			owners[alias.name] = owner
+		}
+	}
+
+	if len(hidden) == 0 {
+		return tools
+	}
+
+	hiddenNames := make(map[string]bool, len(hidden))
+	for _, routeKey := range hidden {
+		if original, exists := toolsByRoute[routeKey]; exists {
+			hiddenNames[original.Name] = true
+		}
+	}
+	return slices.DeleteFunc(tools, func(tool types.Tool) bool { return hiddenNames[tool.Name] })
+}
+
+// aliasInputSchema returns the input schema of the original tool without the bound
+// arguments, merged with the extra schema of the alias
+func aliasInputSchema(inputSchema any, alias toolAlias) any {
+	schema, ok := inputSchema.(map[string]any)
+	if !ok {
+		return inputSchema
+	}
+	schema = maps.Clone(schema)
+
+	if properties, ok := schema["properties"].(map[string]any); ok {
+		properties = maps.Clone(properties)
+		for name := range alias.boundArgs {
+			delete(properties, name)
+		}
+		schema["properties"] = properties
+	}
+
+	requiredFields, _ := schema["required"].([]string)
+	required := slices.DeleteFunc(slices.Clone(requiredFields), func(name string) bool {
+		_, bound := alias.boundArgs[name]
+		return bound
+	})
+	if len(required) > 0 {
+		schema["required"] = required
+	} else {
+		delete(schema, "required")
+	}
+
+	if alias.extraSchema != nil {
+		schema = types.MergeSchemas(schema, types.GetSchema(alias.extraSchema))
+	}
+
+	return types.NormalizePropertyOrder(schema)
+}
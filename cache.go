@@ -0,0 +1,147 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/transport"
+)
+
+// noCacheArgument lets a client skip the response cache for a single tool call
+const noCacheArgument = "_noCache"
+
+// responseCache holds the results of GET and HEAD tool calls for Config.CacheTTL
+type responseCache struct {
+	entries map[string]cacheEntry
+	mu      sync.Mutex
+}
+
+// cacheEntry is a cached tool result
+type cacheEntry struct {
+	expires time.Time
+	result  any
+	tool    string
+}
+
+// Invalidate drops every cached result of the named tool, so its next call reaches the upstream.
+//
+// Example:
+//
+//	mcp.Invalidate("GET_users_id")
+func (e *EchoMCP) Invalidate(toolName string) {
+	toolName = strings.TrimPrefix(toolName, e.toolPrefix())
+
+	e.cache.mu.Lock()
+	defer e.cache.mu.Unlock()
+
+	for key, entry := range e.cache.entries {
+		if entry.tool == toolName {
+			delete(e.cache.entries, key)
+		}
+	}
+}
+
+// popNoCache removes the _noCache argument from parameters and reports whether it was set
+func popNoCache(parameters map[string]any) (map[string]any, bool) {
+	value, exists := parameters[noCacheArgument]
+	if !exists {
+		return parameters, false
+	}
+
+	parameters = maps.Clone(parameters)
+	delete(parameters, noCacheArgument)
+
+	noCache, _ := value.(bool)
+	return parameters, noCache || value == "true"
+}
+
+// cacheKey returns the cache key of a tool call, or "" when its result must not be cached.
+// Calls carrying cookies are scoped to their MCP session so users never share results.
+func (e *EchoMCP) cacheKey(ctx context.Context, operationID, method string, parameters map[string]any) string {
+	if e.config.CacheTTL <= 0 || !isSafeMethod(method) {
+		return ""
+	}
+
+	// encoding/json sorts map keys, giving a canonical form of the arguments
+	arguments, err := json.Marshal(parameters)
+	if err != nil {
+		return ""
+	}
+	hash := sha256.Sum256(arguments)
+
+	key := operationID + " " + hex.EncodeToString(hash[:])
+	if e.config.ForwardCookies || e.config.EnableCookieJar {
+		key = transport.SessionIDFromContext(ctx) + " " + key
+	}
+	return key
+}
+
+// cachedResult returns the unexpired result stored under key
+func (e *EchoMCP) cachedResult(key string) (any, bool) {
+	e.cache.mu.Lock()
+	defer e.cache.mu.Unlock()
+
+	entry, exists := e.cache.entries[key]
+	if !exists {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(e.cache.entries, key)
+		return nil, false
+	}
+	return entry.result, true
+}
+
+// storeResult caches a successful response unless the upstream disallowed it with Cache-Control: no-store
+func (e *EchoMCP) storeResult(key, operationID string, resp *http.Response, result any) {
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return
+	}
+	if strings.Contains(strings.ToLower(resp.Header.Get("Cache-Control")), "no-store") {
+		return
+	}
+
+	e.cache.mu.Lock()
+	defer e.cache.mu.Unlock()
+
+	if e.cache.entries == nil {
+		e.cache.entries = make(map[string]cacheEntry)
+	}
+	if _, exists := e.cache.entries[key]; !exists && e.config.CacheMaxEntries > 0 {
+		e.evictLocked()
+	}
+
+	e.cache.entries[key] = cacheEntry{
+		tool:    operationID,
+		result:  result,
+		expires: time.Now().Add(e.config.CacheTTL),
+	}
+}
+
+// evictLocked makes room for a new entry by dropping expired entries, then the oldest ones
+func (e *EchoMCP) evictLocked() {
+	now := time.Now()
+	for key, entry := range e.cache.entries {
+		if now.After(entry.expires) {
+			delete(e.cache.entries, key)
+		}
+	}
+
+	for len(e.cache.entries) >= e.config.CacheMaxEntries {
+		var oldestKey string
+		var oldest time.Time
+		for key, entry := range e.cache.entries {
+			if oldestKey == "" || entry.expires.Before(oldest) {
+				oldestKey, oldest = key, entry.expires
+			}
+		}
+		delete(e.cache.entries, oldestKey)
+	}
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+)
+
+// Clone returns an independent copy of the MCP server that shares the Echo instance.
+// Registered schemas, endpoint filters, custom tools, aliases, completion providers, tool timeouts
+// and the configuration are deep-copied, so the clone can be reconfigured and mounted at a different
+// path without affecting the original. The clone starts unmounted and without session state.
+//
+// Example:
+//
+//	full := server.New(e)
+//	readOnly := full.Clone()
+//	readOnly.RegisterEndpoints([]string{"/users", "/orders"})
+//	full.Mount("/mcp")
+//	readOnly.Mount("/mcp/read-only")
+func (e *EchoMCP) Clone() *EchoMCP {
+	clone := &EchoMCP{
+		echo:         e.echo,
+		name:         e.name,
+		version:      e.version,
+		description:  e.description,
+		baseURL:      e.baseURL,
+		config:       e.config.clone(),
+		configErr:    e.configErr,
+		swaggerSpec:  e.swaggerSpec,
+		swaggerErr:   e.swaggerErr,
+		descTemplate: e.descTemplate,
+		tools:        []types.Tool{},
+		operations:   make(map[string]types.Operation),
+		cookieJars:   make(map[string]http.CookieJar),
+	}
+
+	e.schemasMu.RLock()
+	clone.registeredSchemas = maps.Clone(e.registeredSchemas)
+	e.schemasMu.RUnlock()
+
+	e.endpointsMu.RLock()
+	clone.includeEndpoints = slices.Clone(e.includeEndpoints)
+	clone.excludeEndpoints = slices.Clone(e.excludeEndpoints)
+	e.endpointsMu.RUnlock()
+
+	e.instancesMu.RLock()
+	for _, instance := range e.instances {
+		copied := *instance
+		copied.excludeEndpoints = slices.Clone(instance.excludeEndpoints)
+		clone.instances = append(clone.instances, &copied)
+	}
+	e.instancesMu.RUnlock()
+
+	e.customToolsMu.RLock()
+	clone.customTools = maps.Clone(e.customTools)
+	clone.customToolOrder = slices.Clone(e.customToolOrder)
+	e.customToolsMu.RUnlock()
+
+	e.aliasesMu.RLock()
+	clone.aliases = slices.Clone(e.aliases)
+	clone.hiddenOriginals = slices.Clone(e.hiddenOriginals)
+	e.aliasesMu.RUnlock()
+
+	e.completionsMu.RLock()
+	clone.completions = maps.Clone(e.completions)
+	e.completionsMu.RUnlock()
+
+	e.timeoutsMu.RLock()
+	clone.toolTimeouts = maps.Clone(e.toolTimeouts)
+	e.timeoutsMu.RUnlock()
+
+	e.clientMu.RLock()
+	clone.client = e.client
+	e.clientMu.RUnlock()
+
+	e.warningsMu.Lock()
+	clone.warnings = slices.Clone(e.warnings)
+	e.warningsMu.Unlock()
+
+	clone.executeToolFunc = clone.defaultExecuteTool
+
+	return clone
+}
+
+// clone returns a deep copy of the configuration
+func (c *Config) clone() *Config {
+	copied := *c
+
+	copied.RequestHeaders = maps.Clone(c.RequestHeaders)
+	copied.CORSOrigins = slices.Clone(c.CORSOrigins)
+	copied.IncludeOperations = slices.Clone(c.IncludeOperations)
+	copied.ExcludeOperations = slices.Clone(c.ExcludeOperations)
+	copied.IncludeTags = slices.Clone(c.IncludeTags)
+	copied.ExcludeTags = slices.Clone(c.ExcludeTags)
+	copied.IncludeEndpoints = slices.Clone(c.IncludeEndpoints)
+	copied.ExcludeEndpoints = slices.Clone(c.ExcludeEndpoints)
+	copied.ExcludeHTTPMethods = slices.Clone(c.ExcludeHTTPMethods)
+	copied.IncludeResponseHeaders = slices.Clone(c.IncludeResponseHeaders)
+	copied.Retry.RetryOn = slices.Clone(c.Retry.RetryOn)
+	copied.Retry.Methods = slices.Clone(c.Retry.Methods)
+
+	if c.SkipHeadRoutes != nil {
+		skip := *c.SkipHeadRoutes
+		copied.SkipHeadRoutes = &skip
+	}
+
+	return &copied
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+)
+
+// maxCompletionValues is the number of completion values returned at most per request
+const maxCompletionValues = 100
+
+// CompletionProvider returns the candidate values of a tool argument starting with prefix
+type CompletionProvider func(prefix string) []string
+
+// CompletionResponse is the result of a completion/complete request
+type CompletionResponse struct {
+	Completion CompletionValues `json:"completion"`
+}
+
+// CompletionValues lists the candidate values of an argument, capped at 100 entries
+type CompletionValues struct {
+	Values  []string `json:"values"`
+	Total   int      `json:"total"`
+	HasMore bool     `json:"hasMore"`
+}
+
+// RegisterCompletion registers a provider of dynamic completion values, such as existing
+// user IDs, for an argument of a tool. tool is the tool name without Config.ToolPrefix.
+// Providers take precedence over the enum values declared in the tool input schema.
+//
+// Example:
+//
+//	mcp.RegisterCompletion("GET_users_id", "id", func(prefix string) []string {
+//		return userStore.IDsWithPrefix(prefix)
+//	})
+func (e *EchoMCP) RegisterCompletion(tool, arg string, fn func(prefix string) []string) {
+	e.completionsMu.Lock()
+	defer e.completionsMu.Unlock()
+
+	key := completionKey(tool, arg)
+	if fn == nil {
+		delete(e.completions, key)
+		return
+	}
+	if e.completions == nil {
+		e.completions = make(map[string]CompletionProvider)
+	}
+	e.completions[key] = fn
+}
+
+// completionKey returns the key of a tool argument in the completions map
+func completionKey(tool, arg string) string {
+	return tool + " " + arg
+}
+
+// handleComplete handles completion/complete requests for tool arguments, referenced as
+// {"ref": {"type": "ref/tool", "name": "GET_users"}, "argument": {"name": "status", "value": "ac"}}.
+// Values come from a registered provider, or else from the enum of the argument schema
+// (jsonschema enum tags and swagger parameter enums); nested arguments use dotted names.
+func (e *EchoMCP) handleComplete(params any) (any, error) {
+	paramMap, _ := params.(map[string]any)
+	ref, _ := paramMap["ref"].(map[string]any)
+	argument, _ := paramMap["argument"].(map[string]any)
+
+	toolName, _ := ref["name"].(string)
+	argName, _ := argument["name"].(string)
+	prefix, _ := argument["value"].(string)
+	if refType, _ := ref["type"].(string); refType != "ref/tool" || toolName == "" || argName == "" {
+		return nil, toMCPError(InvalidParams("completion requires a ref/tool reference and an argument name", nil))
+	}
+
+	tool, found := e.findTool(toolName)
+	if !found {
+		return nil, e.unknownToolError(toolName)
+	}
+
+	e.completionsMu.RLock()
+	provider, hasProvider := e.completions[completionKey(strings.TrimPrefix(toolName, e.toolPrefix()), argName)]
+	e.completionsMu.RUnlock()
+
+	var values []string
+	if hasProvider {
+		values = provider(prefix)
+	} else {
+		inputSchema, _ := tool.InputSchema.(map[string]any)
+		for _, value := range enumValues(argumentSchema(inputSchema, argName)) {
+			if candidate := fmt.Sprint(value); strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(prefix)) {
+				values = append(values, candidate)
+			}
+		}
+	}
+
+	return completionResponse(values), nil
+}
+
+// findTool returns the listed tool with the given name
+func (e *EchoMCP) findTool(name string) (types.Tool, bool) {
+	for _, tool := range e.GetTools() {
+		if tool.Name == name {
+			return tool, true
+		}
+	}
+	return types.Tool{}, false
+}
+
+// argumentSchema returns the property schema of an argument, following dotted names such as
+// "body.status" into nested object properties, or nil if the schema does not declare it
+func argumentSchema(schema map[string]any, name string) map[string]any {
+	for part := range strings.SplitSeq(name, ".") {
+		properties, _ := schema["properties"].(map[string]any)
+		schema, _ = properties[part].(map[string]any)
+	}
+	return schema
+}
+
+// enumValues returns the enum of a property schema, or of its items for array properties
+func enumValues(property map[string]any) []any {
+	if enum, ok := property["enum"].([]any); ok {
+		return enum
+	}
+	if items, ok := property["items"].(map[string]any); ok {
+		enum, _ := items["enum"].([]any)
+		return enum
+	}
+	return nil
+}
+
+// completionResponse caps values at maxCompletionValues, reporting the total and whether more exist
+func completionResponse(values []string) CompletionResponse {
+	completion := CompletionValues{
+		Values: values,
+		Total:  len(values),
+	}
+	if completion.Values == nil {
+		completion.Values = []string{}
+	}
+	if len(values) > maxCompletionValues {
+		completion.Values = values[:maxCompletionValues]
+		completion.HasMore = true
+	}
+
+	return CompletionResponse{Completion: completion}
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/transport"
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+)
+
+// BusyErrorCode is set as "code" in the error data of tool calls rejected because the
+// concurrent tool call limit was reached
+const BusyErrorCode = "busy"
+
+// acquireToolCallSlot reserves a slot under Config.MaxConcurrentToolCallsPerSession and
+// Config.MaxConcurrentToolCalls, waiting up to Config.ToolCallQueueTimeout for one to free
+// up. It returns a function releasing the slots, or a server busy error once the wait is over.
+func (e *EchoMCP) acquireToolCallSlot(ctx context.Context) (func(), error) {
+	waitCtx, cancel := context.WithTimeout(ctx, e.config.ToolCallQueueTimeout)
+	defer cancel()
+
+	var acquired []chan struct{}
+	release := func() {
+		for _, slots := range acquired {
+			<-slots
+		}
+	}
+
+	for _, slots := range []chan struct{}{e.sessionToolCallSlots(ctx), e.globalToolCallSlots()} {
+		if slots == nil {
+			continue
+		}
+
+		if err := acquireSlot(waitCtx, slots); err != nil {
+			release()
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, types.NewMCPError(types.ErrCodeServerBusy,
+				fmt.Sprintf("server busy: %d tool calls already in progress", cap(slots)),
+				map[string]any{"code": BusyErrorCode})
+		}
+		acquired = append(acquired, slots)
+	}
+
+	return release, nil
+}
+
+// acquireSlot takes a free slot, waiting until ctx is done when none is available
+func acquireSlot(ctx context.Context, slots chan struct{}) error {
+	select {
+	case slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// globalToolCallSlots returns the tool call slots shared by all sessions, creating them if
+// needed. It returns nil when there is no global limit.
+func (e *EchoMCP) globalToolCallSlots() chan struct{} {
+	limit := e.config.MaxConcurrentToolCalls
+	if limit <= 0 {
+		return nil
+	}
+
+	e.slotsMu.Lock()
+	defer e.slotsMu.Unlock()
+
+	if e.toolCallSlots == nil {
+		e.toolCallSlots = make(chan struct{}, limit)
+	}
+	return e.toolCallSlots
+}
+
+// sessionToolCallSlots returns the tool call slots of the MCP session carried by ctx, creating
+// them if needed. It returns nil when there is no per-session limit or no session.
+func (e *EchoMCP) sessionToolCallSlots(ctx context.Context) chan struct{} {
+	limit := e.config.MaxConcurrentToolCallsPerSession
+	if limit <= 0 {
+		return nil
+	}
+
+	sessionID := transport.SessionIDFromContext(ctx)
+	if sessionID == "" {
+		return nil
+	}
+
+	e.slotsMu.Lock()
+	defer e.slotsMu.Unlock()
+
+	if e.sessionSlots == nil {
+		e.sessionSlots = make(map[string]chan struct{})
+	}
+
+	slots, exists := e.sessionSlots[sessionID]
+	if !exists {
+		slots = make(chan struct{}, limit)
+		e.sessionSlots[sessionID] = slots
+	}
+	return slots
+}
+
+// dropSessionToolCallSlots discards the tool call slots of a closed session
+func (e *EchoMCP) dropSessionToolCallSlots(sessionID string) {
+	e.slotsMu.Lock()
+	defer e.slotsMu.Unlock()
+	delete(e.sessionSlots, sessionID)
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/transport"
+)
+
+// forwardedHeaders lists the inbound headers copied to tool calls when ForwardCookies is enabled
+var forwardedHeaders = []string{"Cookie", "X-CSRF-Token"}
+
+// applyCookies attaches forwarded inbound cookies and the session cookie jar to a tool call request
+func (e *EchoMCP) applyCookies(ctx context.Context, req *http.Request) {
+	if e.config.ForwardCookies {
+		if inbound := transport.RequestFromContext(ctx); inbound != nil {
+			for _, header := range forwardedHeaders {
+				for _, value := range inbound.Header.Values(header) {
+					req.Header.Add(header, value)
+				}
+			}
+		}
+	}
+
+	jar := e.cookieJar(ctx)
+	if jar == nil {
+		return
+	}
+
+	for _, cookie := range jar.Cookies(cookieURL(req)) {
+		req.AddCookie(cookie)
+	}
+}
+
+// storeCookies saves cookies set by a tool call response into the session cookie jar
+func (e *EchoMCP) storeCookies(ctx context.Context, req *http.Request, resp *http.Response) {
+	jar := e.cookieJar(ctx)
+	if jar == nil {
+		return
+	}
+
+	if cookies := resp.Cookies(); len(cookies) > 0 {
+		jar.SetCookies(cookieURL(req), cookies)
+	}
+}
+
+// cookieJar returns the cookie jar for the MCP session carried by ctx, creating it if needed.
+// It returns nil when the cookie jar is disabled or the call is not bound to a session.
+func (e *EchoMCP) cookieJar(ctx context.Context) http.CookieJar {
+	if !e.config.EnableCookieJar {
+		return nil
+	}
+
+	sessionID := transport.SessionIDFromContext(ctx)
+	if sessionID == "" {
+		return nil
+	}
+
+	e.cookieJarsMu.Lock()
+	defer e.cookieJarsMu.Unlock()
+
+	if jar, exists := e.cookieJars[sessionID]; exists {
+		return jar
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil
+	}
+	e.cookieJars[sessionID] = jar
+
+	return jar
+}
+
+// dropCookieJar discards the cookie jar of an expired or terminated MCP session
+func (e *EchoMCP) dropCookieJar(sessionID string) {
+	e.cookieJarsMu.Lock()
+	defer e.cookieJarsMu.Unlock()
+	delete(e.cookieJars, sessionID)
+}
+
+// cookieURL returns the absolute URL used to scope cookies for an in-process request
+func cookieURL(req *http.Request) *url.URL {
+	u := *req.URL
+	u.Scheme = "http"
+	u.Host = req.Host
+	return &u
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// corsAllowMethods lists the methods browser clients may use on the MCP endpoint
+	corsAllowMethods = "GET, POST, DELETE, OPTIONS"
+
+	// corsAllowHeaders lists the request headers browser clients may send to the MCP endpoint
+	corsAllowHeaders = "Content-Type, Accept, Authorization, Mcp-Session-Id, Mcp-Protocol-Version, " + DryRunHeader
+)
+
+// corsMiddleware adds CORS headers to MCP endpoint responses for the origins in
+// Config.CORSOrigins ("*" allows any origin). Requests from other origins are served
+// without CORS headers, so browsers reject their responses.
+func (e *EchoMCP) corsMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		origin := c.Request().Header.Get(echo.HeaderOrigin)
+		if origin == "" || !e.isAllowedOrigin(origin) {
+			return next(c)
+		}
+
+		header := c.Response().Header()
+		header.Add(echo.HeaderVary, echo.HeaderOrigin)
+		header.Set(echo.HeaderAccessControlAllowOrigin, origin)
+		header.Set(echo.HeaderAccessControlAllowMethods, corsAllowMethods)
+		header.Set(echo.HeaderAccessControlAllowHeaders, corsAllowHeaders)
+		// Browsers hide response headers from scripts unless they are exposed
+		header.Set(echo.HeaderAccessControlExposeHeaders, "Mcp-Session-Id")
+
+		return next(c)
+	}
+}
+
+// handlePreflight answers CORS preflight requests to the MCP endpoint
+func (e *EchoMCP) handlePreflight(c echo.Context) error {
+	return c.NoContent(http.StatusNoContent)
+}
+
+// isAllowedOrigin reports whether origin is listed in Config.CORSOrigins
+func (e *EchoMCP) isAllowedOrigin(origin string) bool {
+	return slices.Contains(e.config.CORSOrigins, "*") || slices.Contains(e.config.CORSOrigins, origin)
+}
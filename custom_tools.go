@@ -0,0 +1,114 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+)
+
+// ToolHandler executes a custom tool registered with RegisterTool.
+type ToolHandler func(params map[string]any) (any, error)
+
+// customTool is a tool registered with RegisterTool that is not backed by an Echo route
+type customTool struct {
+	handler ToolHandler
+	tool    types.Tool
+}
+
+// RegisterTool registers a custom MCP tool that is not tied to an Echo route, such as a
+// local computation or a compound action. Custom tools appear in tools/list alongside
+// the route tools and tools/call dispatches to handler.
+//
+// It returns an error if the tool name is empty or already used by another tool. A route
+// tool with the same name that only appears at Mount is not exposed, with a warning.
+//
+// Example:
+//
+//	err := mcp.RegisterTool(types.Tool{
+//		Name:        "sum",
+//		Description: "Add two numbers",
+//		InputSchema: map[string]any{
+//			"type": "object",
+//			"properties": map[string]any{
+//				"a": map[string]any{"type": "number"},
+//				"b": map[string]any{"type": "number"},
+//			},
+//		},
+//	}, func(params map[string]any) (any, error) {
+//		a, _ := params["a"].(float64)
+//		b, _ := params["b"].(float64)
+//		return a + b, nil
+//	})
+func (e *EchoMCP) RegisterTool(tool types.Tool, handler ToolHandler) error {
+	if tool.Name == "" {
+		return errors.New("tool name is required")
+	}
+	if handler == nil {
+		return fmt.Errorf("tool '%s' has no handler", tool.Name)
+	}
+	if tool.InputSchema == nil {
+		tool.InputSchema = map[string]any{
+			"type":       "object",
+			"properties": map[string]any{},
+		}
+	}
+
+	e.customToolsMu.Lock()
+	defer e.customToolsMu.Unlock()
+
+	if _, exists := e.customTools[tool.Name]; exists {
+		return fmt.Errorf("tool '%s' is already registered", tool.Name)
+	}
+	e.toolsMu.RLock()
+	_, conflicts := e.operations[tool.Name]
+	e.toolsMu.RUnlock()
+	if conflicts {
+		return fmt.Errorf("tool '%s' conflicts with a route tool", tool.Name)
+	}
+
+	e.customTools[tool.Name] = customTool{tool: tool, handler: handler}
+	e.customToolOrder = append(e.customToolOrder, tool.Name)
+
+	return nil
+}
+
+// listCustomTools returns the custom tools in registration order
+func (e *EchoMCP) listCustomTools() []types.Tool {
+	e.customToolsMu.RLock()
+	defer e.customToolsMu.RUnlock()
+
+	tools := make([]types.Tool, 0, len(e.customToolOrder))
+	for _, name := range e.customToolOrder {
+		tools = append(tools, e.customTools[name].tool)
+	}
+	return tools
+}
+
+// appendCustomTools appends the custom tools to the route tools. A route tool with the name of a
+// custom tool, such as one registered before Mount, is dropped with a warning: calls to that
+// name always reach the custom tool.
+func (e *EchoMCP) appendCustomTools(tools []types.Tool, operations map[string]types.Operation, owners map[string]*echoInstance) []types.Tool {
+	customTools := e.listCustomTools()
+	for _, custom := range customTools {
+		if _, conflicts := operations[custom.Name]; !conflicts {
+			continue
+		}
+
+		e.addWarning(fmt.Sprintf("custom tool '%s' conflicts with a route tool, which is not exposed", custom.Name))
+		tools = slices.DeleteFunc(tools, func(tool types.Tool) bool { return tool.Name == custom.Name })
+		delete(operations, custom.Name)
+		delete(owners, custom.Name)
+	}
+	return append(tools, customTools...)
+}
+
+// customToolHandler returns the handler of a custom tool, if registered
+func (e *EchoMCP) customToolHandler(name string) (ToolHandler, bool) {
+	e.customToolsMu.RLock()
+	defer e.customToolsMu.RUnlock()
+
+	custom, exists := e.customTools[name]
+	return custom.handler, exists
+}
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// redactedValue replaces the value of sensitive headers in debug output
+const redactedValue = "[REDACTED]"
+
+// redactedHeaders are the headers whose values are never logged, on top of the headers of the
+// apiKey security schemes of the swagger spec
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "X-CSRF-Token"}
+
+// ProxyExchange describes an upstream request built for a tool call and its response.
+// It is logged when Config.DebugProxy is enabled and attached to failed calls: to the error data,
+// or to the result of calls answered with a non-2xx status.
+type ProxyExchange struct {
+	Headers      map[string]string `json:"headers"`
+	Method       string            `json:"method"`
+	URL          string            `json:"url"`
+	Body         string            `json:"body,omitempty"`
+	Status       int               `json:"status,omitempty"`
+	ResponseSize int               `json:"responseSize,omitempty"`
+}
+
+// withExchange returns a context recording in exchange the last request logged by Config.DebugProxy
+func withExchange(ctx context.Context, exchange *ProxyExchange) context.Context {
+	return context.WithValue(ctx, exchangeKey, exchange)
+}
+
+// proxyError is returned by the debug round tripper when the upstream request fails
+type proxyError struct {
+	err      error
+	exchange ProxyExchange
+}
+
+func (e *proxyError) Error() string {
+	return e.err.Error()
+}
+
+func (e *proxyError) Unwrap() error {
+	return e.err
+}
+
+// inProcessTransport is an http.RoundTripper serving requests through an Echo instance
+type inProcessTransport struct {
+	target http.Handler
+}
+
+// RoundTrip implements http.RoundTripper
+func (t inProcessTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return serveInProcess(req.Context(), t.target, req)
+}
+
+// debugTransport is an http.RoundTripper logging every exchange it forwards to next, hiding
+// the values of the sensitive headers
+type debugTransport struct {
+	next      http.RoundTripper
+	sensitive []string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	exchange := ProxyExchange{
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Headers: redactHeaders(req.Header, t.sensitive),
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		exchange.Body = string(body)
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	// Keep the exchange for the error data of the tool call, updated once the response is in
+	recorded, _ := req.Context().Value(exchangeKey).(*ProxyExchange)
+	if recorded != nil {
+		*recorded = exchange
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		log.WithField("request", exchange).WithError(err).Warn("[MCP] Proxied request failed")
+		return nil, &proxyError{err: err, exchange: exchange}
+	}
+
+	// Buffer the response to report its size without consuming it for the caller
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, &proxyError{err: err, exchange: exchange}
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	exchange.Status = resp.StatusCode
+	exchange.ResponseSize = len(body)
+	if recorded != nil {
+		*recorded = exchange
+	}
+	log.WithField("request", exchange).Info("[MCP] Proxied request")
+
+	return resp, nil
+}
+
+// redactHeaders flattens headers for logging, hiding the values of the sensitive ones
+func redactHeaders(header http.Header, sensitive []string) map[string]string {
+	headers := make(map[string]string, len(header))
+	for key, values := range header {
+		if len(values) == 0 {
+			continue
+		}
+		headers[key] = values[0]
+		if slices.ContainsFunc(sensitive, func(name string) bool { return strings.EqualFold(name, key) }) {
+			headers[key] = redactedValue
+		}
+	}
+	return headers
+}
+
+// sensitiveHeaders returns the headers redacted from debug output: redactedHeaders and the
+// headers of the apiKey security schemes of the swagger spec
+func (e *EchoMCP) sensitiveHeaders() []string {
+	if e.swaggerSpec == nil {
+		return redactedHeaders
+	}
+	return slices.Concat(redactedHeaders, e.swaggerSpec.GetAPIKeyHeaders())
+}
+
+// roundTripper returns the transport dispatching requests in-process to target,
+// wrapped to log exchanges when Config.DebugProxy is enabled
+func (e *EchoMCP) roundTripper(target http.Handler) http.RoundTripper {
+	var rt http.RoundTripper = inProcessTransport{target: target}
+	if e.config.DebugProxy {
+		rt = debugTransport{next: rt, sensitive: e.sensitiveHeaders()}
+	}
+	return rt
+}
+
+// httpClient returns the client set with SetHTTPClient or Config.HTTPClient, applying
+// Config.RedirectPolicy (unless the client checks redirects itself and no policy is set) and
+// wrapped to log exchanges when Config.DebugProxy is enabled. It returns nil when calls are
+// dispatched in-process.
+func (e *EchoMCP) httpClient() *http.Client {
+	e.clientMu.RLock()
+	client := e.client
+	e.clientMu.RUnlock()
+
+	if client == nil {
+		client = e.config.HTTPClient
+	}
+	if client == nil {
+		return nil
+	}
+
+	copied := *client
+	if copied.CheckRedirect == nil || e.config.RedirectPolicy != "" {
+		copied.CheckRedirect = e.checkRedirect
+	}
+	if e.config.DebugProxy {
+		next := copied.Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		copied.Transport = debugTransport{next: next, sensitive: e.sensitiveHeaders()}
+	}
+	return &copied
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/transport"
+)
+
+// DryRunHeader lets an MCP client override Config.DryRun for its requests ("true" or "false").
+const DryRunHeader = "Mcp-Dry-Run"
+
+// DryRunPreview describes the request a tool call would have sent in dry-run mode.
+type DryRunPreview struct {
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    any               `json:"body,omitempty"`
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	DryRun  bool              `json:"dryRun"`
+}
+
+// String returns the preview as JSON for text tool content
+func (p *DryRunPreview) String() string {
+	data, err := sonic.MarshalString(p)
+	if err != nil {
+		return p.Method + " " + p.URL
+	}
+	return data
+}
+
+// isDryRun reports whether tool calls in ctx should only be previewed.
+// The DryRunHeader of the inbound MCP request takes precedence over Config.DryRun.
+func (e *EchoMCP) isDryRun(ctx context.Context) bool {
+	if req := transport.RequestFromContext(ctx); req != nil {
+		if dryRun, err := strconv.ParseBool(req.Header.Get(DryRunHeader)); err == nil {
+			return dryRun
+		}
+	}
+	return e.config.DryRun
+}
+
+// isSafeMethod reports whether method only reads data and always executes in dry-run mode
+func isSafeMethod(method string) bool {
+	return strings.EqualFold(method, http.MethodGet) || strings.EqualFold(method, http.MethodHead)
+}
+
+// newDryRunPreview builds the preview of req, resolving relative URLs against baseURL and
+// redacting the sensitive headers
+func newDryRunPreview(req *http.Request, baseURL string, body []byte, sensitive []string) *DryRunPreview {
+	requestURL := req.URL.String()
+	if !req.URL.IsAbs() && baseURL != "" {
+		requestURL = strings.TrimSuffix(baseURL, "/") + requestURL
+	}
+
+	// Credentials never reach the model: sensitive headers are redacted as in debug output
+	preview := &DryRunPreview{
+		DryRun:  true,
+		Method:  req.Method,
+		URL:     requestURL,
+		Headers: redactHeaders(req.Header, sensitive),
+	}
+
+	if len(body) > 0 {
+		var parsed any
+		if err := sonic.Unmarshal(body, &parsed); err == nil {
+			preview.Body = parsed
+		} else {
+			preview.Body = string(body)
+		}
+	}
+
+	return preview
+}
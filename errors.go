@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+)
+
+// TimeoutErrorCode is set as "code" in the error data of tool calls that exceeded their deadline
+const TimeoutErrorCode = "timeout"
+
+// ToolError is an error that custom tools and handlers can return to control the
+// JSON-RPC error code, message and data sent to the client. Any other error is
+// reported as an internal error.
+type ToolError struct {
+	Data    any
+	Message string
+	Code    int
+}
+
+// Error implements the error interface
+func (e *ToolError) Error() string {
+	return e.Message
+}
+
+// InvalidParams returns a ToolError reporting invalid arguments, with optional data
+// describing the problem (e.g. the offending fields).
+func InvalidParams(message string, data any) *ToolError {
+	return &ToolError{
+		Code:    types.ErrCodeInvalidParams,
+		Message: message,
+		Data:    data,
+	}
+}
+
+// NotFound returns a ToolError reporting that the requested resource does not exist.
+func NotFound(message string) *ToolError {
+	return &ToolError{
+		Code:    types.ErrCodeNotFound,
+		Message: message,
+	}
+}
+
+// Internal returns a ToolError reporting an internal failure, with optional data.
+func Internal(message string, data any) *ToolError {
+	return &ToolError{
+		Code:    types.ErrCodeInternalError,
+		Message: message,
+		Data:    data,
+	}
+}
+
+// toMCPError converts a ToolError or an exceeded deadline anywhere in err's chain
+// into the MCPError sent to the client, leaving other errors unchanged. Requests
+// logged by Config.DebugProxy are attached to the error data as "request".
+func toMCPError(err error) error {
+	var mcpErr *types.MCPError
+	var toolErr *ToolError
+	switch {
+	case errors.As(err, &toolErr):
+		mcpErr = types.NewMCPError(toolErr.Code, toolErr.Message, toolErr.Data)
+	case errors.Is(err, context.DeadlineExceeded):
+		mcpErr = types.NewMCPError(types.ErrCodeInternalError, err.Error(), map[string]any{"code": TimeoutErrorCode})
+	}
+
+	var proxyErr *proxyError
+	if !errors.As(err, &proxyErr) {
+		if mcpErr == nil {
+			return err
+		}
+		return mcpErr
+	}
+
+	if mcpErr == nil {
+		mcpErr = types.NewMCPError(types.ErrCodeInternalError, err.Error(), nil)
+	}
+	switch data := mcpErr.Data.(type) {
+	case nil:
+		mcpErr.Data = map[string]any{"request": proxyErr.exchange}
+	case map[string]any:
+		data["request"] = proxyErr.exchange
+	}
+
+	return mcpErr
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+)
+
+// RouteOption configures the MCP tool generated for a route registered with Handle.
+type RouteOption func(info *types.RegisteredSchemaInfo)
+
+// WithQuery declares the query parameters of the route, e.g. WithQuery(UserQuery{}).
+func WithQuery(query any) RouteOption {
+	return func(info *types.RegisteredSchemaInfo) {
+		info.QuerySchema = query
+	}
+}
+
+// WithBody declares the request body of the route, e.g. WithBody(CreateUserRequest{}).
+func WithBody(body any) RouteOption {
+	return func(info *types.RegisteredSchemaInfo) {
+		info.BodySchema = body
+	}
+}
+
+// WithoutBody declares that the route takes no request body, so its tool has no generic "body" argument.
+func WithoutBody() RouteOption {
+	return func(info *types.RegisteredSchemaInfo) {
+		noBody := false
+		info.GenericBody = &noBody
+	}
+}
+
+// WithRouteDescription sets the tool description, taking precedence over swagger and the description template.
+func WithRouteDescription(description string) RouteOption {
+	return func(info *types.RegisteredSchemaInfo) {
+		info.Description = description
+	}
+}
+
+// WithTags sets the tags of the route, available to description templates when swagger declares none.
+func WithTags(tags ...string) RouteOption {
+	return func(info *types.RegisteredSchemaInfo) {
+		info.Tags = append(info.Tags, tags...)
+	}
+}
+
+// Handle registers a route on the Echo instance and records its MCP schema in one call,
+// so the bind targets of the handler are declared next to the route itself. This is the
+// recommended way to build MCP-aware routes without swagger; routes registered with
+// e.GET and RegisterSchema keep working. Settings registered for the route beforehand, such
+// as static parameters, are kept.
+//
+// Example:
+//
+//	mcp.Handle(http.MethodPost, "/users", createUser,
+//		server.WithBody(CreateUserRequest{}),
+//		server.WithRouteDescription("Create a user"),
+//		server.WithTags("users"),
+//	)
+func (e *EchoMCP) Handle(method, path string, handler echo.HandlerFunc, opts ...RouteOption) *echo.Route {
+	route := e.echo.Add(method, path, handler)
+
+	// Options apply on top of the settings already registered for the route
+	e.updateSchema(method, path, func(info *types.RegisteredSchemaInfo) {
+		for _, opt := range opts {
+			opt(info)
+		}
+	})
+
+	e.refreshIfMounted()
+
+	return route
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultHealthCheckPath is probed by HealthCheck when Config.HealthCheckPath is empty
+	defaultHealthCheckPath = "/health"
+
+	// healthCheckTimeout bounds how long HealthCheck waits for the upstream server
+	healthCheckTimeout = 5 * time.Second
+)
+
+// HealthCheck probes the upstream server with a GET request to Config.HealthCheckPath
+// (default "/health") and reports whether it answered with a 2xx status. The request
+// goes to Config.BaseURL through the custom HTTP client when one is set, and is otherwise
+// served in-process by the Echo instance, the same way tool calls are executed.
+//
+// Example:
+//
+//	if ok, err := mcp.HealthCheck(); !ok {
+//		log.Fatalf("upstream is not healthy: %v", err)
+//	}
+func (e *EchoMCP) HealthCheck() (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+
+	path := e.config.HealthCheckPath
+	if path == "" {
+		path = defaultHealthCheckPath
+	}
+
+	var resp *http.Response
+	var err error
+	if client := e.httpClient(); client != nil {
+		req, reqErr := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(e.baseURL, "/")+path, http.NoBody)
+		if reqErr != nil {
+			return false, fmt.Errorf("failed to create health check request: %w", reqErr)
+		}
+		resp, err = client.Do(req)
+	} else {
+		req := httptest.NewRequestWithContext(ctx, http.MethodGet, path, http.NoBody)
+		resp, err = e.roundTripper(e.echo).RoundTrip(req)
+	}
+	if err != nil {
+		return false, fmt.Errorf("health check request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return false, fmt.Errorf("health check %s returned status %d", path, resp.StatusCode)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/transport"
+)
+
+const (
+	// idempotencyKeyArgument lets a client mark retries of the same tool call
+	idempotencyKeyArgument = "_idempotencyKey"
+	// idempotencyKeyHeader carries the key to upstreams with Config.ForwardIdempotencyKey
+	idempotencyKeyHeader = "Idempotency-Key"
+	// defaultIdempotencyMaxEntries bounds the replay store when Config.IdempotencyMaxEntries is not set
+	defaultIdempotencyMaxEntries = 1000
+)
+
+// idempotencyStore remembers the results of tool calls sent with an idempotency key, least
+// recently used first in order
+type idempotencyStore struct {
+	entries map[string]*list.Element
+	order   *list.List
+	mu      sync.Mutex
+}
+
+// idempotencyEntry is the result of a tool call made with an idempotency key. done is closed
+// once the first call completes or is dropped, so replays arriving in the meantime wait for it.
+type idempotencyEntry struct {
+	expires   time.Time
+	result    any
+	done      chan struct{}
+	key       string
+	completed bool
+}
+
+// popIdempotencyKey removes the _idempotencyKey argument from parameters and returns its value
+func popIdempotencyKey(parameters map[string]any) (map[string]any, string) {
+	value, exists := parameters[idempotencyKeyArgument]
+	if !exists {
+		return parameters, ""
+	}
+
+	parameters = maps.Clone(parameters)
+	delete(parameters, idempotencyKeyArgument)
+
+	if value == nil {
+		return parameters, ""
+	}
+	return parameters, fmt.Sprintf("%v", value)
+}
+
+// idempotencyStoreKey returns the replay store key of a tool call, or "" when calls with this
+// key are not replayed: replays are disabled or the key is forwarded to the upstream instead.
+// Keys are scoped to the MCP session and the tool so clients never see each other's results.
+func (e *EchoMCP) idempotencyStoreKey(ctx context.Context, operationID, idempotencyKey string) string {
+	if idempotencyKey == "" || e.config.IdempotencyTTL <= 0 || e.config.ForwardIdempotencyKey {
+		return ""
+	}
+	return transport.SessionIDFromContext(ctx) + " " + operationID + " " + idempotencyKey
+}
+
+// replayIdempotent runs execute once per key: repeated calls get the result of the first one,
+// waiting for it while it is in flight. Calls that fail release the key so a retry runs again.
+func (e *EchoMCP) replayIdempotent(ctx context.Context, key string, execute func() (any, error)) (any, error) {
+	for {
+		entry, claimed := e.claimIdempotencyKey(key)
+		if claimed {
+			result, err := execute()
+			e.settleIdempotencyKey(entry, result, err)
+			return result, err
+		}
+
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		// A failed or evicted call leaves no result: claim the key again
+		if entry.completed {
+			return entry.result, nil
+		}
+	}
+}
+
+// claimIdempotencyKey returns the live entry stored under key, or stores a pending entry,
+// evicting the least recently used ones beyond Config.IdempotencyMaxEntries, and reports
+// that the caller claimed it
+func (e *EchoMCP) claimIdempotencyKey(key string) (*idempotencyEntry, bool) {
+	store := &e.idempotency
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if store.entries == nil {
+		store.entries = make(map[string]*list.Element)
+		store.order = list.New()
+	}
+
+	if element, exists := store.entries[key]; exists {
+		entry := element.Value.(*idempotencyEntry)
+		if !entry.completed || time.Now().Before(entry.expires) {
+			store.order.MoveToFront(element)
+			return entry, false
+		}
+		store.removeLocked(element)
+	}
+
+	maxEntries := e.config.IdempotencyMaxEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultIdempotencyMaxEntries
+	}
+	for store.order.Len() >= maxEntries {
+		store.removeLocked(store.order.Back())
+	}
+
+	entry := &idempotencyEntry{key: key, done: make(chan struct{})}
+	store.entries[key] = store.order.PushFront(entry)
+	return entry, true
+}
+
+// settleIdempotencyKey records the outcome of a claimed call and wakes up the replays waiting
+// for it. Results are kept for Config.IdempotencyTTL; errors release the key.
+func (e *EchoMCP) settleIdempotencyKey(entry *idempotencyEntry, result any, err error) {
+	store := &e.idempotency
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	element, exists := store.entries[entry.key]
+	if !exists || element.Value != entry {
+		// Evicted while in flight; its waiters were already released
+		return
+	}
+
+	if err != nil {
+		store.removeLocked(element)
+		return
+	}
+
+	entry.result = result
+	entry.expires = time.Now().Add(e.config.IdempotencyTTL)
+	entry.completed = true
+	close(entry.done)
+}
+
+// removeLocked drops an entry, releasing the replays waiting for it when it is still in flight
+func (s *idempotencyStore) removeLocked(element *list.Element) {
+	entry := element.Value.(*idempotencyEntry)
+	s.order.Remove(element)
+	delete(s.entries, entry.key)
+	if !entry.completed {
+		close(entry.done)
+	}
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/convert"
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+)
+
+// echoInstance is an additional Echo instance whose routes are exposed as MCP tools
+type echoInstance struct {
+	echo             *echo.Echo
+	baseURL          string
+	prefix           string
+	excludeEndpoints []string
+}
+
+// AddEchoInstance registers the routes of another Echo instance as MCP tools.
+// Tool names are prefixed with prefix (e.g. "admin" turns GET_users into admin_GET_users),
+// and tool calls are dispatched to that instance using baseURL as the request host.
+// Tools whose name is already taken by the main instance or an earlier instance, as happens
+// with an empty or repeated prefix, are not exposed and reported by Warnings.
+// The include and exclude filters of the EchoMCP apply to every instance; excludeEndpoints
+// adds filters that only apply to this instance.
+//
+// The swagger spec describes the main instance only: its schemas, tags and extensions are
+// never applied to the routes of another instance. Schemas and settings registered with
+// RegisterSchema, Handle and the other per-route methods are keyed by method and path and
+// apply to the routes of every instance, which is how instance routes are described.
+//
+// Example:
+//
+//	public := echo.New()
+//	admin := echo.New()
+//	mcp := server.New(public)
+//	mcp.AddEchoInstance(admin, "http://localhost:9090", "admin", "/internal/*")
+//	mcp.Mount("/mcp")
+func (e *EchoMCP) AddEchoInstance(instance *echo.Echo, baseURL, prefix string, excludeEndpoints ...string) {
+	e.instancesMu.Lock()
+	defer e.instancesMu.Unlock()
+
+	e.instances = append(e.instances, &echoInstance{
+		echo:             instance,
+		baseURL:          baseURL,
+		prefix:           prefix,
+		excludeEndpoints: excludeEndpoints,
+	})
+}
+
+// convertInstances converts the routes of every additional Echo instance into prefixed tools.
+// Tools whose name is already used by the main instance (in taken) or an earlier instance,
+// e.g. with an empty or repeated prefix, are skipped with a warning.
+func (e *EchoMCP) convertInstances(registeredSchemas map[string]types.RegisteredSchemaInfo, taken map[string]types.Operation) ([]types.Tool, map[string]types.Operation, map[string]*echoInstance) {
+	e.instancesMu.RLock()
+	instances := make([]*echoInstance, len(e.instances))
+	copy(instances, e.instances)
+	e.instancesMu.RUnlock()
+
+	var tools []types.Tool
+	operations := make(map[string]types.Operation)
+	owners := make(map[string]*echoInstance)
+
+	for _, instance := range instances {
+		var routes []*echo.Route
+		instanceRoutes := instance.echo.Routes()
+		getPaths := getRoutePaths(instanceRoutes)
+		for _, route := range instanceRoutes {
+			// The swagger spec describes the main instance only
+			if e.isRouteExposed(route, getPaths, nil) && !e.matchesAnyEndpoint(route.Path, instance.excludeEndpoints) {
+				routes = append(routes, route)
+			}
+		}
+
+		instanceTools, instanceOperations := convert.ConvertRoutesToToolsWithOptions(routes, registeredSchemas, nil, e.convertOptions())
+		for _, tool := range instanceTools {
+			operationID := tool.Name
+			tool.Name = prefixToolName(instance.prefix, operationID)
+			_, takenByMain := taken[tool.Name]
+			_, takenByInstance := operations[tool.Name]
+			if takenByMain || takenByInstance {
+				e.addWarning(fmt.Sprintf("tool '%s' of the Echo instance with prefix '%s' conflicts with another tool and is not exposed", tool.Name, instance.prefix))
+				continue
+			}
+			tools = append(tools, tool)
+			operations[tool.Name] = instanceOperations[operationID]
+			owners[tool.Name] = instance
+		}
+	}
+
+	return tools, operations, owners
+}
+
+// matchesAnyEndpoint checks if a route path matches any of the endpoint patterns
+func (e *EchoMCP) matchesAnyEndpoint(routePath string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if e.matchesEndpoint(routePath, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// prefixToolName joins an instance prefix and an operation ID
+func prefixToolName(prefix, operationID string) string {
+	if prefix == "" {
+		return operationID
+	}
+	return prefix + "_" + operationID
+}
+
+// requestTarget returns the in-process request target, qualified with the scheme and host of baseURL if set
+func requestTarget(baseURL, requestPath string) string {
+	if baseURL == "" {
+		return requestPath
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return requestPath
+	}
+
+	return u.Scheme + "://" + u.Host + requestPath
+}
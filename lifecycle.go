@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Shutdown stops accepting tool calls and the route watcher, waits for in-flight calls
+// to finish and then closes the transport, clearing its sessions. If ctx expires before
+// the calls are drained, Shutdown returns the context error and leaves the transport open.
+//
+// Mount registers Shutdown with the Echo server, so e.Shutdown(ctx) also drains the
+// MCP server.
+func (e *EchoMCP) Shutdown(ctx context.Context) error {
+	e.lifecycleMu.Lock()
+	e.shuttingDown = true
+	e.lifecycleMu.Unlock()
+
+	if e.stopRouteWatch != nil {
+		e.stopRouteWatch()
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		e.calls.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if e.transport == nil {
+		return nil
+	}
+	return e.transport.Close(ctx)
+}
+
+// beginCall registers an in-flight tool call, or reports false once Shutdown has started
+func (e *EchoMCP) beginCall() bool {
+	e.lifecycleMu.Lock()
+	defer e.lifecycleMu.Unlock()
+
+	if e.shuttingDown {
+		return false
+	}
+	e.calls.Add(1)
+	return true
+}
+
+// Unmount takes the MCP server off its endpoint. Echo cannot remove routes, so the routes
+// registered by Mount answer 410 Gone from then on; the route watcher stops, new tool calls
+// are rejected and the transport sessions are closed. Calls already running are not waited
+// for (see Shutdown). An unmounted server cannot be mounted again; use Clone to serve the
+// same tools at another path.
+func (e *EchoMCP) Unmount() error {
+	e.lifecycleMu.Lock()
+	if e.transport == nil || e.unmounted {
+		e.lifecycleMu.Unlock()
+		return errors.New("MCP server is not mounted")
+	}
+	e.unmounted = true
+	e.shuttingDown = true
+	e.lifecycleMu.Unlock()
+
+	if e.stopRouteWatch != nil {
+		e.stopRouteWatch()
+	}
+
+	return e.transport.Close(context.Background())
+}
+
+// isUnmounted reports whether Unmount has been called
+func (e *EchoMCP) isUnmounted() bool {
+	e.lifecycleMu.Lock()
+	defer e.lifecycleMu.Unlock()
+	return e.unmounted
+}
+
+// rejectUnmounted answers requests to the MCP routes with 410 Gone once the server is unmounted
+func (e *EchoMCP) rejectUnmounted(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if e.isUnmounted() {
+			return echo.NewHTTPError(http.StatusGone, "MCP server has been unmounted")
+		}
+		return next(c)
+	}
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/transport"
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+)
+
+// logLevels lists the MCP log levels (RFC 5424 severities) from least to most severe
+var logLevels = []string{"debug", "info", "notice", "warning", "error", "critical", "alert", "emergency"}
+
+const (
+	// defaultLogLevel is the minimum level sent to sessions that did not call logging/setLevel
+	defaultLogLevel = "info"
+	// toolErrorLogger names the logger of the tool call failures sent with LogToolErrors
+	toolErrorLogger = "tools"
+)
+
+// handleSetLevel handles logging/setLevel requests, storing the minimum log level of the session
+func (e *EchoMCP) handleSetLevel(ctx context.Context, params any) (any, error) {
+	paramMap, _ := params.(map[string]any)
+	level, _ := paramMap["level"].(string)
+	if !slices.Contains(logLevels, level) {
+		return nil, toMCPError(InvalidParams(fmt.Sprintf("unknown log level '%s'", level), map[string]any{"levels": logLevels}))
+	}
+
+	e.logLevelsMu.Lock()
+	defer e.logLevelsMu.Unlock()
+	if e.logLevels == nil {
+		e.logLevels = make(map[string]string)
+	}
+	e.logLevels[transport.SessionIDFromContext(ctx)] = level
+
+	return map[string]any{}, nil
+}
+
+// dropLogLevel discards the log level of an expired or terminated MCP session
+func (e *EchoMCP) dropLogLevel(sessionID string) {
+	e.logLevelsMu.Lock()
+	defer e.logLevelsMu.Unlock()
+	delete(e.logLevels, sessionID)
+}
+
+// wantsLog reports whether a session asked for log messages of the given level
+func (e *EchoMCP) wantsLog(sessionID, level string) bool {
+	e.logLevelsMu.Lock()
+	minimum, exists := e.logLevels[sessionID]
+	e.logLevelsMu.Unlock()
+	if !exists {
+		minimum = defaultLogLevel
+	}
+
+	return slices.Index(logLevels, level) >= slices.Index(logLevels, minimum)
+}
+
+// LogToClient sends a notifications/message log entry to every session whose level (set
+// with logging/setLevel, "info" by default) it meets. level is an MCP log level such as
+// "info" or "error", logger optionally names the source, and data is any JSON value.
+// Only transports able to push messages outside of a request deliver it: with the plain
+// HTTP transport the entry is dropped and a warning is recorded once. Before Mount, entries
+// are dropped silently.
+func (e *EchoMCP) LogToClient(level, logger string, data any) {
+	if !slices.Contains(logLevels, level) {
+		log.Warnf("[MCP] Unknown log level '%s', log entry dropped", level)
+		return
+	}
+
+	if e.transport == nil {
+		return
+	}
+
+	pusher, ok := e.transport.(transport.PushTransport)
+	if !ok || !pusher.SupportsStreaming() {
+		e.addWarning("the transport cannot push log notifications; LogToClient entries are dropped")
+		return
+	}
+
+	for _, sessionID := range pusher.Sessions() {
+		if !e.wantsLog(sessionID, level) {
+			continue
+		}
+		if err := pusher.Push(sessionID, logMessage(level, logger, data)); err != nil {
+			log.Debugf("[MCP] Failed to push log notification to session %s: %v", sessionID, err)
+		}
+	}
+}
+
+// logToSession sends a log entry to the client of the session handling ctx, on the response
+// stream of the current request when there is one and through a push transport otherwise
+func (e *EchoMCP) logToSession(ctx context.Context, level, logger string, data any) {
+	sessionID := transport.SessionIDFromContext(ctx)
+	if !e.wantsLog(sessionID, level) {
+		return
+	}
+
+	msg := logMessage(level, logger, data)
+	if notifier := transport.NotifierFromContext(ctx); notifier != nil {
+		_ = notifier(msg)
+		return
+	}
+	if pusher, ok := e.transport.(transport.PushTransport); ok && pusher.SupportsStreaming() {
+		_ = pusher.Push(sessionID, msg)
+	}
+}
+
+// logMessage builds a notifications/message log entry
+func logMessage(level, logger string, data any) *types.MCPMessage {
+	params := map[string]any{
+		"level": level,
+		"data":  data,
+	}
+	if logger != "" {
+		params["logger"] = logger
+	}
+
+	return &types.MCPMessage{
+		Jsonrpc: "2.0",
+		Method:  "notifications/message",
+		Params:  params,
+	}
+}
@@ -9,7 +9,9 @@ type InitializeResponse struct {
 }
 
 type Capabilities struct {
-	Tools map[string]any `json:"tools"`
+	Tools       map[string]any `json:"tools"`
+	Logging     map[string]any `json:"logging"`
+	Completions map[string]any `json:"completions"`
 }
 
 type ServerInfo struct {
@@ -18,7 +20,8 @@ type ServerInfo struct {
 }
 
 type ToolsListResponse struct {
-	Tools []types.Tool `json:"tools"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+	Tools      []types.Tool `json:"tools"`
 }
 
 type ToolCallRequest struct {
@@ -27,7 +30,8 @@ type ToolCallRequest struct {
 }
 
 type ToolCallResponse struct {
-	Content []Content `json:"content"`
+	StructuredContent any       `json:"structuredContent,omitempty"`
+	Content           []Content `json:"content"`
 }
 
 type Content struct {
@@ -0,0 +1,514 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/swagger"
+)
+
+// Option configures an EchoMCP instance created with NewWithOptions.
+type Option func(*Config)
+
+// WithConfig copies every field of config into the configuration being built.
+// A nil config is ignored.
+func WithConfig(config *Config) Option {
+	return func(c *Config) {
+		if config != nil {
+			*c = *config
+		}
+	}
+}
+
+// WithName sets the server name reported to MCP clients.
+func WithName(name string) Option {
+	return func(c *Config) {
+		c.Name = name
+	}
+}
+
+// WithVersion sets the server version reported to MCP clients.
+func WithVersion(version string) Option {
+	return func(c *Config) {
+		c.Version = version
+	}
+}
+
+// WithDescription sets the server description.
+func WithDescription(description string) Option {
+	return func(c *Config) {
+		c.Description = description
+	}
+}
+
+// WithBaseURL sets the base URL of the API.
+func WithBaseURL(u string) Option {
+	return func(c *Config) {
+		c.BaseURL = u
+	}
+}
+
+// WithSwaggerSchemas enables schema generation from swaggo annotations.
+func WithSwaggerSchemas() Option {
+	return func(c *Config) {
+		c.EnableSwaggerSchemas = true
+	}
+}
+
+// WithOpenAPISchema sets a raw OpenAPI schema (JSON or YAML) used for tool schemas.
+func WithOpenAPISchema(schema string) Option {
+	return func(c *Config) {
+		c.OpenAPISchema = schema
+	}
+}
+
+// WithIncludeEndpoints restricts MCP tools to the given endpoint paths.
+func WithIncludeEndpoints(paths ...string) Option {
+	return func(c *Config) {
+		c.IncludeEndpoints = append(c.IncludeEndpoints, paths...)
+	}
+}
+
+// WithExcludeEndpoints excludes the given endpoint paths from MCP tools.
+func WithExcludeEndpoints(paths ...string) Option {
+	return func(c *Config) {
+		c.ExcludeEndpoints = append(c.ExcludeEndpoints, paths...)
+	}
+}
+
+// WithIncludeTags restricts MCP tools to routes whose swagger operations have one of the given tags.
+func WithIncludeTags(tags ...string) Option {
+	return func(c *Config) {
+		c.IncludeTags = append(c.IncludeTags, tags...)
+	}
+}
+
+// WithExcludeTags excludes routes whose swagger operations have one of the given tags.
+func WithExcludeTags(tags ...string) Option {
+	return func(c *Config) {
+		c.ExcludeTags = append(c.ExcludeTags, tags...)
+	}
+}
+
+// WithDescribeAllResponses includes all response codes in tool descriptions.
+func WithDescribeAllResponses() Option {
+	return func(c *Config) {
+		c.DescribeAllResponses = true
+	}
+}
+
+// WithDescribeFullResponseSchema includes full response schemas in tool descriptions.
+func WithDescribeFullResponseSchema() Option {
+	return func(c *Config) {
+		c.DescribeFullResponseSchema = true
+	}
+}
+
+// WithSessionTTL sets how long an MCP session may stay idle before it expires.
+func WithSessionTTL(ttl time.Duration) Option {
+	return func(c *Config) {
+		c.SessionTTL = ttl
+	}
+}
+
+// WithForwardCookies copies inbound Cookie and X-CSRF-Token headers to tool calls.
+func WithForwardCookies() Option {
+	return func(c *Config) {
+		c.ForwardCookies = true
+	}
+}
+
+// WithCookieJar keeps a cookie jar per MCP session across tool calls.
+func WithCookieJar() Option {
+	return func(c *Config) {
+		c.EnableCookieJar = true
+	}
+}
+
+// WithRetry configures retries of transient upstream failures.
+func WithRetry(retry RetryConfig) Option {
+	return func(c *Config) {
+		c.Retry = retry
+	}
+}
+
+// WithToolsDebugEndpoint mounts GET {mountPath}/tools rendering the tools list as pretty JSON.
+func WithToolsDebugEndpoint() Option {
+	return func(c *Config) {
+		c.EnableToolsDebugEndpoint = true
+	}
+}
+
+// WithSkipCatchAllRoutes excludes catch-all routes (e.g. e.Static) from MCP tools.
+func WithSkipCatchAllRoutes() Option {
+	return func(c *Config) {
+		c.SkipCatchAllRoutes = true
+	}
+}
+
+// WithSkipHeadRoutes controls whether HEAD routes duplicating a GET route are dropped (default true).
+func WithSkipHeadRoutes(skip bool) Option {
+	return func(c *Config) {
+		c.SkipHeadRoutes = &skip
+	}
+}
+
+// WithExcludeHTTPMethods sets the HTTP methods never exposed as tools,
+// replacing the default OPTIONS, HEAD and TRACE. Pass no methods to expose every method.
+func WithExcludeHTTPMethods(methods ...string) Option {
+	return func(c *Config) {
+		c.ExcludeHTTPMethods = append([]string{}, methods...)
+	}
+}
+
+// WithOperationIDTransform post-processes every generated operation ID (tool name).
+func WithOperationIDTransform(transform func(id string) string) Option {
+	return func(c *Config) {
+		c.OperationIDTransform = transform
+	}
+}
+
+// WithRequireSecurityParameters marks credentials from swagger security schemes as required tool arguments.
+func WithRequireSecurityParameters() Option {
+	return func(c *Config) {
+		c.RequireSecurityParameters = true
+	}
+}
+
+// WithSkipDeprecated drops operations marked deprecated in the swagger spec instead of flagging them.
+func WithSkipDeprecated() Option {
+	return func(c *Config) {
+		c.SkipDeprecated = true
+	}
+}
+
+// WithToolsPageSize splits tools/list responses into pages of at most size tools. Clients
+// fetch the next page with the nextCursor of the previous one.
+func WithToolsPageSize(size int) Option {
+	return func(c *Config) {
+		c.ToolsPageSize = size
+	}
+}
+
+// WithExcludeDeprecated hides deprecated tools from tools/list while keeping them callable.
+func WithExcludeDeprecated() Option {
+	return func(c *Config) {
+		c.ExcludeDeprecated = true
+	}
+}
+
+// WithMaxResponseBodyBytes truncates tool responses larger than limit bytes (0 means unlimited).
+func WithMaxResponseBodyBytes(limit int) Option {
+	return func(c *Config) {
+		c.MaxResponseBodyBytes = limit
+	}
+}
+
+// WithPreferSwaggerOperationID names tools after their swagger operationId when it is present and unique.
+func WithPreferSwaggerOperationID() Option {
+	return func(c *Config) {
+		c.PreferSwaggerOperationID = true
+	}
+}
+
+// WithRequestHeaders sets static headers on every tool call; operation header parameters take precedence.
+func WithRequestHeaders(headers map[string]string) Option {
+	return func(c *Config) {
+		c.RequestHeaders = headers
+	}
+}
+
+// WithDescriptionTemplate renders descriptions of tools without a swagger summary from a text/template.
+// The template can use {{.Method}}, {{.Path}}, {{.Summary}}, {{.Tags}}, {{.OperationID}}, and {{.HandlerName}}.
+func WithDescriptionTemplate(tmpl string) Option {
+	return func(c *Config) {
+		c.DescriptionTemplate = tmpl
+	}
+}
+
+// WithDescriptionFormatter post-processes every tool description, including swagger summaries.
+func WithDescriptionFormatter(formatter func(route *echo.Route, op swagger.SwaggerOperation) string) Option {
+	return func(c *Config) {
+		c.DescriptionFormatter = formatter
+	}
+}
+
+// WithRequestTransform calls transform with every upstream request just before it is sent, after
+// headers and cookies are set, e.g. to sign it or inject a short-lived token. An error aborts
+// the tool call with an internal error (or the code of a returned ToolError).
+func WithRequestTransform(transform func(req *http.Request) error) Option {
+	return func(c *Config) {
+		c.RequestTransform = transform
+	}
+}
+
+// WithHTTPClient sends tool calls through client to the base URL instead of dispatching them in-process.
+// The caller is responsible for setting an appropriate Timeout on the client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Config) {
+		c.HTTPClient = client
+	}
+}
+
+// WithStrictSwagger makes Mount fail when a configured swagger spec cannot be loaded.
+func WithStrictSwagger() Option {
+	return func(c *Config) {
+		c.StrictSwagger = true
+	}
+}
+
+// WithProgressInterval sets how often progress notifications are sent for running tool calls (default 5s).
+func WithProgressInterval(interval time.Duration) Option {
+	return func(c *Config) {
+		c.ProgressInterval = interval
+	}
+}
+
+// WithDryRun previews non-GET tool calls instead of executing them.
+func WithDryRun() Option {
+	return func(c *Config) {
+		c.DryRun = true
+	}
+}
+
+// WithDebugProxy logs every upstream request built for a tool call (with credentials redacted)
+// and its response, and attaches the request to the error data of failed calls.
+func WithDebugProxy() Option {
+	return func(c *Config) {
+		c.DebugProxy = true
+	}
+}
+
+// WithHealthCheckPath sets the path probed by HealthCheck (default "/health").
+func WithHealthCheckPath(path string) Option {
+	return func(c *Config) {
+		c.HealthCheckPath = path
+	}
+}
+
+// WithStartupHealthCheck makes Mount run HealthCheck and fail if the upstream server is not healthy.
+func WithStartupHealthCheck() Option {
+	return func(c *Config) {
+		c.StartupHealthCheck = true
+	}
+}
+
+// WithCacheTTL caches the results of GET and HEAD tool calls for ttl. Clients can pass
+// "_noCache": true to skip the cache for a single call.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(c *Config) {
+		c.CacheTTL = ttl
+	}
+}
+
+// WithCacheMaxEntries limits how many results the tool call cache holds; the oldest are evicted first.
+func WithCacheMaxEntries(maxEntries int) Option {
+	return func(c *Config) {
+		c.CacheMaxEntries = maxEntries
+	}
+}
+
+// WithIdempotencyTTL remembers the results of tool calls carrying an "_idempotencyKey" argument
+// for ttl, per MCP session. A retry with the same key returns the first result instead of
+// calling the upstream again.
+func WithIdempotencyTTL(ttl time.Duration) Option {
+	return func(c *Config) {
+		c.IdempotencyTTL = ttl
+	}
+}
+
+// WithIdempotencyMaxEntries limits how many results are kept for idempotency keys (1000 by
+// default); the least recently used are evicted first.
+func WithIdempotencyMaxEntries(maxEntries int) Option {
+	return func(c *Config) {
+		c.IdempotencyMaxEntries = maxEntries
+	}
+}
+
+// WithForwardIdempotencyKey sends the "_idempotencyKey" argument of tool calls to the upstream
+// as an Idempotency-Key header, for upstreams that deduplicate requests themselves. Results are
+// then not replayed by the MCP server.
+func WithForwardIdempotencyKey() Option {
+	return func(c *Config) {
+		c.ForwardIdempotencyKey = true
+	}
+}
+
+// WithToolPrefix prepends prefix to every tool name (e.g. "billing_" lists GET_users as
+// billing_GET_users), so tools from several servers behind one gateway do not collide.
+// Characters not allowed in MCP tool names are replaced with underscores.
+func WithToolPrefix(prefix string) Option {
+	return func(c *Config) {
+		c.ToolPrefix = prefix
+	}
+}
+
+// WithMaxToolNameLength caps generated tool names (default 64); longer names are shortened
+// with a hash suffix that keeps them unique.
+func WithMaxToolNameLength(length int) Option {
+	return func(c *Config) {
+		c.MaxToolNameLength = length
+	}
+}
+
+// WithFlattenBodySchema lists request body properties at the top level of tool input schemas
+// instead of nesting them under a "body" property.
+func WithFlattenBodySchema() Option {
+	return func(c *Config) {
+		c.FlattenBodySchema = true
+	}
+}
+
+// WithToolNamePrefix adds prefix to every tool name generated from a route (e.g. "user_service_"
+// produces user_service_GET_users). Unlike WithToolPrefix, the prefix is part of the operation
+// name and custom tools are left unchanged.
+func WithToolNamePrefix(prefix string) Option {
+	return func(c *Config) {
+		c.ToolNamePrefix = prefix
+	}
+}
+
+// WithToolNameSuffix adds suffix to every tool name generated from a route (e.g. "_v2"
+// produces GET_users_v2).
+func WithToolNameSuffix(suffix string) Option {
+	return func(c *Config) {
+		c.ToolNameSuffix = suffix
+	}
+}
+
+// WithCORSOrigins allows browser-based MCP clients from the given origins ("*" for any) to
+// call the MCP endpoint, answering CORS preflight requests with 204 No Content.
+func WithCORSOrigins(origins ...string) Option {
+	return func(c *Config) {
+		c.CORSOrigins = origins
+	}
+}
+
+// WithRouteRefreshInterval rebuilds the tools list at the given interval after Mount, so
+// routes added or removed at runtime show up and clients are notified of the change.
+func WithRouteRefreshInterval(interval time.Duration) Option {
+	return func(c *Config) {
+		c.RouteRefreshInterval = interval
+	}
+}
+
+// WithNoGenericBody leaves out the generic "body" argument of POST, PUT and PATCH tools whose
+// route has no registered or swagger body schema. SetGenericBody overrides it per route.
+func WithNoGenericBody() Option {
+	return func(c *Config) {
+		c.NoGenericBody = true
+	}
+}
+
+// WithIncludeResponseHeaders returns the response headers matching one of the patterns with the
+// tool result, e.g. "X-Total-Count" or "X-RateLimit-*". Location is also returned on 201 and 3xx
+// responses. SetResponseHeaders overrides the list per route.
+func WithIncludeResponseHeaders(patterns ...string) Option {
+	return func(c *Config) {
+		c.IncludeResponseHeaders = patterns
+	}
+}
+
+// WithRedirectPolicy sets how tool calls sent through a custom HTTP client handle redirects:
+// RedirectFollow (the default), RedirectNone or RedirectSameHost. Redirects that are not followed
+// return their status and Location header flagged with "redirected": true.
+func WithRedirectPolicy(policy RedirectPolicy) Option {
+	return func(c *Config) {
+		c.RedirectPolicy = policy
+	}
+}
+
+// WithAllowDeleteBody sends the arguments of DELETE tools that are not path, query or header
+// parameters as a JSON body, like POST, and gives DELETE tools without a body schema the generic
+// "body" argument. DELETE routes with a swagger body parameter or a registered body schema send
+// their body without it.
+func WithAllowDeleteBody() Option {
+	return func(c *Config) {
+		c.AllowDeleteBody = true
+	}
+}
+
+// WithBasePath sets the path prefix of the API (e.g. "/api/v1"), overriding the swagger
+// basePath. Routes under it match the relative swagger paths, and requests sent to BaseURL
+// through a custom HTTP client are prefixed with it when the route path does not include it.
+func WithBasePath(basePath string) Option {
+	return func(c *Config) {
+		c.BasePath = basePath
+	}
+}
+
+// WithSwaggerSpecURL loads the swagger or OpenAPI spec served at specURL (e.g.
+// "http://localhost:8080/swagger/doc.json") instead of the swaggo registry, enabling
+// swagger schemas for projects that serve their spec rather than embedding it.
+func WithSwaggerSpecURL(specURL string) Option {
+	return func(c *Config) {
+		c.SwaggerSpecURL = specURL
+	}
+}
+
+// WithStrictSchemas sets "additionalProperties": false on generated input schemas (map-typed
+// fields stay open) and rejects tool calls with arguments the schema does not declare.
+func WithStrictSchemas() Option {
+	return func(c *Config) {
+		c.StrictSchemas = true
+	}
+}
+
+// WithSwaggerValidation checks the swagger spec with swagger.ValidateForMCP when tools are
+// generated and logs missing descriptions, undeclared path parameters and unresolvable refs.
+func WithSwaggerValidation() Option {
+	return func(c *Config) {
+		c.EnableSwaggerValidation = true
+	}
+}
+
+// WithNullableKeyword marks nullable swagger properties with the OpenAPI "nullable": true
+// keyword instead of a ["type", "null"] type union, for clients that only accept one type.
+func WithNullableKeyword() Option {
+	return func(c *Config) {
+		c.NullableKeyword = true
+	}
+}
+
+// WithToolErrorLogging sends a warning-level log notification to the client of a session
+// whenever one of its tool calls fails, in addition to the error response.
+func WithToolErrorLogging() Option {
+	return func(c *Config) {
+		c.LogToolErrors = true
+	}
+}
+
+// WithStripHTML reduces text/html responses (such as error pages served by a proxy) to their
+// visible text, so large HTML documents do not fill the model context with markup.
+func WithStripHTML() Option {
+	return func(c *Config) {
+		c.StripHTML = true
+	}
+}
+
+// WithMaxConcurrentToolCalls limits how many tool calls run at once across all sessions.
+// Calls beyond the limit wait up to the queue timeout (see WithToolCallQueueTimeout) for a
+// slot and then fail with a "server busy" error.
+func WithMaxConcurrentToolCalls(limit int) Option {
+	return func(c *Config) {
+		c.MaxConcurrentToolCalls = limit
+	}
+}
+
+// WithMaxConcurrentToolCallsPerSession limits how many tool calls each MCP session runs at once,
+// so one client cannot exhaust the upstream connection pools.
+func WithMaxConcurrentToolCallsPerSession(limit int) Option {
+	return func(c *Config) {
+		c.MaxConcurrentToolCallsPerSession = limit
+	}
+}
+
+// WithToolCallQueueTimeout sets how long a tool call waits for a free slot when a concurrency
+// limit is reached before failing with a "server busy" error (0, the default, fails immediately).
+func WithToolCallQueueTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.ToolCallQueueTimeout = timeout
+	}
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"encoding/base64"
+	"slices"
+	"strings"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+)
+
+// paginateTools returns the page of tools following cursor, at most Config.ToolsPageSize long
+// (all remaining tools when unset), and the cursor of the next page, empty on the last page.
+// Tools are sorted by name and cursors carry the name of the last tool of their page, so pages
+// neither skip nor repeat tools when the list changes between requests.
+func (e *EchoMCP) paginateTools(tools []types.Tool, cursor string) ([]types.Tool, string, error) {
+	offset := 0
+	if cursor != "" {
+		after, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", InvalidParams("invalid cursor", map[string]any{"cursor": cursor})
+		}
+		offset, _ = slices.BinarySearchFunc(tools, after, func(tool types.Tool, name string) int {
+			return strings.Compare(tool.Name, name)
+		})
+		if offset < len(tools) && tools[offset].Name == after {
+			offset++
+		}
+	}
+
+	end := len(tools)
+	if size := e.config.ToolsPageSize; size > 0 {
+		end = min(offset+size, len(tools))
+	}
+
+	nextCursor := ""
+	if end < len(tools) {
+		nextCursor = encodeCursor(tools[end-1].Name)
+	}
+	return tools[offset:end], nextCursor, nil
+}
+
+// encodeCursor returns the opaque cursor of the page following the tool named after
+func encodeCursor(after string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(after))
+}
+
+// decodeCursor returns the name of the last tool before the page a cursor points to
+func decodeCursor(cursor string) (string, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", err
+	}
+	if len(decoded) == 0 {
+		return "", base64.CorruptInputError(0)
+	}
+	return string(decoded), nil
+}
@@ -0,0 +1,218 @@
+// Package client provides a small MCP client for calling echo-mcp servers,
+// either over the network or in-process through an Echo instance for tests.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/bytedance/sonic"
+	"github.com/labstack/echo/v4"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+)
+
+// sessionHeader is the header carrying the MCP session ID
+const sessionHeader = "Mcp-Session-Id"
+
+// Client calls the tools of an MCP server. The session ID returned by Initialize
+// is sent with every following request.
+type Client struct {
+	do        func(req *http.Request) (*http.Response, error)
+	endpoint  string
+	sessionID string
+	nextID    atomic.Int64
+	mu        sync.RWMutex
+}
+
+// InitializeResult is the server response to an initialize request
+type InitializeResult struct {
+	Capabilities    map[string]any `json:"capabilities"`
+	ServerInfo      ServerInfo     `json:"serverInfo"`
+	ProtocolVersion string         `json:"protocolVersion"`
+}
+
+// ServerInfo identifies the server
+type ServerInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// CallToolResult is the result of a tool call
+type CallToolResult struct {
+	StructuredContent any       `json:"structuredContent,omitempty"`
+	Content           []Content `json:"content"`
+}
+
+// Content is a single content item of a tool call result
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Text returns the concatenated text content of the result
+func (r *CallToolResult) Text() string {
+	var text strings.Builder
+	for _, content := range r.Content {
+		text.WriteString(content.Text)
+	}
+	return text.String()
+}
+
+// NewClient creates a client for the MCP endpoint of a live server
+// (e.g. "http://localhost:8080/mcp"), using http.DefaultClient.
+func NewClient(endpoint string) *Client {
+	return NewClientWithHTTPClient(endpoint, http.DefaultClient)
+}
+
+// NewClientWithHTTPClient creates a client for the MCP endpoint of a live server
+// that sends requests through httpClient.
+func NewClientWithHTTPClient(endpoint string, httpClient *http.Client) *Client {
+	return &Client{
+		endpoint: endpoint,
+		do:       httpClient.Do,
+	}
+}
+
+// NewEchoClient creates a client that serves requests in-process through e,
+// calling the MCP server mounted at path. It is intended for unit tests.
+//
+// Example:
+//
+//	c := client.NewEchoClient(e, "/mcp")
+//	result, err := c.CallTool(ctx, "GET_users_id", map[string]any{"id": "1"})
+func NewEchoClient(e *echo.Echo, path string) *Client {
+	return &Client{
+		endpoint: path,
+		do: func(req *http.Request) (*http.Response, error) {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			return rec.Result(), nil
+		},
+	}
+}
+
+// SessionID returns the session ID assigned by the server, if any
+func (c *Client) SessionID() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.sessionID
+}
+
+// Initialize starts a session with the server
+func (c *Client) Initialize(ctx context.Context) (*InitializeResult, error) {
+	var result InitializeResult
+	if err := c.call(ctx, "initialize", map[string]any{}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Ping checks that the server is alive
+func (c *Client) Ping(ctx context.Context) error {
+	var result map[string]any
+	return c.call(ctx, "ping", map[string]any{}, &result)
+}
+
+// ListTools returns the tools exposed by the server, following nextCursor through every page
+func (c *Client) ListTools(ctx context.Context) ([]types.Tool, error) {
+	var tools []types.Tool
+	var request types.ToolsListRequest
+	for {
+		var result struct {
+			NextCursor string       `json:"nextCursor"`
+			Tools      []types.Tool `json:"tools"`
+		}
+		if err := c.call(ctx, "tools/list", request, &result); err != nil {
+			return nil, err
+		}
+
+		tools = append(tools, result.Tools...)
+		if result.NextCursor == "" {
+			return tools, nil
+		}
+		request.Cursor = result.NextCursor
+	}
+}
+
+// CallTool calls a tool with the given arguments. JSON-RPC errors are returned as *types.MCPError.
+func (c *Client) CallTool(ctx context.Context, name string, arguments map[string]any) (*CallToolResult, error) {
+	var result CallToolResult
+	params := map[string]any{
+		"name":      name,
+		"arguments": arguments,
+	}
+	if err := c.call(ctx, "tools/call", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// call sends a JSON-RPC request and decodes its result into result
+func (c *Client) call(ctx context.Context, method string, params, result any) error {
+	id := c.nextID.Add(1)
+	payload, err := sonic.Marshal(types.MCPMessage{
+		Jsonrpc: "2.0",
+		ID:      json.RawMessage(strconv.FormatInt(id, 10)),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s request: %w", method, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", method, err)
+	}
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationJSON)
+	if sessionID := c.SessionID(); sessionID != "" {
+		req.Header.Set(sessionHeader, sessionID)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read %s response: %w", method, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s request failed with status %d: %s", method, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if sessionID := resp.Header.Get(sessionHeader); sessionID != "" {
+		c.mu.Lock()
+		c.sessionID = sessionID
+		c.mu.Unlock()
+	}
+
+	var response struct {
+		Error  *types.MCPError `json:"error"`
+		Result json.RawMessage `json:"result"`
+	}
+	if err := sonic.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", method, err)
+	}
+	if response.Error != nil {
+		return response.Error
+	}
+
+	if err := sonic.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("failed to decode %s result: %w", method, err)
+	}
+	return nil
+}
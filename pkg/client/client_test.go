@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	server "github.com/BrunoKrugel/echo-mcp"
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+)
+
+func newEcho(t *testing.T) *echo.Echo {
+	t.Helper()
+
+	e := echo.New()
+	e.GET("/users/:id", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"id": c.Param("id"), "name": "Jane"})
+	})
+
+	mcp := server.NewWithOptions(e, server.WithName("Users API"))
+	require.NoError(t, mcp.Mount("/mcp"))
+
+	return e
+}
+
+func TestEchoClient(t *testing.T) {
+	t.Run("Should initialize and keep the session ID", func(t *testing.T) {
+		c := NewEchoClient(newEcho(t), "/mcp")
+
+		result, err := c.Initialize(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, "Users API", result.ServerInfo.Name)
+		assert.NotEmpty(t, result.ProtocolVersion)
+		assert.NotEmpty(t, c.SessionID())
+	})
+
+	t.Run("Should list and call tools", func(t *testing.T) {
+		c := NewEchoClient(newEcho(t), "/mcp")
+		_, err := c.Initialize(context.Background())
+		require.NoError(t, err)
+
+		tools, err := c.ListTools(context.Background())
+		require.NoError(t, err)
+		require.Len(t, tools, 1)
+		assert.Equal(t, "GET_users_id", tools[0].Name)
+
+		result, err := c.CallTool(context.Background(), "GET_users_id", map[string]any{"id": "42"})
+		require.NoError(t, err)
+		assert.Contains(t, result.Text(), "42")
+		assert.Contains(t, result.Text(), "Jane")
+	})
+
+	t.Run("Should answer ping and pong", func(t *testing.T) {
+		c := NewEchoClient(newEcho(t), "/mcp")
+		_, err := c.Initialize(context.Background())
+		require.NoError(t, err)
+
+		require.NoError(t, c.Ping(context.Background()))
+
+		var result map[string]any
+		require.NoError(t, c.call(context.Background(), "pong", map[string]any{}, &result))
+		assert.Empty(t, result)
+	})
+
+	t.Run("Should list tools across pages", func(t *testing.T) {
+		e := echo.New()
+		for _, path := range []string{"/a", "/b", "/c"} {
+			e.GET(path, func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+		}
+		require.NoError(t, server.NewWithOptions(e, server.WithToolsPageSize(2)).Mount("/mcp"))
+
+		tools, err := NewEchoClient(e, "/mcp").ListTools(context.Background())
+		require.NoError(t, err)
+
+		assert.Len(t, tools, 3)
+	})
+
+	t.Run("Should return JSON-RPC errors as MCPError", func(t *testing.T) {
+		c := NewEchoClient(newEcho(t), "/mcp")
+
+		_, err := c.CallTool(context.Background(), "GET_missing", nil)
+
+		var mcpErr *types.MCPError
+		require.True(t, errors.As(err, &mcpErr))
+		assert.Equal(t, types.ErrCodeInvalidParams, mcpErr.Code)
+	})
+
+	t.Run("Should report HTTP errors", func(t *testing.T) {
+		c := NewEchoClient(newEcho(t), "/missing")
+
+		_, err := c.ListTools(context.Background())
+
+		assert.ErrorContains(t, err, "status 404")
+	})
+}
+
+func TestClient(t *testing.T) {
+	t.Run("Should call tools on a live server", func(t *testing.T) {
+		srv := httptest.NewServer(newEcho(t))
+		defer srv.Close()
+
+		c := NewClient(srv.URL + "/mcp")
+		_, err := c.Initialize(context.Background())
+		require.NoError(t, err)
+
+		result, err := c.CallTool(context.Background(), "GET_users_id", map[string]any{"id": "7"})
+		require.NoError(t, err)
+		assert.Contains(t, result.Text(), "id:7")
+	})
+}
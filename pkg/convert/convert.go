@@ -8,73 +8,440 @@ import (
 	"maps"
 	"net/http"
 	"regexp"
+	"slices"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/BrunoKrugel/echo-mcp/pkg/swagger"
 	"github.com/BrunoKrugel/echo-mcp/pkg/types"
 	"github.com/labstack/echo/v4"
 )
 
+// WildcardParameter is the tool argument substituted for the "*" segment of catch-all routes.
+const WildcardParameter = "path"
+
+// toolNamePattern matches the tool names accepted by MCP clients
+var toolNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// Options customizes how routes are converted into tools.
+// The zero value converts routes with the default behavior.
+type Options struct {
+	// OperationIDTransform post-processes every generated operation ID (and therefore tool name)
+	OperationIDTransform func(id string) string
+	// DescriptionTemplate renders the description of tools without a swagger summary
+	DescriptionTemplate *template.Template
+	// DescriptionFormatter post-processes every tool description, including swagger summaries
+	DescriptionFormatter func(route *echo.Route, op swagger.SwaggerOperation) string
+	// DescribeAllResponses appends the shapes of documented 4xx responses to tool descriptions
+	DescribeAllResponses bool
+	// RequireSecurityParameters marks credentials from swagger security schemes as required
+	RequireSecurityParameters bool
+	// MaxToolNameLength caps tool names, shortening longer ones with a hash suffix
+	// (default DefaultMaxToolNameLength)
+	MaxToolNameLength int
+	// PreferSwaggerOperationID uses the swagger operationId as the tool name when it is
+	// present and unique, falling back to the generated METHOD_path name
+	PreferSwaggerOperationID bool
+	// ToolNamePrefix and ToolNameSuffix are added around every generated tool name
+	ToolNamePrefix string
+	ToolNameSuffix string
+	// FlattenBodySchema hoists request body properties out of the "body" wrapper to the
+	// top level of the input schema
+	FlattenBodySchema bool
+	// StrictSchemas sets "additionalProperties": false on input schemas and the object
+	// schemas nested in them, except map-typed fields
+	StrictSchemas bool
+	// NoGenericBody leaves out the generic "body" property of POST, PUT and PATCH routes
+	// without a registered or swagger body schema, unless the route overrides it
+	NoGenericBody bool
+	// AllowDeleteBody sends a request body on every DELETE route, which also gets the generic
+	// "body" property without a body schema. DELETE routes with a swagger body parameter or a
+	// registered body schema send one regardless.
+	AllowDeleteBody bool
+}
+
+// genericBody reports whether routes without a body schema get the generic "body" property
+func (opts Options) genericBody(registered types.RegisteredSchemaInfo) bool {
+	if registered.GenericBody != nil {
+		return *registered.GenericBody
+	}
+	return !opts.NoGenericBody
+}
+
+// deleteBody reports whether a DELETE route sends a request body: when DELETE bodies are
+// allowed, or when its swagger operation declares a body parameter or a body schema is registered
+func (opts Options) deleteBody(route *echo.Route, registered types.RegisteredSchemaInfo, swaggerSpec *swagger.SwaggerSpec) bool {
+	if route.Method != http.MethodDelete {
+		return false
+	}
+	if opts.AllowDeleteBody || registered.BodySchema != nil {
+		return true
+	}
+	return swaggerSpec != nil && swaggerSpec.HasBodyParameter(route.Method, route.Path)
+}
+
+// maxToolNameLength returns the configured tool name limit or the default
+func (opts Options) maxToolNameLength() int {
+	if opts.MaxToolNameLength > 0 {
+		return opts.MaxToolNameLength
+	}
+	return DefaultMaxToolNameLength
+}
+
+// DescriptionData is the data available to Options.DescriptionTemplate.
+type DescriptionData struct {
+	Method      string
+	Path        string
+	Summary     string
+	OperationID string
+	HandlerName string
+	Tags        []string
+}
+
 // ConvertRoutesToTools converts Echo routes into a list of MCP Tools and an operation map.
 func ConvertRoutesToTools(routes []*echo.Route, registeredSchemas map[string]types.RegisteredSchemaInfo, swaggerSpec *swagger.SwaggerSpec) ([]types.Tool, map[string]types.Operation) {
+	return ConvertRoutesToToolsWithOptions(routes, registeredSchemas, swaggerSpec, Options{})
+}
+
+// ConvertRoutesToToolsWithOptions converts Echo routes into a list of MCP Tools and an operation map,
+// applying the given conversion options.
+func ConvertRoutesToToolsWithOptions(routes []*echo.Route, registeredSchemas map[string]types.RegisteredSchemaInfo, swaggerSpec *swagger.SwaggerSpec, opts Options) ([]types.Tool, map[string]types.Operation) {
 	tools := make([]types.Tool, 0)
 	operations := make(map[string]types.Operation)
+	catchAllGets := catchAllGetPaths(routes)
+	seen := make(map[string]bool)
+	maxLength := opts.maxToolNameLength()
+
+	// Process routes in a stable order so collision suffixes are deterministic
+	routes = slices.Clone(routes)
+	slices.SortStableFunc(routes, func(a, b *echo.Route) int {
+		if c := strings.Compare(a.Path, b.Path); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Method, b.Method)
+	})
 
 	for _, route := range routes {
-		if route.Method == "" || route.Path == "" {
+		if route.Method == "" || route.Path == "" || route.Method == echo.RouteNotFound {
+			continue
+		}
+
+		// Collapse exact duplicate method+path pairs
+		routeKey := route.Method + " " + route.Path
+		if seen[routeKey] {
+			continue
+		}
+		seen[routeKey] = true
+
+		// Skip HEAD/OPTIONS duplicates of catch-all GET routes (e.g. registered through Any or Match)
+		if (route.Method == http.MethodHead || route.Method == http.MethodOptions) && catchAllGets[route.Path] {
 			continue
 		}
 
 		operationID := generateOperationID(route.Method, route.Path)
+		if opts.PreferSwaggerOperationID && swaggerSpec != nil {
+			if swaggerID := swaggerSpec.GetOperationID(route.Method, route.Path); toolNamePattern.MatchString(swaggerID) {
+				operationID = swaggerID
+			}
+		}
+		if opts.OperationIDTransform != nil {
+			operationID = opts.OperationIDTransform(operationID)
+		}
+		// Operations naming their tool with x-mcp-tool-name keep that name untransformed
+		if swaggerSpec != nil {
+			if toolName := swaggerSpec.GetToolName(route.Method, route.Path); toolNamePattern.MatchString(toolName) {
+				operationID = toolName
+			}
+		}
+		operationID = uniqueOperationID(affixToolName(operationID, opts.ToolNamePrefix, opts.ToolNameSuffix, maxLength), operations, maxLength)
 
-		tool := generateTool(route, operationID, registeredSchemas, swaggerSpec)
-		tools = append(tools, tool)
+		tool := generateTool(route, operationID, registeredSchemas, swaggerSpec, opts.genericBody(registeredSchemas[routeKey]))
+
+		// DELETE routes allowed to send a body get the generic body argument like POST routes
+		deleteBody := opts.deleteBody(route, registeredSchemas[routeKey], swaggerSpec)
+		if deleteBody && opts.AllowDeleteBody && opts.genericBody(registeredSchemas[routeKey]) && !hasBodySchema(route, registeredSchemas[routeKey], swaggerSpec) {
+			if inputSchema, ok := tool.InputSchema.(map[string]any); ok {
+				tool.InputSchema = types.MergeSchemas(inputSchema, genericBodySchema())
+			}
+		}
+		if inputSchema, ok := tool.InputSchema.(map[string]any); ok && opts.FlattenBodySchema {
+			tool.InputSchema = types.FlattenSchema(inputSchema)
+		}
+		tool.Description = describeTool(route, operationID, tool.Description, registeredSchemas[routeKey], swaggerSpec, opts)
+		if opts.DescribeAllResponses && swaggerSpec != nil {
+			if errorShapes := swaggerSpec.DescribeErrorResponses(route.Method, route.Path); errorShapes != "" {
+				tool.Description += "\n\n" + errorShapes
+			}
+		}
+
+		// Expose the success response shape so clients know what the call returns
+		if swaggerSpec != nil {
+			tool.ResponseSchema = swaggerSpec.GetResponseSchema(route.Method, route.Path)
+		}
+
+		// Flag deprecated operations so clients can de-prioritize them
+		if swaggerSpec != nil && swaggerSpec.IsDeprecated(route.Method, route.Path) {
+			markDeprecated(&tool)
+		}
 
 		// Extract header, query, and form data parameters from swagger if available
 		var headerParams []string
 		var queryParams []string
 		var formDataParams []string
+		var defaults map[string]any
+		var timeout time.Duration
+		var contentType string
 		if swaggerSpec != nil {
+			defaults = swaggerSpec.GetParameterDefaults(route.Method, route.Path)
+			contentType = swaggerSpec.GetRequestContentType(route.Method, route.Path)
+			timeout = swaggerSpec.GetTimeout(route.Method, route.Path)
 			headerParams = extractHeaderParameters(route, swaggerSpec)
 			queryParams = extractQueryParameters(route, swaggerSpec)
 			formDataParams = extractFormDataParameters(route, swaggerSpec)
+
+			// Expose credentials required by swagger security schemes
+			for _, param := range swaggerSpec.GetSecurityParameters(route.Method, route.Path) {
+				addSecurityParameter(&tool, param, opts.RequireSecurityParameters)
+				switch param.In {
+				case "header":
+					if !containsHeader(headerParams, param.Name) {
+						headerParams = append(headerParams, param.Name)
+					}
+				case "query":
+					if !slices.Contains(queryParams, param.Name) {
+						queryParams = append(queryParams, param.Name)
+					}
+				}
+			}
 		}
 
+		// Registered query schemas are sent as query parameters when swagger does not declare any
+		if len(queryParams) == 0 {
+			queryParams = registeredQueryParameters(route, registeredSchemas)
+		}
+
+		// Registered query fields tagged with form are sent as form data on body methods
+		if len(formDataParams) == 0 && isBodyMethod(route.Method) {
+			formDataParams = registeredFormDataParameters(route, registeredSchemas)
+			queryParams = slices.DeleteFunc(queryParams, func(name string) bool { return slices.Contains(formDataParams, name) })
+		}
+
+		// Tell clients the arguments are not sent as a JSON body
+		if len(formDataParams) > 0 {
+			tool.Description += "\n\nSends form-encoded data."
+		}
+
+		// Registered header schemas are always sent as headers
+		for _, name := range registeredHeaderParameters(route, registeredSchemas) {
+			if !containsHeader(headerParams, name) {
+				headerParams = append(headerParams, name)
+			}
+		}
+
+		// Static parameters are sent with every call and never exposed to the model
+		registered := registeredSchemas[routeKey]
+		hideStaticParameters(&tool, registered)
+
+		// Record the final property order, including parameters added or hidden above
+		if inputSchema, ok := tool.InputSchema.(map[string]any); ok {
+			tool.InputSchema = types.NormalizePropertyOrder(inputSchema)
+		}
+
+		// Close the input schema so clients do not send arguments the route does not declare
+		if inputSchema, ok := tool.InputSchema.(map[string]any); ok && opts.StrictSchemas {
+			tool.InputSchema = types.CloseSchema(inputSchema)
+		}
+
+		tools = append(tools, tool)
+
 		operations[operationID] = types.Operation{
-			Method:         route.Method,
-			Path:           route.Path,
-			HeaderParams:   headerParams,
-			QueryParams:    queryParams,
-			FormDataParams: formDataParams,
+			Method:          route.Method,
+			Path:            route.Path,
+			HeaderParams:    headerParams,
+			QueryParams:     queryParams,
+			FormDataParams:  formDataParams,
+			HasBody:         isBodyMethod(route.Method) || deleteBody,
+			ContentType:     contentType,
+			Defaults:        defaults,
+			StaticQuery:     registered.StaticQuery,
+			StaticHeaders:   registered.StaticHeaders,
+			ResponseHeaders: registered.ResponseHeaders,
+			RawPathParams:   rawPathParameters(route, registered, swaggerSpec),
+			Timeout:         timeout,
 		}
 	}
 
 	return tools, operations
 }
 
-// generateOperationID creates a unique operation ID for a route, some agents only support tools names that only contain [a-z0-9_-]
-func generateOperationID(method, path string) string {
-	// Convert path parameters to a consistent format
-	// /users/:id -> /users/{id}
-	normalizedPath := strings.ReplaceAll(path, ":", "")
-	normalizedPath = strings.ReplaceAll(normalizedPath, "/", "_")
-	normalizedPath = strings.ReplaceAll(normalizedPath, ".", "")
-	normalizedPath = strings.Trim(normalizedPath, "_")
-	normalizedPath = strings.ToLower(normalizedPath)
+// describeTool applies the registered route description or the description template
+// (when there is no swagger summary) and then the description formatter to a generated tool description
+func describeTool(route *echo.Route, operationID, description string, registered types.RegisteredSchemaInfo, swaggerSpec *swagger.SwaggerSpec, opts Options) string {
+	var operation swagger.SwaggerOperation
+	if swaggerSpec != nil {
+		operation, _ = swaggerSpec.GetOperation(route.Method, route.Path)
+	}
+
+	tags := operation.Tags
+	if len(tags) == 0 {
+		tags = registered.Tags
+	}
+
+	if registered.Description != "" {
+		description = registered.Description
+	} else if opts.DescriptionTemplate != nil && operation.Summary == "" {
+		var rendered strings.Builder
+		err := opts.DescriptionTemplate.Execute(&rendered, DescriptionData{
+			Method:      route.Method,
+			Path:        route.Path,
+			Summary:     operation.Description,
+			OperationID: operationID,
+			HandlerName: route.Name,
+			Tags:        tags,
+		})
+		if err == nil {
+			description = rendered.String()
+		}
+	}
+
+	if opts.DescriptionFormatter != nil {
+		if formatted := opts.DescriptionFormatter(route, operation); formatted != "" {
+			description = formatted
+		}
+	}
+
+	return description
+}
+
+// registeredQueryParameters returns the property names of the query schema registered for a route
+func registeredQueryParameters(route *echo.Route, registeredSchemas map[string]types.RegisteredSchemaInfo) []string {
+	registeredSchema, exists := registeredSchemas[fmt.Sprintf("%s %s", route.Method, route.Path)]
+	if !exists || registeredSchema.QuerySchema == nil {
+		return nil
+	}
+
+	properties, _ := cachedSchema(registeredSchema.QuerySchema)["properties"].(map[string]any)
+	queryParams := slices.Collect(maps.Keys(properties))
+	slices.Sort(queryParams)
+	return queryParams
+}
+
+// registeredFormDataParameters returns the names of the form-tagged fields of the query schema struct
+// registered for a route, or all fields of a body schema struct made only of form-tagged fields.
+// Routes with any other registered body schema take a JSON body, so they have none.
+func registeredFormDataParameters(route *echo.Route, registeredSchemas map[string]types.RegisteredSchemaInfo) []string {
+	registeredSchema, exists := registeredSchemas[fmt.Sprintf("%s %s", route.Method, route.Path)]
+	if !exists {
+		return nil
+	}
+
+	formSchema := registeredSchema.QuerySchema
+	if registeredSchema.BodySchema != nil {
+		if !types.IsFormStruct(registeredSchema.BodySchema) {
+			return nil
+		}
+		formSchema = registeredSchema.BodySchema
+	}
+
+	formDataParams := types.FormFieldNames(formSchema)
+	slices.Sort(formDataParams)
+	return formDataParams
+}
+
+// rawPathParameters returns the path parameters of a route marked with the x-mcp-raw-path
+// swagger extension or registered with RegisterRawPathParam
+func rawPathParameters(route *echo.Route, registered types.RegisteredSchemaInfo, swaggerSpec *swagger.SwaggerSpec) []string {
+	rawPathParams := slices.Clone(registered.RawPathParams)
+	if swaggerSpec != nil {
+		for _, name := range swaggerSpec.GetRawPathParameters(route.Method, route.Path) {
+			if !slices.Contains(rawPathParams, name) {
+				rawPathParams = append(rawPathParams, name)
+			}
+		}
+	}
+	return rawPathParams
+}
 
-	if normalizedPath == "" {
-		normalizedPath = "root"
+// registeredHeaderParameters returns the property names of the header schema registered for a route
+func registeredHeaderParameters(route *echo.Route, registeredSchemas map[string]types.RegisteredSchemaInfo) []string {
+	registeredSchema, exists := registeredSchemas[fmt.Sprintf("%s %s", route.Method, route.Path)]
+	if !exists || registeredSchema.HeaderSchema == nil {
+		return nil
 	}
 
-	return fmt.Sprintf("%s_%s", method, normalizedPath)
+	properties, _ := cachedSchema(registeredSchema.HeaderSchema)["properties"].(map[string]any)
+	headerParams := slices.Collect(maps.Keys(properties))
+	slices.Sort(headerParams)
+	return headerParams
+}
+
+// hideStaticParameters removes the registered static query parameters and headers from the tool input schema
+func hideStaticParameters(tool *types.Tool, registered types.RegisteredSchemaInfo) {
+	schema, ok := tool.InputSchema.(map[string]any)
+	if !ok || len(registered.StaticQuery)+len(registered.StaticHeaders) == 0 {
+		return
+	}
+
+	isStatic := func(name string) bool {
+		if _, exists := registered.StaticQuery[name]; exists {
+			return true
+		}
+		for header := range registered.StaticHeaders {
+			if strings.EqualFold(header, name) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if properties, ok := schema["properties"].(map[string]any); ok {
+		visible := maps.Clone(properties)
+		maps.DeleteFunc(visible, func(name string, _ any) bool { return isStatic(name) })
+		schema["properties"] = visible
+	}
+
+	requiredFields, _ := schema["required"].([]string)
+	if required := slices.DeleteFunc(slices.Clone(requiredFields), isStatic); len(required) > 0 {
+		schema["required"] = required
+	} else {
+		delete(schema, "required")
+	}
+}
+
+// addSecurityParameter adds a credential parameter to the tool input schema
+func addSecurityParameter(tool *types.Tool, param swagger.SecurityParameter, required bool) {
+	schema, ok := tool.InputSchema.(map[string]any)
+	if !ok {
+		return
+	}
+
+	properties, ok := schema["properties"].(map[string]any)
+	if !ok {
+		return
+	}
+
+	if _, exists := properties[param.Name]; !exists {
+		properties[param.Name] = map[string]any{
+			"type":        "string",
+			"description": param.Description,
+		}
+	}
+
+	if required {
+		requiredFields, _ := schema["required"].([]string)
+		if !slices.Contains(requiredFields, param.Name) {
+			schema["required"] = append(requiredFields, param.Name)
+		}
+	}
 }
 
 // generateTool converts an Echo route to an MCP Tool
-func generateTool(route *echo.Route, operationID string, registeredSchemas map[string]types.RegisteredSchemaInfo, swaggerSpec *swagger.SwaggerSpec) types.Tool {
+func generateTool(route *echo.Route, operationID string, registeredSchemas map[string]types.RegisteredSchemaInfo, swaggerSpec *swagger.SwaggerSpec, genericBody bool) types.Tool {
 	schemaKey := fmt.Sprintf("%s %s", route.Method, route.Path)
 	registeredSchema, hasRegisteredSchema := registeredSchemas[schemaKey]
 
-	inputSchema := generateInputSchema(route, registeredSchema, hasRegisteredSchema, swaggerSpec)
+	inputSchema := generateInputSchema(route, registeredSchema, hasRegisteredSchema, swaggerSpec, genericBody)
 
 	description := fmt.Sprintf("Execute %s request to %s", route.Method, route.Path)
 
@@ -94,8 +461,10 @@ func generateTool(route *echo.Route, operationID string, registeredSchemas map[s
 	}
 }
 
-// generateInputSchema creates the input schema for a tool based on the route
-func generateInputSchema(route *echo.Route, registeredSchema types.RegisteredSchemaInfo, hasRegisteredSchema bool, swaggerSpec *swagger.SwaggerSpec) map[string]any {
+// generateInputSchema creates the input schema for a tool based on the route. Without a
+// body schema, POST, PUT and PATCH routes get a generic "body" property if genericBody is set.
+// DELETE routes only get the registered body schema.
+func generateInputSchema(route *echo.Route, registeredSchema types.RegisteredSchemaInfo, hasRegisteredSchema bool, swaggerSpec *swagger.SwaggerSpec, genericBody bool) map[string]any {
 	schema := map[string]any{
 		"type":       "object",
 		"properties": map[string]any{},
@@ -117,17 +486,26 @@ func generateInputSchema(route *echo.Route, registeredSchema types.RegisteredSch
 		required = append(required, param)
 	}
 
+	// Catch-all routes take the remainder of the path as a single argument
+	if IsCatchAll(route.Path) {
+		properties[WildcardParameter] = map[string]any{
+			"type":        "string",
+			"description": fmt.Sprintf("Path matched by the wildcard in %s (e.g. a/b.txt)", route.Path),
+		}
+		required = append(required, WildcardParameter)
+	}
+
+	if len(required) > 0 {
+		schema["required"] = required
+		schema[types.PropertyOrderKey] = slices.Clone(required)
+	}
+
 	// Try swagger schema first, then registered schema, then fallback
 	swaggerUsed := false
 	if swaggerSpec != nil {
 		if swaggerSchema, err := swaggerSpec.GetOperationSchema(route.Method, route.Path); err == nil {
 			// Use swagger schema
-			if props, ok := swaggerSchema["properties"].(map[string]any); ok {
-				maps.Copy(properties, props)
-			}
-			if reqFields, ok := swaggerSchema["required"].([]string); ok {
-				required = append(required, reqFields...)
-			}
+			schema = types.MergeSchemas(schema, swaggerSchema)
 			swaggerUsed = true
 		}
 	}
@@ -136,48 +514,77 @@ func generateInputSchema(route *echo.Route, registeredSchema types.RegisteredSch
 	if !swaggerUsed {
 		// Add query parameters from registered schema if available
 		if hasRegisteredSchema && registeredSchema.QuerySchema != nil {
-			querySchema := types.GetSchema(registeredSchema.QuerySchema)
-			if queryProps, ok := querySchema["properties"].(map[string]any); ok {
-				maps.Copy(properties, queryProps)
-			}
-			if queryRequired, ok := querySchema["required"].([]string); ok {
-				required = append(required, queryRequired...)
-			}
+			schema = types.MergeSchemas(schema, cachedSchema(registeredSchema.QuerySchema))
 		}
 
-		// Add request body schema for methods that typically have bodies
-		if isBodyMethod(route.Method) {
+		// Add request body schema for methods that typically have bodies, and for DELETE
+		// routes registered with one
+		if isBodyMethod(route.Method) || route.Method == http.MethodDelete {
 			if hasRegisteredSchema && registeredSchema.BodySchema != nil {
-				bodySchema := types.GetSchema(registeredSchema.BodySchema)
-				if bodyProps, ok := bodySchema["properties"].(map[string]any); ok {
-					maps.Copy(properties, bodyProps)
-				}
-				if bodyRequired, ok := bodySchema["required"].([]string); ok {
-					required = append(required, bodyRequired...)
-				}
-			} else {
+				schema = types.MergeSchemas(schema, cachedSchema(registeredSchema.BodySchema))
+			} else if genericBody && isBodyMethod(route.Method) {
 				// Generic body parameter
-				properties["body"] = map[string]any{
-					"type":        "object",
-					"description": "Request body",
-				}
+				schema = types.MergeSchemas(schema, genericBodySchema())
 			}
 		}
 	}
 
-	if len(required) > 0 {
-		schema["required"] = required
+	// Registered headers complement both swagger and registered schemas
+	if hasRegisteredSchema && registeredSchema.HeaderSchema != nil {
+		schema = types.MergeSchemas(schema, cachedSchema(registeredSchema.HeaderSchema))
 	}
 
 	return schema
 }
 
+// genericBodySchema returns the schema fragment of the generic "body" property
+func genericBodySchema() map[string]any {
+	return map[string]any{
+		"properties": map[string]any{
+			"body": map[string]any{
+				"type":        "object",
+				"description": "Request body",
+			},
+		},
+		types.PropertyOrderKey: []string{"body"},
+	}
+}
+
+// hasBodySchema reports whether the input schema of a route is built from a swagger
+// operation or a registered body schema, which describe the body themselves
+func hasBodySchema(route *echo.Route, registered types.RegisteredSchemaInfo, swaggerSpec *swagger.SwaggerSpec) bool {
+	if registered.BodySchema != nil {
+		return true
+	}
+	if swaggerSpec == nil {
+		return false
+	}
+	_, err := swaggerSpec.GetOperationSchema(route.Method, route.Path)
+	return err == nil
+}
+
 // isBodyMethod returns true if the HTTP method typically has a request body
 func isBodyMethod(method string) bool {
 	method = strings.ToUpper(method)
 	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch
 }
 
+// IsCatchAll returns true if the Echo route path contains a "*" wildcard segment
+func IsCatchAll(path string) bool {
+	return strings.Contains(path, "*")
+}
+
+// catchAllGetPaths returns the set of catch-all paths registered with a GET route
+func catchAllGetPaths(routes []*echo.Route) map[string]bool {
+	paths := make(map[string]bool)
+	for _, route := range routes {
+		if route.Method == http.MethodGet && IsCatchAll(route.Path) {
+			paths[route.Path] = true
+		}
+	}
+	return paths
+}
+
 // extractPathParameters extracts parameter names from an Echo route path
 func extractPathParameters(path string) []string {
 	var params []string
@@ -213,23 +620,25 @@ func getSwaggerDescription(route *echo.Route, swaggerSpec *swagger.SwaggerSpec)
 		return ""
 	}
 
-	swaggerPath := echoPathToSwaggerPath(route.Path)
-
-	if pathSpec, exists := swaggerSpec.Paths[swaggerPath]; exists {
-		method := strings.ToLower(route.Method)
-		if operation, operationExists := pathSpec[method]; operationExists {
-			if operation.Summary != "" {
-				return operation.Summary
-			}
-			if operation.Description != "" {
-				return operation.Description
-			}
+	if operation, exists := swaggerSpec.GetOperation(route.Method, route.Path); exists {
+		if operation.Summary != "" {
+			return operation.Summary
+		}
+		if operation.Description != "" {
+			return operation.Description
 		}
 	}
 
 	return ""
 }
 
+// containsHeader reports whether headers contains name, comparing canonical header keys
+func containsHeader(headers []string, name string) bool {
+	return slices.ContainsFunc(headers, func(header string) bool {
+		return http.CanonicalHeaderKey(header) == http.CanonicalHeaderKey(name)
+	})
+}
+
 // extractHeaderParameters extracts header parameter names from swagger specification
 func extractHeaderParameters(route *echo.Route, swaggerSpec *swagger.SwaggerSpec) []string {
 	var headerParams []string
@@ -238,15 +647,10 @@ func extractHeaderParameters(route *echo.Route, swaggerSpec *swagger.SwaggerSpec
 		return headerParams
 	}
 
-	swaggerPath := echoPathToSwaggerPath(route.Path)
-
-	if pathSpec, exists := swaggerSpec.Paths[swaggerPath]; exists {
-		method := strings.ToLower(route.Method)
-		if operation, operationExists := pathSpec[method]; operationExists {
-			for _, param := range operation.Parameters {
-				if param.In == "header" {
-					headerParams = append(headerParams, param.Name)
-				}
+	if operation, exists := swaggerSpec.GetOperation(route.Method, route.Path); exists {
+		for _, param := range operation.Parameters {
+			if param.In == "header" {
+				headerParams = append(headerParams, param.Name)
 			}
 		}
 	}
@@ -262,15 +666,10 @@ func extractQueryParameters(route *echo.Route, swaggerSpec *swagger.SwaggerSpec)
 		return queryParams
 	}
 
-	swaggerPath := echoPathToSwaggerPath(route.Path)
-
-	if pathSpec, exists := swaggerSpec.Paths[swaggerPath]; exists {
-		method := strings.ToLower(route.Method)
-		if operation, operationExists := pathSpec[method]; operationExists {
-			for _, param := range operation.Parameters {
-				if param.In == "query" {
-					queryParams = append(queryParams, param.Name)
-				}
+	if operation, exists := swaggerSpec.GetOperation(route.Method, route.Path); exists {
+		for _, param := range operation.Parameters {
+			if param.In == "query" {
+				queryParams = append(queryParams, param.Name)
 			}
 		}
 	}
@@ -286,17 +685,10 @@ func extractFormDataParameters(route *echo.Route, swaggerSpec *swagger.SwaggerSp
 		return formDataParams
 	}
 
-	// Convert Echo path to Swagger path format
-	swaggerPath := echoPathToSwaggerPath(route.Path)
-
-	// Try to find the path in swagger spec
-	if pathSpec, exists := swaggerSpec.Paths[swaggerPath]; exists {
-		method := strings.ToLower(route.Method)
-		if operation, operationExists := pathSpec[method]; operationExists {
-			for _, param := range operation.Parameters {
-				if param.In == "formData" {
-					formDataParams = append(formDataParams, param.Name)
-				}
+	if operation, exists := swaggerSpec.GetOperation(route.Method, route.Path); exists {
+		for _, param := range operation.Parameters {
+			if param.In == "formData" {
+				formDataParams = append(formDataParams, param.Name)
 			}
 		}
 	}
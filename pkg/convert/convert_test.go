@@ -1,10 +1,15 @@
 package convert
 
 import (
+	"encoding/json"
+	"regexp"
+	"strings"
 	"testing"
+	"text/template"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/BrunoKrugel/echo-mcp/pkg/swagger"
 	"github.com/BrunoKrugel/echo-mcp/pkg/types"
@@ -101,7 +106,7 @@ func TestGenerateTool(t *testing.T) {
 			Method: "GET",
 		}
 
-		tool := generateTool(route, "GET_users_id", nil, nil)
+		tool := generateTool(route, "GET_users_id", nil, nil, true)
 
 		assert.Equal(t, "GET_users_id", tool.Name)
 		assert.Contains(t, tool.Description, "GET")
@@ -127,7 +132,7 @@ func TestGenerateTool(t *testing.T) {
 			},
 		}
 
-		tool := generateTool(route, "GET_users", nil, swaggerSpec)
+		tool := generateTool(route, "GET_users", nil, swaggerSpec, true)
 
 		assert.Equal(t, "Get all users", tool.Description)
 	})
@@ -140,7 +145,7 @@ func TestGenerateInputSchema(t *testing.T) {
 			Method: "GET",
 		}
 
-		schema := generateInputSchema(route, types.RegisteredSchemaInfo{}, false, nil)
+		schema := generateInputSchema(route, types.RegisteredSchemaInfo{}, false, nil, true)
 
 		assert.Equal(t, "object", schema["type"])
 
@@ -161,7 +166,7 @@ func TestGenerateInputSchema(t *testing.T) {
 			Method: "POST",
 		}
 
-		schema := generateInputSchema(route, types.RegisteredSchemaInfo{}, false, nil)
+		schema := generateInputSchema(route, types.RegisteredSchemaInfo{}, false, nil, true)
 
 		properties, ok := schema["properties"].(map[string]any)
 		assert.True(t, ok)
@@ -177,7 +182,7 @@ func TestGenerateInputSchema(t *testing.T) {
 			Method: "GET",
 		}
 
-		schema := generateInputSchema(route, types.RegisteredSchemaInfo{}, false, nil)
+		schema := generateInputSchema(route, types.RegisteredSchemaInfo{}, false, nil, true)
 
 		properties, ok := schema["properties"].(map[string]any)
 		assert.True(t, ok)
@@ -199,7 +204,7 @@ func TestGenerateInputSchema(t *testing.T) {
 			BodySchema: BodySchema{},
 		}
 
-		schema := generateInputSchema(route, registeredSchema, true, nil)
+		schema := generateInputSchema(route, registeredSchema, true, nil, true)
 
 		properties, ok := schema["properties"].(map[string]any)
 		assert.True(t, ok)
@@ -228,7 +233,7 @@ func TestGenerateInputSchema(t *testing.T) {
 			QuerySchema: QuerySchema{},
 		}
 
-		schema := generateInputSchema(route, registeredSchema, true, nil)
+		schema := generateInputSchema(route, registeredSchema, true, nil, true)
 
 		properties, ok := schema["properties"].(map[string]any)
 		assert.True(t, ok)
@@ -526,3 +531,892 @@ func TestExtractFormDataParameters(t *testing.T) {
 		assert.Empty(t, formDataParams)
 	})
 }
+
+func TestCatchAllRoutes(t *testing.T) {
+	t.Run("Should convert catch-all routes with a required path argument", func(t *testing.T) {
+		routes := []*echo.Route{
+			{Path: "/files/*", Method: "GET"},
+		}
+
+		tools, operations := ConvertRoutesToTools(routes, nil, nil)
+
+		assert.Len(t, tools, 1)
+		assert.Equal(t, "GET_files_wildcard", tools[0].Name)
+		assert.Contains(t, operations, "GET_files_wildcard")
+
+		schema := tools[0].InputSchema.(map[string]any)
+		properties := schema["properties"].(map[string]any)
+		assert.Contains(t, properties, WildcardParameter)
+		assert.Contains(t, schema["required"], WildcardParameter)
+	})
+
+	t.Run("Should skip HEAD and OPTIONS duplicates of catch-all routes", func(t *testing.T) {
+		routes := []*echo.Route{
+			{Path: "/files/*", Method: "GET"},
+			{Path: "/files/*", Method: "HEAD"},
+			{Path: "/files/*", Method: "OPTIONS"},
+			{Path: "/users", Method: "HEAD"},
+		}
+
+		tools, _ := ConvertRoutesToTools(routes, nil, nil)
+
+		names := make([]string, 0, len(tools))
+		for _, tool := range tools {
+			names = append(names, tool.Name)
+		}
+		assert.ElementsMatch(t, []string{"GET_files_wildcard", "HEAD_users"}, names)
+	})
+
+	t.Run("Should skip route not found handlers", func(t *testing.T) {
+		routes := []*echo.Route{
+			{Path: "/*", Method: echo.RouteNotFound},
+		}
+
+		tools, operations := ConvertRoutesToTools(routes, nil, nil)
+
+		assert.Empty(t, tools)
+		assert.Empty(t, operations)
+	})
+}
+
+func TestDuplicateRoutes(t *testing.T) {
+	t.Run("Should collapse exact duplicate method and path pairs", func(t *testing.T) {
+		routes := []*echo.Route{
+			{Path: "/users", Method: "GET"},
+			{Path: "/users", Method: "GET"},
+			{Path: "/users", Method: "POST"},
+		}
+
+		tools, operations := ConvertRoutesToTools(routes, nil, nil)
+
+		assert.Len(t, tools, 2)
+		assert.Len(t, operations, 2)
+	})
+}
+
+func TestOperationIDCollisions(t *testing.T) {
+	t.Run("Should suffix colliding operation IDs", func(t *testing.T) {
+		routes := []*echo.Route{
+			{Path: "/users/id", Method: "GET"},
+			{Path: "/users/:id", Method: "GET"},
+		}
+
+		tools, operations := ConvertRoutesToTools(routes, nil, nil)
+
+		assert.Len(t, tools, 2)
+		assert.Len(t, operations, 2)
+		assert.Equal(t, "/users/:id", operations["GET_users_id"].Path)
+		assert.Equal(t, "/users/id", operations["GET_users_id_2"].Path)
+	})
+
+	t.Run("Should assign suffixes independently of route order", func(t *testing.T) {
+		routes := []*echo.Route{
+			{Path: "/users.json", Method: "GET"},
+			{Path: "/usersjson", Method: "GET"},
+			{Path: "/users/:id", Method: "GET"},
+			{Path: "/users/id", Method: "GET"},
+		}
+		reversed := []*echo.Route{routes[3], routes[2], routes[1], routes[0]}
+
+		_, operations := ConvertRoutesToTools(routes, nil, nil)
+		_, reversedOperations := ConvertRoutesToTools(reversed, nil, nil)
+
+		assert.Len(t, operations, 4)
+		assert.Contains(t, operations, "GET_usersjson_2")
+		assert.Equal(t, operations, reversedOperations)
+	})
+
+	t.Run("Should keep distinct parameter names distinct", func(t *testing.T) {
+		routes := []*echo.Route{
+			{Path: "/users/:id", Method: "GET"},
+			{Path: "/users/:userID", Method: "GET"},
+		}
+
+		_, operations := ConvertRoutesToTools(routes, nil, nil)
+
+		assert.Contains(t, operations, "GET_users_id")
+		assert.Contains(t, operations, "GET_users_userid")
+	})
+}
+
+func TestOperationIDTransform(t *testing.T) {
+	t.Run("Should apply the transform to tool names and operation keys", func(t *testing.T) {
+		routes := []*echo.Route{
+			{Path: "/v1/users", Method: "GET"},
+		}
+
+		tools, operations := ConvertRoutesToToolsWithOptions(routes, nil, nil, Options{
+			OperationIDTransform: func(id string) string {
+				return "billing_" + strings.Replace(id, "_v1", "", 1)
+			},
+		})
+
+		assert.Equal(t, "billing_GET_users", tools[0].Name)
+		assert.Contains(t, operations, "billing_GET_users")
+	})
+
+	t.Run("Should keep transformed IDs unique", func(t *testing.T) {
+		routes := []*echo.Route{
+			{Path: "/v1/users", Method: "GET"},
+			{Path: "/v2/users", Method: "GET"},
+		}
+
+		_, operations := ConvertRoutesToToolsWithOptions(routes, nil, nil, Options{
+			OperationIDTransform: func(id string) string {
+				return strings.NewReplacer("_v1", "", "_v2", "").Replace(id)
+			},
+		})
+
+		assert.Contains(t, operations, "GET_users")
+		assert.Contains(t, operations, "GET_users_2")
+	})
+}
+
+func TestSecurityParameters(t *testing.T) {
+	swaggerSpec := &swagger.SwaggerSpec{
+		SecurityDefinitions: map[string]*swagger.SwaggerSecurityScheme{
+			"ApiKey":   {Type: "apiKey", Name: "X-API-Key", In: "header"},
+			"QueryKey": {Type: "apiKey", Name: "api_key", In: "query"},
+		},
+		Paths: map[string]swagger.SwaggerPath{
+			"/users": {
+				"get": swagger.SwaggerOperation{
+					Security: []swagger.SecurityRequirement{{"ApiKey": {}, "QueryKey": {}}},
+				},
+			},
+		},
+	}
+	routes := []*echo.Route{{Path: "/users", Method: "GET"}}
+
+	t.Run("Should add security parameters to schema and operation", func(t *testing.T) {
+		tools, operations := ConvertRoutesToTools(routes, nil, swaggerSpec)
+
+		schema := tools[0].InputSchema.(map[string]any)
+		properties := schema["properties"].(map[string]any)
+		assert.Contains(t, properties, "X-API-Key")
+		assert.Contains(t, properties, "api_key")
+		assert.NotContains(t, schema, "required")
+
+		assert.Equal(t, []string{"X-API-Key"}, operations["GET_users"].HeaderParams)
+		assert.Equal(t, []string{"api_key"}, operations["GET_users"].QueryParams)
+	})
+
+	t.Run("Should mark security parameters as required when configured", func(t *testing.T) {
+		tools, _ := ConvertRoutesToToolsWithOptions(routes, nil, swaggerSpec, Options{RequireSecurityParameters: true})
+
+		schema := tools[0].InputSchema.(map[string]any)
+		assert.ElementsMatch(t, []string{"X-API-Key", "api_key"}, schema["required"])
+	})
+}
+
+func TestPreferSwaggerOperationID(t *testing.T) {
+	swaggerSpec := &swagger.SwaggerSpec{
+		Paths: map[string]swagger.SwaggerPath{
+			"/api/v1/users/{id}": {
+				"get":    swagger.SwaggerOperation{OperationID: "getUserById"},
+				"delete": swagger.SwaggerOperation{OperationID: "removeUser"},
+				"put":    swagger.SwaggerOperation{OperationID: "removeUser"},
+			},
+			"/api/v1/users": {
+				"get": swagger.SwaggerOperation{OperationID: "users.list"},
+			},
+		},
+	}
+	routes := []*echo.Route{
+		{Path: "/api/v1/users/:id", Method: "GET"},
+		{Path: "/api/v1/users/:id", Method: "DELETE"},
+		{Path: "/api/v1/users/:id", Method: "PUT"},
+		{Path: "/api/v1/users", Method: "GET"},
+		{Path: "/health", Method: "GET"},
+	}
+
+	t.Run("Should keep generated names by default", func(t *testing.T) {
+		_, operations := ConvertRoutesToTools(routes, nil, swaggerSpec)
+
+		assert.Contains(t, operations, "GET_api_v1_users_id")
+		assert.NotContains(t, operations, "getUserById")
+	})
+
+	t.Run("Should use swagger operationId when preferred", func(t *testing.T) {
+		tools, operations := ConvertRoutesToToolsWithOptions(routes, nil, swaggerSpec, Options{PreferSwaggerOperationID: true})
+
+		require.Contains(t, operations, "getUserById")
+		assert.Equal(t, "/api/v1/users/:id", operations["getUserById"].Path)
+		assert.Equal(t, "GET", operations["getUserById"].Method)
+
+		names := make([]string, 0, len(tools))
+		for _, tool := range tools {
+			names = append(names, tool.Name)
+		}
+		assert.Contains(t, names, "getUserById")
+		assert.Contains(t, names, "GET_health")
+	})
+
+	t.Run("Should fall back when operationIds collide or are invalid", func(t *testing.T) {
+		_, operations := ConvertRoutesToToolsWithOptions(routes, nil, swaggerSpec, Options{PreferSwaggerOperationID: true})
+
+		assert.NotContains(t, operations, "removeUser")
+		assert.Contains(t, operations, "DELETE_api_v1_users_id")
+		assert.Contains(t, operations, "PUT_api_v1_users_id")
+		assert.Contains(t, operations, "GET_api_v1_users")
+	})
+}
+
+func TestDescriptionCustomization(t *testing.T) {
+	swaggerSpec := &swagger.SwaggerSpec{
+		Paths: map[string]swagger.SwaggerPath{
+			"/users": {
+				"get": swagger.SwaggerOperation{Summary: "List users", Tags: []string{"users"}},
+			},
+			"/users/{id}": {
+				"get": swagger.SwaggerOperation{Tags: []string{"users", "admin"}},
+			},
+		},
+	}
+	routes := []*echo.Route{
+		{Path: "/users", Method: "GET"},
+		{Path: "/users/:id", Method: "GET"},
+	}
+
+	toolsByName := func(tools []types.Tool) map[string]types.Tool {
+		byName := make(map[string]types.Tool)
+		for _, tool := range tools {
+			byName[tool.Name] = tool
+		}
+		return byName
+	}
+
+	t.Run("Should render the template when there is no swagger summary", func(t *testing.T) {
+		tmpl := template.Must(template.New("description").Funcs(template.FuncMap{"join": strings.Join}).Parse(`{{.OperationID}}: {{.Method}} {{.Path}} [{{join .Tags ", "}}]`))
+
+		tools, _ := ConvertRoutesToToolsWithOptions(routes, nil, swaggerSpec, Options{DescriptionTemplate: tmpl})
+
+		byName := toolsByName(tools)
+		assert.Equal(t, "List users", byName["GET_users"].Description)
+		assert.Equal(t, "GET_users_id: GET /users/:id [users, admin]", byName["GET_users_id"].Description)
+	})
+
+	t.Run("Should expose the handler name to the template", func(t *testing.T) {
+		tmpl := template.Must(template.New("description").Parse(`Calls {{.HandlerName}} for {{.Method}} {{.Path}}`))
+		named := []*echo.Route{{Path: "/orders", Method: "GET", Name: "main.listOrders"}}
+
+		tools, _ := ConvertRoutesToToolsWithOptions(named, nil, nil, Options{DescriptionTemplate: tmpl})
+
+		assert.Equal(t, "Calls main.listOrders for GET /orders", tools[0].Description)
+	})
+
+	t.Run("Should apply the formatter last", func(t *testing.T) {
+		tools, _ := ConvertRoutesToToolsWithOptions(routes, nil, swaggerSpec, Options{
+			DescriptionFormatter: func(route *echo.Route, op swagger.SwaggerOperation) string {
+				if op.Summary == "" {
+					return ""
+				}
+				return strings.ToUpper(op.Summary) + " (" + route.Path + ")"
+			},
+		})
+
+		byName := toolsByName(tools)
+		assert.Equal(t, "LIST USERS (/users)", byName["GET_users"].Description)
+		assert.Equal(t, "Execute GET request to /users/:id", byName["GET_users_id"].Description)
+	})
+}
+
+func TestDescribeAllResponses(t *testing.T) {
+	swaggerSpec := &swagger.SwaggerSpec{
+		Definitions: map[string]*swagger.SwaggerSchema{
+			"main.AppError": {
+				Type:       "object",
+				Properties: map[string]*swagger.SwaggerSchema{"error": {Type: "string"}},
+			},
+		},
+		Paths: map[string]swagger.SwaggerPath{
+			"/users/{id}": {
+				"get": swagger.SwaggerOperation{
+					Summary: "Get a user",
+					Parameters: []swagger.SwaggerParameter{
+						{Name: "id", In: "path", Type: "string", Format: "uuid", Required: true},
+					},
+					Responses: map[string]swagger.SwaggerResponse{
+						"400": {Schema: &swagger.SwaggerSchema{Ref: "#/definitions/main.AppError"}},
+					},
+				},
+			},
+		},
+	}
+	routes := []*echo.Route{{Path: "/users/:id", Method: "GET"}}
+
+	t.Run("Should append error shapes and keep parameter formats", func(t *testing.T) {
+		tools, _ := ConvertRoutesToToolsWithOptions(routes, nil, swaggerSpec, Options{DescribeAllResponses: true})
+
+		assert.Equal(t, "Get a user\n\nError shape: {error: string}", tools[0].Description)
+
+		properties := tools[0].InputSchema.(map[string]any)["properties"].(map[string]any)
+		assert.Equal(t, "uuid", properties["id"].(map[string]any)["format"])
+	})
+
+	t.Run("Should not describe error responses by default", func(t *testing.T) {
+		tools, _ := ConvertRoutesToTools(routes, nil, swaggerSpec)
+
+		assert.Equal(t, "Get a user", tools[0].Description)
+	})
+}
+
+func TestResponseSchema(t *testing.T) {
+	swaggerSpec := &swagger.SwaggerSpec{
+		Paths: map[string]swagger.SwaggerPath{
+			"/users": {
+				"get": swagger.SwaggerOperation{
+					Responses: map[string]swagger.SwaggerResponse{
+						"200": {Schema: &swagger.SwaggerSchema{
+							Type:  "array",
+							Items: &swagger.SwaggerSchema{Type: "string"},
+						}},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("Should expose the swagger success response schema", func(t *testing.T) {
+		routes := []*echo.Route{{Path: "/users", Method: "GET"}}
+		tools, _ := ConvertRoutesToTools(routes, nil, swaggerSpec)
+
+		schema := tools[0].ResponseSchema.(map[string]any)
+		assert.Equal(t, "array", schema["type"])
+	})
+
+	t.Run("Should leave the response schema empty without swagger", func(t *testing.T) {
+		routes := []*echo.Route{{Path: "/users", Method: "GET"}}
+		tools, _ := ConvertRoutesToTools(routes, nil, nil)
+
+		assert.Nil(t, tools[0].ResponseSchema)
+	})
+}
+
+func TestInputSchemaMerging(t *testing.T) {
+	t.Run("Should merge swagger path parameters without duplicating required fields", func(t *testing.T) {
+		swaggerSpec := &swagger.SwaggerSpec{
+			Paths: map[string]swagger.SwaggerPath{
+				"/users/{id}": {
+					"get": swagger.SwaggerOperation{
+						Parameters: []swagger.SwaggerParameter{
+							{Name: "id", In: "path", Type: "string", Format: "uuid", Required: true},
+						},
+					},
+				},
+			},
+		}
+		route := &echo.Route{Path: "/users/:id", Method: "GET"}
+
+		schema := generateInputSchema(route, types.RegisteredSchemaInfo{}, false, swaggerSpec, true)
+
+		assert.Equal(t, []string{"id"}, schema["required"])
+		id := schema["properties"].(map[string]any)["id"].(map[string]any)
+		assert.Equal(t, "uuid", id["format"])
+		assert.Equal(t, "Path parameter: id", id["description"])
+	})
+
+	t.Run("Should keep nested registered body schemas", func(t *testing.T) {
+		body := map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"address": map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"city": map[string]any{"type": "string"}},
+				},
+			},
+		}
+		route := &echo.Route{Path: "/users", Method: "POST"}
+
+		schema := generateInputSchema(route, types.RegisteredSchemaInfo{BodySchema: body}, true, nil, true)
+
+		address := schema["properties"].(map[string]any)["address"].(map[string]any)
+		assert.Contains(t, address["properties"], "city")
+	})
+}
+
+func TestToolNames(t *testing.T) {
+	t.Run("Should replace dots and unicode in generated tool names", func(t *testing.T) {
+		routes := []*echo.Route{
+			{Path: "/files/résumé.pdf", Method: "GET"},
+			{Path: "/v1.2/ℕ/items", Method: "GET"},
+			{Path: "/api/v1/user-profiles/:profile_id/email@settings", Method: "PUT"},
+		}
+
+		_, operations := ConvertRoutesToTools(routes, nil, nil)
+
+		assert.Contains(t, operations, "GET_files_r_sum_pdf")
+		assert.Contains(t, operations, "GET_v12_items")
+		assert.Contains(t, operations, "PUT_api_v1_user-profiles_profile_id_email_settings")
+	})
+
+	t.Run("Should keep valid names unchanged", func(t *testing.T) {
+		assert.Equal(t, "GET_users-v2", SanitizeToolName("GET_users-v2"))
+	})
+
+	t.Run("Should shorten long names with a hash suffix", func(t *testing.T) {
+		long := strings.Repeat("a", 100)
+
+		name := LimitToolName(long, DefaultMaxToolNameLength)
+
+		assert.Len(t, name, DefaultMaxToolNameLength)
+		assert.NotEqual(t, name, LimitToolName(long+"b", DefaultMaxToolNameLength))
+		assert.Equal(t, "GET_users", LimitToolName("GET_users", DefaultMaxToolNameLength))
+	})
+
+	t.Run("Should honor a custom maximum length", func(t *testing.T) {
+		routes := []*echo.Route{{Path: "/organizations/:org_id/members", Method: "GET"}}
+
+		tools, operations := ConvertRoutesToToolsWithOptions(routes, nil, nil, Options{MaxToolNameLength: 20})
+
+		require.Len(t, tools, 1)
+		assert.Len(t, tools[0].Name, 20)
+		assert.Contains(t, operations, tools[0].Name)
+	})
+
+	t.Run("Should generate valid and unique names for unusual paths", func(t *testing.T) {
+		segments := []string{
+			"users", ":id", "*", "email@settings", "v1.2", "résumé", "日本語", "a b", "100%", "~tilde",
+			"semi;colon", "q?x=1", "hash#frag", "plus+sign", "user-profiles", "__", "ℕ", "😀",
+			strings.Repeat("very-long-segment", 5),
+		}
+		methods := []string{"GET", "POST", "PUT", "DELETE", "PATCH"}
+
+		var routes []*echo.Route
+		for i, first := range segments {
+			for j, second := range segments {
+				path := "/" + first + "/" + second
+				if (i+j)%3 == 0 {
+					path += "/" + strings.Repeat(first, 4)
+				}
+				routes = append(routes, &echo.Route{Path: path, Method: methods[(i+j)%len(methods)]})
+			}
+		}
+
+		tools, operations := ConvertRoutesToTools(routes, nil, nil)
+
+		pattern := regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+		names := make(map[string]bool, len(tools))
+		for _, tool := range tools {
+			assert.Regexp(t, pattern, tool.Name)
+			assert.False(t, names[tool.Name], "duplicate tool name %s", tool.Name)
+			names[tool.Name] = true
+			assert.Contains(t, operations, tool.Name)
+		}
+		assert.Len(t, operations, len(tools))
+	})
+}
+
+func TestFlattenBodySchema(t *testing.T) {
+	swaggerSpec := &swagger.SwaggerSpec{
+		Paths: map[string]swagger.SwaggerPath{
+			"/users/{id}": {
+				"put": swagger.SwaggerOperation{
+					Parameters: []swagger.SwaggerParameter{
+						{Name: "id", In: "path", Type: "string", Required: true},
+						{Name: "body", In: "body", Required: true, Schema: &swagger.SwaggerSchema{
+							Type:       "object",
+							Properties: map[string]*swagger.SwaggerSchema{"name": {Type: "string"}},
+							Required:   []string{"name"},
+						}},
+					},
+				},
+			},
+		},
+	}
+	routes := []*echo.Route{{Path: "/users/:id", Method: "PUT"}}
+
+	t.Run("Should nest swagger bodies by default", func(t *testing.T) {
+		tools, _ := ConvertRoutesToToolsWithOptions(routes, nil, swaggerSpec, Options{})
+
+		schema := tools[0].InputSchema.(map[string]any)
+		assert.Contains(t, schema["properties"], "body")
+	})
+
+	t.Run("Should hoist swagger body properties when enabled", func(t *testing.T) {
+		tools, _ := ConvertRoutesToToolsWithOptions(routes, nil, swaggerSpec, Options{FlattenBodySchema: true})
+
+		schema := tools[0].InputSchema.(map[string]any)
+		properties := schema["properties"].(map[string]any)
+		assert.NotContains(t, properties, "body")
+		assert.Contains(t, properties, "id")
+		assert.Contains(t, properties, "name")
+		assert.ElementsMatch(t, []string{"id", "name"}, schema["required"])
+	})
+}
+
+func TestToolNameAffixes(t *testing.T) {
+	t.Run("Should add the prefix and suffix to generated names", func(t *testing.T) {
+		routes := []*echo.Route{{Path: "/users", Method: "GET"}}
+
+		tools, operations := ConvertRoutesToToolsWithOptions(routes, nil, nil, Options{
+			ToolNamePrefix: "user_service_",
+			ToolNameSuffix: "_v2",
+		})
+
+		assert.Equal(t, "user_service_GET_users_v2", tools[0].Name)
+		assert.Contains(t, operations, "user_service_GET_users_v2")
+	})
+
+	t.Run("Should keep the prefix and suffix when shortening long names", func(t *testing.T) {
+		routes := []*echo.Route{{Path: "/" + strings.Repeat("segment/", 10), Method: "GET"}}
+
+		tools, _ := ConvertRoutesToToolsWithOptions(routes, nil, nil, Options{
+			ToolNamePrefix: "svc_",
+			ToolNameSuffix: "_v2",
+		})
+
+		assert.Len(t, tools[0].Name, DefaultMaxToolNameLength)
+		assert.True(t, strings.HasPrefix(tools[0].Name, "svc_GET_segment"), tools[0].Name)
+		assert.True(t, strings.HasSuffix(tools[0].Name, "_v2"), tools[0].Name)
+	})
+}
+
+func TestStaticParameters(t *testing.T) {
+	t.Run("Should hide static parameters and record them on the operation", func(t *testing.T) {
+		routes := []*echo.Route{{Path: "/reports", Method: "GET"}}
+		registered := map[string]types.RegisteredSchemaInfo{
+			"GET /reports": {
+				QuerySchema: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"year":        map[string]any{"type": "integer"},
+						"api-version": map[string]any{"type": "string"},
+					},
+					"required": []string{"api-version", "year"},
+				},
+				StaticQuery:   map[string]string{"api-version": "2024-01-01"},
+				StaticHeaders: map[string]string{"X-Source": "mcp"},
+			},
+		}
+
+		tools, operations := ConvertRoutesToTools(routes, registered, nil)
+
+		schema := tools[0].InputSchema.(map[string]any)
+		assert.Contains(t, schema["properties"], "year")
+		assert.NotContains(t, schema["properties"], "api-version")
+		assert.Equal(t, []string{"year"}, schema["required"])
+
+		operation := operations["GET_reports"]
+		assert.Equal(t, map[string]string{"api-version": "2024-01-01"}, operation.StaticQuery)
+		assert.Equal(t, map[string]string{"X-Source": "mcp"}, operation.StaticHeaders)
+	})
+}
+
+func TestGenericBody(t *testing.T) {
+	routes := []*echo.Route{{Path: "/jobs/:id/retry", Method: "POST"}}
+
+	t.Run("Should add a generic body by default", func(t *testing.T) {
+		tools, _ := ConvertRoutesToToolsWithOptions(routes, nil, nil, Options{})
+
+		assert.Contains(t, tools[0].InputSchema.(map[string]any)["properties"], "body")
+	})
+
+	t.Run("Should leave out the generic body when disabled", func(t *testing.T) {
+		tools, _ := ConvertRoutesToToolsWithOptions(routes, nil, nil, Options{NoGenericBody: true})
+
+		properties := tools[0].InputSchema.(map[string]any)["properties"].(map[string]any)
+		assert.NotContains(t, properties, "body")
+		assert.Contains(t, properties, "id")
+	})
+
+	t.Run("Should keep registered bodies when disabled", func(t *testing.T) {
+		registered := map[string]types.RegisteredSchemaInfo{
+			"POST /jobs/:id/retry": {BodySchema: struct {
+				Reason string `json:"reason"`
+			}{}},
+		}
+
+		tools, _ := ConvertRoutesToToolsWithOptions(routes, registered, nil, Options{NoGenericBody: true})
+
+		assert.Contains(t, tools[0].InputSchema.(map[string]any)["properties"], "reason")
+	})
+
+	t.Run("Should let routes override the option", func(t *testing.T) {
+		enabled, disabled := true, false
+
+		tools, _ := ConvertRoutesToToolsWithOptions(routes, map[string]types.RegisteredSchemaInfo{
+			"POST /jobs/:id/retry": {GenericBody: &enabled},
+		}, nil, Options{NoGenericBody: true})
+		assert.Contains(t, tools[0].InputSchema.(map[string]any)["properties"], "body")
+
+		tools, _ = ConvertRoutesToToolsWithOptions(routes, map[string]types.RegisteredSchemaInfo{
+			"POST /jobs/:id/retry": {GenericBody: &disabled},
+		}, nil, Options{})
+		assert.NotContains(t, tools[0].InputSchema.(map[string]any)["properties"], "body")
+	})
+}
+
+func TestDeleteBody(t *testing.T) {
+	routes := []*echo.Route{{Path: "/users", Method: "DELETE"}}
+
+	t.Run("Should not send DELETE bodies by default", func(t *testing.T) {
+		tools, operations := ConvertRoutesToToolsWithOptions(routes, nil, nil, Options{})
+
+		assert.NotContains(t, tools[0].InputSchema.(map[string]any)["properties"], "body")
+		assert.False(t, operations["DELETE_users"].HasBody)
+	})
+
+	t.Run("Should send DELETE bodies when allowed", func(t *testing.T) {
+		tools, operations := ConvertRoutesToToolsWithOptions(routes, nil, nil, Options{AllowDeleteBody: true})
+
+		assert.Contains(t, tools[0].InputSchema.(map[string]any)["properties"], "body")
+		assert.True(t, operations["DELETE_users"].HasBody)
+	})
+
+	t.Run("Should detect DELETE bodies from registered schemas", func(t *testing.T) {
+		registered := map[string]types.RegisteredSchemaInfo{
+			"DELETE /users": {BodySchema: struct {
+				IDs []int `json:"ids"`
+			}{}},
+		}
+
+		tools, operations := ConvertRoutesToToolsWithOptions(routes, registered, nil, Options{})
+
+		assert.Contains(t, tools[0].InputSchema.(map[string]any)["properties"], "ids")
+		assert.True(t, operations["DELETE_users"].HasBody)
+	})
+
+	t.Run("Should detect DELETE bodies from swagger body parameters", func(t *testing.T) {
+		swaggerSpec := &swagger.SwaggerSpec{
+			Paths: map[string]swagger.SwaggerPath{
+				"/users": {"delete": swagger.SwaggerOperation{
+					Parameters: []swagger.SwaggerParameter{{Name: "filter", In: "body", Schema: &swagger.SwaggerSchema{Type: "object"}}},
+				}},
+			},
+		}
+
+		tools, operations := ConvertRoutesToToolsWithOptions(routes, nil, swaggerSpec, Options{AllowDeleteBody: true})
+
+		properties := tools[0].InputSchema.(map[string]any)["properties"].(map[string]any)
+		assert.Equal(t, map[string]any{"type": "object"}, properties["body"])
+		assert.True(t, operations["DELETE_users"].HasBody)
+	})
+}
+
+// describedBody implements types.SchemaTitler and types.SchemaDescriber
+type describedBody struct {
+	Reason string `json:"reason"`
+}
+
+func (describedBody) SchemaTitle() string { return "Retry job" }
+
+func (describedBody) SchemaDescription() string { return "Retries a failed job" }
+
+func TestSchemaMetadataInInputSchema(t *testing.T) {
+	t.Run("Should surface the title and description of a registered body", func(t *testing.T) {
+		routes := []*echo.Route{{Path: "/jobs/:id/retry", Method: "POST"}}
+		registered := map[string]types.RegisteredSchemaInfo{
+			"POST /jobs/:id/retry": {BodySchema: describedBody{}},
+		}
+
+		tools, _ := ConvertRoutesToToolsWithOptions(routes, registered, nil, Options{})
+
+		schema := tools[0].InputSchema.(map[string]any)
+		assert.Equal(t, "Retry job", schema["title"])
+		assert.Equal(t, "Retries a failed job", schema["description"])
+		assert.Contains(t, schema["properties"], "id")
+		assert.Contains(t, schema["properties"], "reason")
+	})
+}
+
+func TestStrictSchemas(t *testing.T) {
+	swaggerSpec := &swagger.SwaggerSpec{
+		Paths: map[string]swagger.SwaggerPath{
+			"/users": {
+				"post": swagger.SwaggerOperation{
+					Parameters: []swagger.SwaggerParameter{
+						{Name: "body", In: "body", Schema: &swagger.SwaggerSchema{Ref: "#/definitions/User"}},
+					},
+				},
+			},
+		},
+		Definitions: map[string]*swagger.SwaggerSchema{
+			"User": {
+				Type:       "object",
+				Properties: map[string]*swagger.SwaggerSchema{"name": {Type: "string"}},
+			},
+		},
+	}
+	routes := []*echo.Route{{Path: "/users", Method: "POST"}}
+
+	t.Run("Should close swagger body schemas", func(t *testing.T) {
+		tools, _ := ConvertRoutesToToolsWithOptions(routes, nil, swaggerSpec, Options{StrictSchemas: true})
+
+		schema := tools[0].InputSchema.(map[string]any)
+		body := schema["properties"].(map[string]any)["body"].(map[string]any)
+		assert.Equal(t, false, schema["additionalProperties"])
+		assert.Equal(t, false, body["additionalProperties"])
+	})
+
+	t.Run("Should keep schemas open by default", func(t *testing.T) {
+		tools, _ := ConvertRoutesToToolsWithOptions(routes, nil, swaggerSpec, Options{})
+
+		assert.NotContains(t, tools[0].InputSchema, "additionalProperties")
+	})
+}
+
+func TestPropertyOrder(t *testing.T) {
+	type listQuery struct {
+		Verbose bool   `json:"verbose"`
+		Fields  string `json:"fields"`
+	}
+	type updateUserRequest struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+		Age   int    `json:"age"`
+	}
+
+	routes := []*echo.Route{{Path: "/orgs/:org/users/:id", Method: "PUT"}}
+	registered := map[string]types.RegisteredSchemaInfo{
+		"PUT /orgs/:org/users/:id": {QuerySchema: listQuery{}, BodySchema: updateUserRequest{}},
+	}
+
+	t.Run("Should order path parameters, then query parameters, then body fields", func(t *testing.T) {
+		tools, _ := ConvertRoutesToTools(routes, registered, nil)
+
+		assert.Equal(t, []string{"org", "id", "verbose", "fields", "name", "email", "age"}, tools[0].InputSchema.(map[string]any)[types.PropertyOrderKey])
+	})
+
+	t.Run("Should survive JSON round-tripping", func(t *testing.T) {
+		tools, _ := ConvertRoutesToTools(routes, registered, nil)
+
+		encoded, err := json.Marshal(tools[0].InputSchema)
+		require.NoError(t, err)
+		var decoded map[string]any
+		require.NoError(t, json.Unmarshal(encoded, &decoded))
+
+		assert.Equal(t, []string{"org", "id", "verbose", "fields", "name", "email", "age"}, types.PropertyOrder(decoded))
+	})
+
+	t.Run("Should order swagger parameters by location", func(t *testing.T) {
+		swaggerSpec := &swagger.SwaggerSpec{
+			Paths: map[string]swagger.SwaggerPath{
+				"/users/{id}": {
+					"put": swagger.SwaggerOperation{
+						Parameters: []swagger.SwaggerParameter{
+							{Name: "body", In: "body", Schema: &swagger.SwaggerSchema{Type: "object"}},
+							{Name: "dryRun", In: "query", Type: "boolean"},
+							{Name: "id", In: "path", Type: "string", Required: true},
+						},
+					},
+				},
+			},
+		}
+
+		tools, _ := ConvertRoutesToTools([]*echo.Route{{Path: "/users/:id", Method: "PUT"}}, nil, swaggerSpec)
+
+		assert.Equal(t, []string{"id", "dryRun", "body"}, tools[0].InputSchema.(map[string]any)[types.PropertyOrderKey])
+	})
+}
+
+func TestToolNameExtension(t *testing.T) {
+	swaggerSpec := &swagger.SwaggerSpec{
+		Paths: map[string]swagger.SwaggerPath{
+			"/users/{id}": {
+				"get": swagger.SwaggerOperation{
+					OperationID: "getUserByID",
+					Extensions:  map[string]any{swagger.ToolNameExtension: "fetch_user"},
+				},
+				"delete": swagger.SwaggerOperation{
+					Extensions: map[string]any{swagger.ToolNameExtension: "remove user!"},
+				},
+			},
+		},
+	}
+	routes := []*echo.Route{
+		{Path: "/users/:id", Method: "GET"},
+		{Path: "/users/:id", Method: "DELETE"},
+	}
+
+	t.Run("Should name tools after x-mcp-tool-name", func(t *testing.T) {
+		tools, operations := ConvertRoutesToToolsWithOptions(routes, nil, swaggerSpec, Options{
+			PreferSwaggerOperationID: true,
+			OperationIDTransform:     strings.ToUpper,
+		})
+
+		names := []string{tools[0].Name, tools[1].Name}
+		assert.Contains(t, names, "fetch_user")
+		assert.Equal(t, "GET", operations["fetch_user"].Method)
+	})
+
+	t.Run("Should ignore names MCP clients would reject", func(t *testing.T) {
+		_, operations := ConvertRoutesToTools(routes, nil, swaggerSpec)
+
+		assert.Contains(t, operations, "DELETE_users_id")
+	})
+}
+
+func TestRegisteredFormDataParameters(t *testing.T) {
+	type signupForm struct {
+		Email    string `form:"email" json:"email"`
+		Password string `form:"password" json:"password"`
+		Referrer string `query:"ref" json:"ref"`
+	}
+
+	t.Run("Should send form-tagged fields as form data on body methods", func(t *testing.T) {
+		routes := []*echo.Route{{Path: "/signup", Method: "POST"}}
+		registered := map[string]types.RegisteredSchemaInfo{"POST /signup": {QuerySchema: signupForm{}}}
+
+		_, operations := ConvertRoutesToTools(routes, registered, nil)
+
+		assert.Equal(t, []string{"email", "password"}, operations["POST_signup"].FormDataParams)
+		assert.Equal(t, []string{"ref"}, operations["POST_signup"].QueryParams)
+	})
+
+	t.Run("Should keep form-tagged fields in the query on other methods", func(t *testing.T) {
+		routes := []*echo.Route{{Path: "/signup", Method: "GET"}}
+		registered := map[string]types.RegisteredSchemaInfo{"GET /signup": {QuerySchema: signupForm{}}}
+
+		_, operations := ConvertRoutesToTools(routes, registered, nil)
+
+		assert.Empty(t, operations["GET_signup"].FormDataParams)
+		assert.Equal(t, []string{"email", "password", "ref"}, operations["GET_signup"].QueryParams)
+	})
+
+	t.Run("Should prefer swagger form data parameters", func(t *testing.T) {
+		routes := []*echo.Route{{Path: "/signup", Method: "POST"}}
+		registered := map[string]types.RegisteredSchemaInfo{"POST /signup": {QuerySchema: signupForm{}}}
+		swaggerSpec := &swagger.SwaggerSpec{
+			Paths: map[string]swagger.SwaggerPath{
+				"/signup": {"post": swagger.SwaggerOperation{
+					Parameters: []swagger.SwaggerParameter{{Name: "token", In: "formData", Type: "string"}},
+				}},
+			},
+		}
+
+		_, operations := ConvertRoutesToTools(routes, registered, swaggerSpec)
+
+		assert.Equal(t, []string{"token"}, operations["POST_signup"].FormDataParams)
+	})
+
+	t.Run("Should send body schemas made only of form fields as form data", func(t *testing.T) {
+		type loginForm struct {
+			Username string `form:"username" json:"username"`
+			Password string `form:"password" json:"password"`
+		}
+
+		routes := []*echo.Route{{Path: "/login", Method: "POST"}}
+		registered := map[string]types.RegisteredSchemaInfo{"POST /login": {BodySchema: loginForm{}}}
+
+		tools, operations := ConvertRoutesToTools(routes, registered, nil)
+
+		assert.Equal(t, []string{"password", "username"}, operations["POST_login"].FormDataParams)
+		assert.Contains(t, tools[0].Description, "Sends form-encoded data")
+
+		properties := tools[0].InputSchema.(map[string]any)["properties"].(map[string]any)
+		assert.Contains(t, properties, "username")
+		assert.Contains(t, properties, "password")
+		assert.NotContains(t, properties, "body")
+	})
+
+	t.Run("Should keep JSON bodies for body schemas with other fields", func(t *testing.T) {
+		routes := []*echo.Route{{Path: "/signup", Method: "POST"}}
+		registered := map[string]types.RegisteredSchemaInfo{"POST /signup": {BodySchema: signupForm{}}}
+
+		tools, operations := ConvertRoutesToTools(routes, registered, nil)
+
+		assert.Empty(t, operations["POST_signup"].FormDataParams)
+		assert.NotContains(t, tools[0].Description, "Sends form-encoded data")
+	})
+}
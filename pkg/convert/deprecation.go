@@ -0,0 +1,16 @@
+package convert
+
+import "github.com/BrunoKrugel/echo-mcp/pkg/types"
+
+// DeprecatedPrefix is prepended to the description of tools for deprecated operations.
+const DeprecatedPrefix = "[DEPRECATED] "
+
+// markDeprecated flags the tool as deprecated, prefixing its description and setting the deprecated annotation
+func markDeprecated(tool *types.Tool) {
+	tool.Deprecated = true
+	tool.Description = DeprecatedPrefix + tool.Description
+	if tool.Annotations == nil {
+		tool.Annotations = map[string]any{}
+	}
+	tool.Annotations["deprecated"] = true
+}
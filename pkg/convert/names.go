@@ -0,0 +1,118 @@
+package convert
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+)
+
+// DefaultMaxToolNameLength is the longest tool name accepted by common MCP clients.
+const DefaultMaxToolNameLength = 64
+
+// toolNameHashLength is the number of hex characters of the hash appended to shortened tool names
+const toolNameHashLength = 8
+
+// toolNameCharset matches names made only of the characters MCP clients accept
+var toolNameCharset = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// generateOperationID creates a unique operation ID for a route, some agents only support tools names that only contain [a-z0-9_-]
+func generateOperationID(method, path string) string {
+	// Convert path parameters to a consistent format
+	// /users/:id -> /users/{id}
+	normalizedPath := strings.ReplaceAll(path, ":", "")
+	normalizedPath = strings.ReplaceAll(normalizedPath, "*", "wildcard")
+	normalizedPath = strings.ReplaceAll(normalizedPath, "/", "_")
+	normalizedPath = strings.ReplaceAll(normalizedPath, ".", "")
+	normalizedPath = strings.ToLower(normalizedPath)
+	normalizedPath = strings.Trim(SanitizeToolName(normalizedPath), "_")
+
+	if normalizedPath == "" {
+		normalizedPath = "root"
+	}
+
+	return fmt.Sprintf("%s_%s", method, normalizedPath)
+}
+
+// SanitizeToolName replaces the characters MCP clients reject in tool names (anything but
+// ASCII letters, digits, underscores and dashes) with underscores, collapsing repeats.
+func SanitizeToolName(name string) string {
+	var sanitized strings.Builder
+	replaced := false
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '_' && r != '-' {
+			replaced = true
+			continue
+		}
+
+		// Collapse a run of rejected characters and its surrounding underscores into one underscore
+		if replaced {
+			replaced = false
+			if strings.HasSuffix(sanitized.String(), "_") {
+				if r == '_' {
+					continue
+				}
+			} else if r != '_' {
+				sanitized.WriteByte('_')
+			}
+		}
+		sanitized.WriteRune(r)
+	}
+	if replaced && !strings.HasSuffix(sanitized.String(), "_") {
+		sanitized.WriteByte('_')
+	}
+
+	return sanitized.String()
+}
+
+// LimitToolName shortens names longer than maxLength, replacing their tail with a hash
+// of the full name so distinct long names stay distinct.
+func LimitToolName(name string, maxLength int) string {
+	if len(name) <= maxLength {
+		return name
+	}
+
+	sum := sha256.Sum256([]byte(name))
+	hash := hex.EncodeToString(sum[:])[:toolNameHashLength]
+	if maxLength <= toolNameHashLength+1 {
+		return hash[:min(maxLength, len(hash))]
+	}
+
+	return name[:maxLength-toolNameHashLength-1] + "_" + hash
+}
+
+// affixToolName sanitizes a tool name and adds prefix and suffix around it, shortening the
+// name itself so the result stays within maxLength
+func affixToolName(name, prefix, suffix string, maxLength int) string {
+	prefix, suffix = SanitizeToolName(prefix), SanitizeToolName(suffix)
+	if prefix == "" && suffix == "" {
+		return LimitToolName(SanitizeToolName(name), maxLength)
+	}
+
+	available := max(maxLength-len(prefix)-len(suffix), 1)
+	return LimitToolName(prefix+LimitToolName(SanitizeToolName(name), available)+suffix, maxLength)
+}
+
+// IsValidToolName reports whether name only uses the characters MCP clients accept
+// and is at most maxLength characters long.
+func IsValidToolName(name string, maxLength int) bool {
+	return len(name) <= maxLength && toolNameCharset.MatchString(name)
+}
+
+// uniqueOperationID appends an incrementing suffix (_2, _3, ...) to operationID
+// if it is already used in the operations map, keeping the result within maxLength
+func uniqueOperationID(operationID string, operations map[string]types.Operation, maxLength int) string {
+	if _, exists := operations[operationID]; !exists {
+		return operationID
+	}
+
+	for i := 2; ; i++ {
+		candidate := LimitToolName(fmt.Sprintf("%s_%d", operationID, i), maxLength)
+		if _, exists := operations[candidate]; !exists {
+			return candidate
+		}
+	}
+}
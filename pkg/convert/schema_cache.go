@@ -0,0 +1,43 @@
+package convert
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+)
+
+// schemaCache holds the schemas generated by types.GetSchema for registered struct types,
+// keyed by their reflect.Type, so rebuilding the tools list does not repeat the reflection
+var schemaCache sync.Map
+
+// cachedSchema returns types.GetSchema(input), reusing the schema generated earlier for the
+// same struct type (T and *T share an entry). Generated schemas and nil values are not cached.
+// The returned schema is shared and must not be modified.
+func cachedSchema(input any) map[string]any {
+	if _, isSchema := input.(map[string]any); isSchema || input == nil {
+		return types.GetSchema(input)
+	}
+
+	typ := reflect.TypeOf(input)
+	if typ.Kind() == reflect.Pointer {
+		if reflect.ValueOf(input).IsNil() {
+			return types.GetSchema(input)
+		}
+		typ = typ.Elem()
+	}
+
+	if cached, ok := schemaCache.Load(typ); ok {
+		return cached.(map[string]any)
+	}
+
+	schema := types.GetSchema(input)
+	schemaCache.Store(typ, schema)
+	return schema
+}
+
+// FlushSchemaCache discards the schemas cached for registered struct types, so the next
+// conversion generates them again (e.g. after a SchemaTitler starts returning a new title).
+func FlushSchemaCache() {
+	schemaCache.Clear()
+}
@@ -0,0 +1,71 @@
+package convert
+
+import (
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+)
+
+// titleCalls counts the schemas generated for cachedRequest
+var titleCalls atomic.Int32
+
+type cachedRequest struct {
+	Name string `json:"name"`
+}
+
+func (cachedRequest) SchemaTitle() string {
+	titleCalls.Add(1)
+	return "Cached request"
+}
+
+func TestSchemaCache(t *testing.T) {
+	t.Cleanup(FlushSchemaCache)
+
+	routes := []*echo.Route{{Path: "/users", Method: "POST"}}
+	registered := map[string]types.RegisteredSchemaInfo{
+		"POST /users": {BodySchema: cachedRequest{}},
+	}
+
+	t.Run("Should generate a struct schema once across conversions", func(t *testing.T) {
+		FlushSchemaCache()
+		titleCalls.Store(0)
+
+		first, _ := ConvertRoutesToTools(routes, registered, nil)
+		second, _ := ConvertRoutesToTools(routes, registered, nil)
+
+		assert.Equal(t, int32(1), titleCalls.Load())
+		assert.Equal(t, first[0].InputSchema, second[0].InputSchema)
+	})
+
+	t.Run("Should share the entry between a type and its pointer", func(t *testing.T) {
+		FlushSchemaCache()
+
+		value := cachedSchema(cachedRequest{})
+		pointer := cachedSchema(&cachedRequest{})
+
+		assert.Equal(t, reflect.ValueOf(value).Pointer(), reflect.ValueOf(pointer).Pointer())
+	})
+
+	t.Run("Should generate schemas again after a flush", func(t *testing.T) {
+		FlushSchemaCache()
+		titleCalls.Store(0)
+
+		cachedSchema(cachedRequest{})
+		FlushSchemaCache()
+		cachedSchema(cachedRequest{})
+
+		assert.Equal(t, int32(2), titleCalls.Load())
+	})
+
+	t.Run("Should pass generated schemas and nil values through", func(t *testing.T) {
+		schema := map[string]any{"type": "object", "properties": map[string]any{}}
+
+		assert.Equal(t, schema, cachedSchema(schema))
+		assert.Equal(t, types.GetSchema(nil), cachedSchema((*cachedRequest)(nil)))
+	})
+}
@@ -0,0 +1,73 @@
+package swagger
+
+import (
+	"mime"
+	"slices"
+	"strings"
+)
+
+// IsXMLContentType reports whether contentType is an XML media type
+// (application/xml, text/xml or a +xml suffix such as application/soap+xml).
+func IsXMLContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml")
+}
+
+// IsJSONContentType reports whether contentType is a JSON media type
+// (application/json or a +json suffix such as application/problem+json).
+func IsJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// IsMultipartContentType reports whether contentType is multipart/form-data
+func IsMultipartContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "multipart/form-data"
+}
+
+// isURLEncodedContentType reports whether contentType is application/x-www-form-urlencoded
+func isURLEncodedContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/x-www-form-urlencoded"
+}
+
+// GetRequestContentType returns the media type the request body of an operation must be
+// sent as when it differs from the JSON and URL-encoded form defaults: the XML type from the
+// consumes list when the operation only accepts XML, or multipart/form-data when it accepts
+// multipart but not URL-encoded forms. It returns an empty string otherwise.
+func (spec *SwaggerSpec) GetRequestContentType(method, path string) string {
+	operation, exists := spec.GetOperation(method, path)
+	if !exists {
+		return ""
+	}
+
+	if slices.ContainsFunc(operation.Consumes, isJSONContentType) {
+		return ""
+	}
+	if index := slices.IndexFunc(operation.Consumes, IsXMLContentType); index >= 0 {
+		return operation.Consumes[index]
+	}
+	if slices.ContainsFunc(operation.Consumes, isURLEncodedContentType) {
+		return ""
+	}
+	if index := slices.IndexFunc(operation.Consumes, IsMultipartContentType); index >= 0 {
+		return operation.Consumes[index]
+	}
+	return ""
+}
+
+// isJSONContentType reports whether contentType is a JSON media type
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,51 @@
+package swagger
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ValidateRoutesCoverage returns the Echo routes ("METHOD /path") that have no
+// corresponding operation in the swagger spec, sorted for stable output.
+// It is useful in CI to enforce that every endpoint is documented.
+func (spec *SwaggerSpec) ValidateRoutesCoverage(routes []*echo.Route) []string {
+	var undocumented []string
+	for _, route := range routes {
+		if route.Method == "" || route.Path == "" || route.Method == echo.RouteNotFound {
+			continue
+		}
+
+		if _, exists := spec.GetOperation(route.Method, route.Path); !exists {
+			undocumented = append(undocumented, route.Method+" "+route.Path)
+		}
+	}
+
+	slices.Sort(undocumented)
+	return slices.Compact(undocumented)
+}
+
+// FindOrphanedSwaggerPaths returns the swagger operations ("METHOD /path") that have no
+// matching Echo route, sorted for stable output. This is the inverse of ValidateRoutesCoverage.
+// The Echo routes are required to know which operations are served.
+func (spec *SwaggerSpec) FindOrphanedSwaggerPaths(routes []*echo.Route) []string {
+	served := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		_, swaggerPath, _ := spec.lookupPath(route.Path)
+		served[strings.ToUpper(route.Method)+" "+swaggerPath] = true
+	}
+
+	var orphaned []string
+	for path, pathSpec := range spec.Paths {
+		for method := range pathSpec {
+			key := strings.ToUpper(method) + " " + path
+			if !served[key] {
+				orphaned = append(orphaned, key)
+			}
+		}
+	}
+
+	slices.Sort(orphaned)
+	return orphaned
+}
@@ -0,0 +1,53 @@
+package swagger
+
+import (
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutesCoverage(t *testing.T) {
+	spec := &SwaggerSpec{
+		Paths: map[string]SwaggerPath{
+			"/users": {
+				"get":  SwaggerOperation{},
+				"post": SwaggerOperation{},
+			},
+			"/users/{id}": {
+				"get": SwaggerOperation{},
+			},
+			"/legacy": {
+				"delete": SwaggerOperation{},
+			},
+		},
+	}
+	routes := []*echo.Route{
+		{Method: "GET", Path: "/users"},
+		{Method: "POST", Path: "/users"},
+		{Method: "GET", Path: "/users/:id"},
+		{Method: "PUT", Path: "/users/:id"},
+		{Method: "GET", Path: "/health"},
+		{Method: echo.RouteNotFound, Path: "/*"},
+	}
+
+	t.Run("Should list routes without swagger documentation", func(t *testing.T) {
+		assert.Equal(t, []string{"GET /health", "PUT /users/:id"}, spec.ValidateRoutesCoverage(routes))
+	})
+
+	t.Run("Should list swagger operations without an Echo route", func(t *testing.T) {
+		assert.Equal(t, []string{"DELETE /legacy"}, spec.FindOrphanedSwaggerPaths(routes))
+	})
+
+	t.Run("Should report nothing when fully covered", func(t *testing.T) {
+		covered := []*echo.Route{
+			{Method: "GET", Path: "/users"},
+			{Method: "POST", Path: "/users"},
+			{Method: "GET", Path: "/users/:id"},
+			{Method: "DELETE", Path: "/legacy"},
+		}
+
+		assert.Empty(t, spec.ValidateRoutesCoverage(covered))
+		assert.Empty(t, spec.FindOrphanedSwaggerPaths(covered))
+	})
+}
@@ -1,13 +1,18 @@
 package swagger
 
-import "strings"
+import (
+	"maps"
+	"slices"
+	"strings"
+)
 
 type OpenAPISpec struct {
-	Paths      map[string]PathItem `yaml:"paths"`
-	Components Components          `yaml:"components"`
-	Info       Info                `yaml:"info"`
-	OpenAPI    string              `yaml:"openapi"`
-	Servers    []Server            `yaml:"servers"`
+	Paths      map[string]PathItem   `yaml:"paths"`
+	Components Components            `yaml:"components"`
+	Info       Info                  `yaml:"info"`
+	OpenAPI    string                `yaml:"openapi"`
+	Servers    []Server              `yaml:"servers"`
+	Security   []SecurityRequirement `yaml:"security"`
 }
 
 type Info struct {
@@ -24,11 +29,16 @@ type Server struct {
 type PathItem map[string]Operation
 
 type Operation struct {
-	RequestBody *RequestBody        `yaml:"requestBody,omitempty"`
-	Responses   map[string]Response `yaml:"responses"`
-	Description string              `yaml:"description"`
-	Tags        []string            `yaml:"tags"`
-	Parameters  []Parameter         `yaml:"parameters,omitempty"`
+	RequestBody *RequestBody          `yaml:"requestBody,omitempty"`
+	Responses   map[string]Response   `yaml:"responses"`
+	Extra       map[string]any        `yaml:",inline"`
+	XMCPTimeout any                   `yaml:"x-mcp-timeout,omitempty"`
+	Description string                `yaml:"description"`
+	OperationID string                `yaml:"operationId"`
+	Tags        []string              `yaml:"tags"`
+	Parameters  []Parameter           `yaml:"parameters,omitempty"`
+	Security    []SecurityRequirement `yaml:"security,omitempty"`
+	Deprecated  bool                  `yaml:"deprecated,omitempty"`
 }
 
 type Parameter struct {
@@ -36,11 +46,17 @@ type Parameter struct {
 	In       string          `yaml:"in"`
 	Name     string          `yaml:"name"`
 	Required bool            `yaml:"required"`
+	RawPath  bool            `yaml:"x-mcp-raw-path,omitempty"`
 }
 
 type ParameterSchema struct {
-	Type    string `yaml:"type"`
-	Example string `yaml:"example,omitempty"`
+	Items   *Schema `yaml:"items,omitempty"`
+	Default any     `yaml:"default,omitempty"`
+	Type    string  `yaml:"type"`
+	Format  string  `yaml:"format,omitempty"`
+	Example string  `yaml:"example,omitempty"`
+	Ref     string  `yaml:"$ref,omitempty"`
+	Enum    []any   `yaml:"enum,omitempty"`
 }
 
 type Response struct {
@@ -54,18 +70,38 @@ type MediaType struct {
 
 type Schema struct {
 	Properties map[string]SchemaProperty `yaml:"properties,omitempty"`
+	Items      *Schema                   `yaml:"items,omitempty"`
 	Ref        string                    `yaml:"$ref,omitempty"`
 	Type       string                    `yaml:"type,omitempty"`
+	Required   []string                  `yaml:"required,omitempty"`
+	AllOf      []Schema                  `yaml:"allOf,omitempty"`
+	AnyOf      []Schema                  `yaml:"anyOf,omitempty"`
+	OneOf      []Schema                  `yaml:"oneOf,omitempty"`
+	Nullable   bool                      `yaml:"nullable,omitempty"`
 }
 
 type SchemaProperty struct {
-	Type    string `yaml:"type,omitempty"`
-	Example string `yaml:"example,omitempty"`
-	Ref     string `yaml:"$ref,omitempty"`
+	Items    *Schema `yaml:"items,omitempty"`
+	Default  any     `yaml:"default,omitempty"`
+	Type     string  `yaml:"type,omitempty"`
+	Format   string  `yaml:"format,omitempty"`
+	Example  string  `yaml:"example,omitempty"`
+	Ref      string  `yaml:"$ref,omitempty"`
+	Enum     []any   `yaml:"enum,omitempty"`
+	Nullable bool    `yaml:"nullable,omitempty"`
 }
 
 type Components struct {
-	Schemas map[string]Schema `yaml:"schemas"`
+	Schemas         map[string]Schema         `yaml:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `yaml:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type        string `yaml:"type"`
+	Name        string `yaml:"name"`
+	In          string `yaml:"in"`
+	Scheme      string `yaml:"scheme"`
+	Description string `yaml:"description"`
 }
 
 type RequestBody struct {
@@ -80,8 +116,21 @@ func (o *OpenAPISpec) ToSwaggerSpec() *SwaggerSpec {
 			Description: o.Info.Description,
 			Version:     o.Info.Version,
 		},
-		Paths:       map[string]SwaggerPath{},
-		Definitions: map[string]*SwaggerSchema{},
+		Paths:               map[string]SwaggerPath{},
+		Definitions:         map[string]*SwaggerSchema{},
+		SecurityDefinitions: map[string]*SwaggerSecurityScheme{},
+		Security:            o.Security,
+	}
+
+	// Convert security schemes
+	for name, scheme := range o.Components.SecuritySchemes {
+		spec.SecurityDefinitions[name] = &SwaggerSecurityScheme{
+			Type:        scheme.Type,
+			Name:        scheme.Name,
+			In:          scheme.In,
+			Scheme:      scheme.Scheme,
+			Description: scheme.Description,
+		}
 	}
 
 	// Convert schemas
@@ -107,23 +156,39 @@ func convertOperation(op *Operation) SwaggerOperation {
 	operation := SwaggerOperation{
 		Summary:     op.Description,
 		Description: op.Description,
+		OperationID: op.OperationID,
 		Tags:        op.Tags,
+		Security:    op.Security,
+		Deprecated:  op.Deprecated,
+		XMCPTimeout: op.XMCPTimeout,
+		Extensions:  extensions(op.Extra),
 		Responses:   map[string]SwaggerResponse{},
 	}
 
 	// Parameters
 	for _, p := range op.Parameters {
-		operation.Parameters = append(operation.Parameters, SwaggerParameter{
+		param := SwaggerParameter{
 			Name:     p.Name,
 			In:       p.In,
 			Type:     p.Schema.Type,
+			Format:   p.Schema.Format,
+			Items:    convertItems(p.Schema.Type, p.Schema.Items),
 			Required: p.Required,
-		})
+			Default:  p.Schema.Default,
+			Example:  convertExample(p.Schema.Example),
+			Enum:     p.Schema.Enum,
+			RawPath:  p.RawPath,
+		}
+		if p.Schema.Ref != "" {
+			param.Schema = &SwaggerSchema{Ref: convertRef(p.Schema.Ref)}
+		}
+		operation.Parameters = append(operation.Parameters, param)
 	}
 
-	// Request body → body parameter
+	// Request body → body parameter, preferring JSON over XML content
 	if op.RequestBody != nil {
-		if mt, ok := op.RequestBody.Content["application/json"]; ok {
+		operation.Consumes = slices.Sorted(maps.Keys(op.RequestBody.Content))
+		if mt, ok := jsonOrXMLContent(op.RequestBody.Content); ok {
 			operation.Parameters = append(operation.Parameters, SwaggerParameter{
 				Name:     "body",
 				In:       "body",
@@ -139,19 +204,37 @@ func convertOperation(op *Operation) SwaggerOperation {
 			Description: resp.Description,
 		}
 
-		if mt, ok := resp.Content["application/json"]; ok {
+		if mt, ok := jsonOrXMLContent(resp.Content); ok {
 			swaggerResp.Schema = convertSchema(mt.Schema)
 		}
+		for contentType := range resp.Content {
+			if !slices.Contains(operation.Produces, contentType) {
+				operation.Produces = append(operation.Produces, contentType)
+			}
+		}
 
 		operation.Responses[code] = swaggerResp
 	}
+	slices.Sort(operation.Produces)
 
 	return operation
 }
 
+// jsonOrXMLContent returns the JSON media type of an OpenAPI 3.0 content map,
+// falling back to the XML one for endpoints that only exchange XML
+func jsonOrXMLContent(content map[string]MediaType) (MediaType, bool) {
+	for _, contentType := range []string{"application/json", "application/xml", "text/xml"} {
+		if mt, ok := content[contentType]; ok {
+			return mt, true
+		}
+	}
+	return MediaType{}, false
+}
+
 func convertSchema(s Schema) *SwaggerSchema {
 	sw := &SwaggerSchema{
-		Type: s.Type,
+		Type:     s.Type,
+		Nullable: s.Nullable,
 	}
 
 	if s.Ref != "" {
@@ -166,17 +249,49 @@ func convertSchema(s Schema) *SwaggerSchema {
 		}
 	}
 
+	sw.Items = convertItems(s.Type, s.Items)
+	sw.Required = s.Required
+	sw.AllOf = convertSchemas(s.AllOf)
+	sw.AnyOf = convertSchemas(s.AnyOf)
+	sw.OneOf = convertSchemas(s.OneOf)
+
 	return sw
 }
 
+func convertSchemas(schemas []Schema) []*SwaggerSchema {
+	if len(schemas) == 0 {
+		return nil
+	}
+
+	converted := make([]*SwaggerSchema, 0, len(schemas))
+	for _, s := range schemas {
+		converted = append(converted, convertSchema(s))
+	}
+	return converted
+}
+
 func convertSchemaProperty(prop SchemaProperty) *SwaggerSchema {
 	sw := &SwaggerSchema{
-		Type: prop.Type,
-		Ref:  convertRef(prop.Ref),
+		Type:     prop.Type,
+		Format:   prop.Format,
+		Ref:      convertRef(prop.Ref),
+		Items:    convertItems(prop.Type, prop.Items),
+		Default:  prop.Default,
+		Example:  convertExample(prop.Example),
+		Enum:     prop.Enum,
+		Nullable: prop.Nullable,
 	}
 	return sw
 }
 
+// convertExample returns nil for an empty example so it is omitted from the schema
+func convertExample(example string) any {
+	if example == "" {
+		return nil
+	}
+	return example
+}
+
 func convertRef(ref string) string {
 	// "#/components/schemas/User" → "#/definitions/User"
 	return strings.Replace(ref, "#/components/schemas/", "#/definitions/", 1)
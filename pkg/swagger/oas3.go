@@ -0,0 +1,12 @@
+package swagger
+
+// convertItems converts the items schema of an OpenAPI 3.0 array.
+// OAS3 uses the JSON Schema items keyword, so the items schema is converted
+// recursively (including $ref and nested arrays); non-array schemas have no items.
+func convertItems(schemaType string, items *Schema) *SwaggerSchema {
+	if schemaType != "array" || items == nil {
+		return nil
+	}
+
+	return convertSchema(*items)
+}
@@ -0,0 +1,104 @@
+package swagger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"gopkg.in/yaml.v3"
+)
+
+// remoteSpecTimeout bounds how long GetSwaggerSpecFromURL waits for the spec
+const remoteSpecTimeout = 10 * time.Second
+
+// remoteSpecs caches the specifications fetched by GetSwaggerSpecFromURL by URL
+var remoteSpecs struct {
+	specs map[string]*SwaggerSpec
+	mu    sync.Mutex
+}
+
+// GetSwaggerSpecFromURL fetches the specification served at specURL (e.g.
+// "http://localhost:8080/swagger/doc.json") with a GET request and parses it. Swagger 2.0
+// documents must be JSON; OpenAPI 3 documents may be JSON or YAML. Successfully parsed
+// specifications are cached by URL until ResetSwaggerCache; the returned spec is shared
+// and must not be modified.
+func GetSwaggerSpecFromURL(specURL string) (*SwaggerSpec, error) {
+	remoteSpecs.mu.Lock()
+	defer remoteSpecs.mu.Unlock()
+
+	if spec, cached := remoteSpecs.specs[specURL]; cached {
+		return spec, nil
+	}
+
+	data, err := fetchSpec(specURL)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := parseSpecDocument(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse swagger spec from %s: %w", specURL, err)
+	}
+
+	if remoteSpecs.specs == nil {
+		remoteSpecs.specs = make(map[string]*SwaggerSpec)
+	}
+	remoteSpecs.specs[specURL] = spec
+
+	return spec, nil
+}
+
+// fetchSpec downloads the specification document served at specURL
+func fetchSpec(specURL string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteSpecTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, specURL, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("invalid swagger spec URL %s: %w", specURL, err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch swagger spec from %s: %w", specURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch swagger spec from %s: status %d", specURL, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read swagger spec from %s: %w", specURL, err)
+	}
+	return data, nil
+}
+
+// parseSpecDocument parses a Swagger 2.0 JSON document or an OpenAPI 3 JSON or YAML document
+func parseSpecDocument(data []byte) (*SwaggerSpec, error) {
+	var version struct {
+		OpenAPI string `yaml:"openapi"`
+	}
+	if err := yaml.Unmarshal(data, &version); err != nil {
+		return nil, err
+	}
+
+	if version.OpenAPI != "" {
+		var openAPI OpenAPISpec
+		if err := yaml.Unmarshal(data, &openAPI); err != nil {
+			return nil, err
+		}
+		return openAPI.ToSwaggerSpec(), nil
+	}
+
+	var spec SwaggerSpec
+	if err := sonic.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
@@ -0,0 +1,86 @@
+package swagger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSwaggerSpecFromURL(t *testing.T) {
+	newServer := func(t *testing.T, status int, doc string) (*httptest.Server, *atomic.Int32) {
+		t.Helper()
+
+		var requests atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(doc))
+		}))
+		t.Cleanup(srv.Close)
+		t.Cleanup(ResetSwaggerCache)
+		return srv, &requests
+	}
+
+	t.Run("Should fetch, parse and cache a swagger document", func(t *testing.T) {
+		srv, requests := newServer(t, http.StatusOK, `{
+			"swagger": "2.0",
+			"info": {"title": "Users API"},
+			"paths": {"/users/{id}": {"get": {"operationId": "getUser"}}}
+		}`)
+
+		spec, err := GetSwaggerSpecFromURL(srv.URL + "/swagger.json")
+		require.NoError(t, err)
+		assert.Equal(t, "Users API", spec.Info.Title)
+		assert.Equal(t, "getUser", spec.GetOperationID("GET", "/users/:id"))
+
+		cached, err := GetSwaggerSpecFromURL(srv.URL + "/swagger.json")
+		require.NoError(t, err)
+		assert.Same(t, spec, cached)
+		assert.Equal(t, int32(1), requests.Load())
+
+		ResetSwaggerCache()
+		_, err = GetSwaggerSpecFromURL(srv.URL + "/swagger.json")
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), requests.Load())
+	})
+
+	t.Run("Should parse OpenAPI 3 YAML documents", func(t *testing.T) {
+		srv, _ := newServer(t, http.StatusOK, `
+openapi: 3.0.3
+info:
+  title: Orders API
+  version: 1.0.0
+paths:
+  /orders:
+    get:
+      operationId: listOrders
+`)
+
+		spec, err := GetSwaggerSpecFromURL(srv.URL + "/openapi.yaml")
+		require.NoError(t, err)
+		assert.Equal(t, "Orders API", spec.Info.Title)
+		assert.Equal(t, "listOrders", spec.GetOperationID("GET", "/orders"))
+	})
+
+	t.Run("Should return an error for unsuccessful responses without caching it", func(t *testing.T) {
+		srv, requests := newServer(t, http.StatusNotFound, "not found")
+
+		_, err := GetSwaggerSpecFromURL(srv.URL)
+		require.ErrorContains(t, err, "status 404")
+
+		_, err = GetSwaggerSpecFromURL(srv.URL)
+		require.Error(t, err)
+		assert.Equal(t, int32(2), requests.Load())
+	})
+
+	t.Run("Should return an error for invalid documents", func(t *testing.T) {
+		srv, _ := newServer(t, http.StatusOK, `{"swagger": "2.0", "paths": {`)
+
+		_, err := GetSwaggerSpecFromURL(srv.URL)
+		assert.ErrorContains(t, err, "failed to parse swagger spec")
+	})
+}
@@ -0,0 +1,123 @@
+package swagger
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DescribeErrorResponses returns one "Error shape: ..." line per distinct 4xx response
+// schema of an operation, or an empty string if the operation documents none.
+// When the 4xx responses use different shapes, the status codes are listed per line.
+func (spec *SwaggerSpec) DescribeErrorResponses(method, path string) string {
+	operation, exists := spec.GetOperation(method, path)
+	if !exists {
+		return ""
+	}
+
+	codes := make([]string, 0, len(operation.Responses))
+	for code, response := range operation.Responses {
+		if strings.HasPrefix(code, "4") && response.Schema != nil {
+			codes = append(codes, code)
+		}
+	}
+	slices.Sort(codes)
+
+	// Group status codes by the shape they return, keeping first-seen order
+	var shapes []string
+	codesByShape := make(map[string][]string)
+	for _, code := range codes {
+		shape := formatShape(spec.convertSwaggerSchemaToMCP(operation.Responses[code].Schema))
+		if _, seen := codesByShape[shape]; !seen {
+			shapes = append(shapes, shape)
+		}
+		codesByShape[shape] = append(codesByShape[shape], code)
+	}
+
+	lines := make([]string, 0, len(shapes))
+	for _, shape := range shapes {
+		if len(shapes) == 1 {
+			lines = append(lines, "Error shape: "+shape)
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("Error shape (%s): %s", strings.Join(codesByShape[shape], ", "), shape))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// GetResponseSchema returns the converted schema of the success response of an operation
+// (200, or the lowest documented 2xx code), or nil if the operation documents none.
+func (spec *SwaggerSpec) GetResponseSchema(method, path string) any {
+	operation, exists := spec.GetOperation(method, path)
+	if !exists {
+		return nil
+	}
+
+	codes := make([]string, 0, len(operation.Responses))
+	for code, response := range operation.Responses {
+		if strings.HasPrefix(code, "2") && response.Schema != nil {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	slices.Sort(codes)
+
+	return spec.convertSwaggerSchemaToMCP(operation.Responses[codes[0]].Schema)
+}
+
+// formatShape renders a converted schema as a compact {field: type} summary
+func formatShape(schema any) string {
+	schemaMap, ok := schema.(map[string]any)
+	if !ok {
+		return "object"
+	}
+
+	properties, ok := schemaMap["properties"].(map[string]any)
+	if !ok || len(properties) == 0 {
+		if schemaType := shapeType(schemaMap["type"]); schemaType != "" {
+			return schemaType
+		}
+		return "object"
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	fields := make([]string, 0, len(names))
+	for _, name := range names {
+		fieldType := "any"
+		if property, ok := properties[name].(map[string]any); ok {
+			if propertyType := shapeType(property["type"]); propertyType != "" {
+				fieldType = propertyType
+			}
+		}
+		fields = append(fields, name+": "+fieldType)
+	}
+
+	return "{" + strings.Join(fields, ", ") + "}"
+}
+
+// shapeType renders a schema type, joining nullable type unions such as ["string", "null"]
+// as "string|null", or returns an empty string when the type is missing
+func shapeType(schemaType any) string {
+	switch schemaType := schemaType.(type) {
+	case string:
+		return schemaType
+	case []any:
+		types := make([]string, 0, len(schemaType))
+		for _, member := range schemaType {
+			if name, ok := member.(string); ok {
+				types = append(types, name)
+			}
+		}
+		return strings.Join(types, "|")
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,142 @@
+package swagger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribeErrorResponses(t *testing.T) {
+	spec := &SwaggerSpec{
+		Definitions: map[string]*SwaggerSchema{
+			"main.AppError": {
+				Type:       "object",
+				Properties: map[string]*SwaggerSchema{"error": {Type: "string"}},
+			},
+			"main.ValidationError": {
+				Type: "object",
+				Properties: map[string]*SwaggerSchema{
+					"error":  {Type: "string"},
+					"fields": {Type: "array"},
+				},
+			},
+		},
+		Paths: map[string]SwaggerPath{
+			"/users/{id}": {
+				"get": SwaggerOperation{
+					Responses: map[string]SwaggerResponse{
+						"200": {Schema: &SwaggerSchema{Type: "object"}},
+						"400": {Schema: &SwaggerSchema{Ref: "#/definitions/main.AppError"}},
+						"404": {Schema: &SwaggerSchema{Ref: "#/definitions/main.AppError"}},
+						"500": {Schema: &SwaggerSchema{Ref: "#/definitions/main.AppError"}},
+					},
+				},
+				"put": SwaggerOperation{
+					Responses: map[string]SwaggerResponse{
+						"404": {Schema: &SwaggerSchema{Ref: "#/definitions/main.AppError"}},
+						"422": {Schema: &SwaggerSchema{Ref: "#/definitions/main.ValidationError"}},
+					},
+				},
+				"delete": SwaggerOperation{
+					Responses: map[string]SwaggerResponse{
+						"204": {Description: "No Content"},
+						"404": {Description: "Not Found"},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("Should describe a shared error shape once", func(t *testing.T) {
+		assert.Equal(t, "Error shape: {error: string}", spec.DescribeErrorResponses("GET", "/users/:id"))
+	})
+
+	t.Run("Should list status codes for distinct error shapes", func(t *testing.T) {
+		assert.Equal(t,
+			"Error shape (404): {error: string}\nError shape (422): {error: string, fields: array}",
+			spec.DescribeErrorResponses("PUT", "/users/:id"),
+		)
+	})
+
+	t.Run("Should return empty string without error schemas", func(t *testing.T) {
+		assert.Empty(t, spec.DescribeErrorResponses("DELETE", "/users/:id"))
+		assert.Empty(t, spec.DescribeErrorResponses("GET", "/missing"))
+	})
+}
+
+func TestParameterFormat(t *testing.T) {
+	t.Run("Should propagate parameter format into the property schema", func(t *testing.T) {
+		spec := &SwaggerSpec{
+			Paths: map[string]SwaggerPath{
+				"/users/{id}": {
+					"get": SwaggerOperation{
+						Parameters: []SwaggerParameter{
+							{Name: "id", In: "path", Type: "string", Format: "uuid", Required: true},
+						},
+					},
+				},
+			},
+		}
+
+		schema, err := spec.GetOperationSchema("GET", "/users/:id")
+		assert.NoError(t, err)
+
+		id := schema["properties"].(map[string]any)["id"].(map[string]any)
+		assert.Equal(t, "uuid", id["format"])
+	})
+}
+
+func TestGetResponseSchema(t *testing.T) {
+	spec := &SwaggerSpec{
+		Definitions: map[string]*SwaggerSchema{
+			"main.User": {
+				Type:       "object",
+				Properties: map[string]*SwaggerSchema{"id": {Type: "integer"}, "name": {Type: "string"}},
+			},
+		},
+		Paths: map[string]SwaggerPath{
+			"/users/{id}": {
+				"get": SwaggerOperation{
+					Responses: map[string]SwaggerResponse{
+						"200": {Schema: &SwaggerSchema{Ref: "#/definitions/main.User"}},
+						"404": {Schema: &SwaggerSchema{Type: "object"}},
+					},
+				},
+				"delete": SwaggerOperation{
+					Responses: map[string]SwaggerResponse{
+						"204": {Description: "No Content"},
+					},
+				},
+			},
+			"/users": {
+				"post": SwaggerOperation{
+					Responses: map[string]SwaggerResponse{
+						"202": {Schema: &SwaggerSchema{Type: "string"}},
+						"201": {Schema: &SwaggerSchema{Ref: "#/definitions/main.User"}},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("Should resolve the 200 response schema", func(t *testing.T) {
+		schema, ok := spec.GetResponseSchema("GET", "/users/:id").(map[string]any)
+
+		assert.True(t, ok)
+		assert.Equal(t, "object", schema["type"])
+		assert.Contains(t, schema["properties"], "id")
+		assert.Contains(t, schema["properties"], "name")
+	})
+
+	t.Run("Should use the lowest 2xx response with a schema", func(t *testing.T) {
+		schema, ok := spec.GetResponseSchema("POST", "/users").(map[string]any)
+
+		assert.True(t, ok)
+		assert.Contains(t, schema["properties"], "id")
+	})
+
+	t.Run("Should return nil without a success schema", func(t *testing.T) {
+		assert.Nil(t, spec.GetResponseSchema("DELETE", "/users/:id"))
+		assert.Nil(t, spec.GetResponseSchema("GET", "/missing"))
+	})
+}
@@ -0,0 +1,116 @@
+package swagger
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SwaggerSecurityScheme describes a security scheme from securityDefinitions (Swagger 2.0)
+// or components.securitySchemes (OpenAPI 3.0)
+type SwaggerSecurityScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Scheme      string `json:"scheme"`
+	Description string `json:"description"`
+}
+
+// SecurityRequirement maps security scheme names to their required scopes
+type SecurityRequirement map[string][]string
+
+// SecurityParameter is a credential an operation expects in a header or query parameter
+type SecurityParameter struct {
+	Name        string
+	In          string
+	Description string
+}
+
+// GetSecurityParameters returns the credentials required by an operation, derived from its
+// security requirements (or the global ones when the operation declares none).
+// apiKey schemes map to their header or query parameter; basic, http and oauth2 schemes
+// map to the Authorization header.
+func (spec *SwaggerSpec) GetSecurityParameters(method, path string) []SecurityParameter {
+	pathSpec, _, exists := spec.lookupPath(path)
+	if !exists {
+		return nil
+	}
+
+	operation, exists := pathSpec[strings.ToLower(method)]
+	if !exists {
+		return nil
+	}
+
+	requirements := operation.Security
+	if requirements == nil {
+		requirements = spec.Security
+	}
+
+	var params []SecurityParameter
+	for _, requirement := range requirements {
+		names := make([]string, 0, len(requirement))
+		for name := range requirement {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+
+		for _, name := range names {
+			scheme, ok := spec.SecurityDefinitions[name]
+			if !ok || scheme == nil {
+				continue
+			}
+
+			param, ok := securityParameter(name, scheme)
+			if !ok {
+				continue
+			}
+
+			if !slices.ContainsFunc(params, func(p SecurityParameter) bool {
+				return p.Name == param.Name && p.In == param.In
+			}) {
+				params = append(params, param)
+			}
+		}
+	}
+
+	return params
+}
+
+// securityParameter maps a security scheme to the parameter that carries its credential
+func securityParameter(name string, scheme *SwaggerSecurityScheme) (SecurityParameter, bool) {
+	description := scheme.Description
+
+	switch strings.ToLower(scheme.Type) {
+	case "apikey":
+		if scheme.Name == "" || (scheme.In != "header" && scheme.In != "query") {
+			return SecurityParameter{}, false
+		}
+		if description == "" {
+			description = fmt.Sprintf("API key for the %s security scheme", name)
+		}
+		return SecurityParameter{Name: scheme.Name, In: scheme.In, Description: description}, true
+	case "basic", "http", "oauth2", "openidconnect":
+		if description == "" {
+			description = fmt.Sprintf("Authorization header for the %s security scheme", name)
+		}
+		return SecurityParameter{Name: "Authorization", In: "header", Description: description}, true
+	default:
+		return SecurityParameter{}, false
+	}
+}
+
+// GetAPIKeyHeaders returns the sorted names of the headers carrying the credentials of apiKey
+// security schemes
+func (spec *SwaggerSpec) GetAPIKeyHeaders() []string {
+	var headers []string
+	for _, scheme := range spec.SecurityDefinitions {
+		if scheme == nil || !strings.EqualFold(scheme.Type, "apikey") || scheme.In != "header" || scheme.Name == "" {
+			continue
+		}
+		if !slices.Contains(headers, scheme.Name) {
+			headers = append(headers, scheme.Name)
+		}
+	}
+	slices.Sort(headers)
+	return headers
+}
@@ -6,19 +6,34 @@ package swagger
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"net/url"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
 	"github.com/bytedance/sonic"
 	"github.com/swaggo/swag"
 	"gopkg.in/yaml.v3"
 )
 
 type SwaggerSpec struct {
-	Paths       map[string]SwaggerPath    `json:"paths"`
-	Definitions map[string]*SwaggerSchema `json:"definitions"`
-	Info        *SwaggerInfo              `json:"info"`
-	Swagger     string                    `json:"swagger"`
+	Paths               map[string]SwaggerPath            `json:"paths"`
+	Definitions         map[string]*SwaggerSchema         `json:"definitions"`
+	SecurityDefinitions map[string]*SwaggerSecurityScheme `json:"securityDefinitions"`
+	Info                *SwaggerInfo                      `json:"info"`
+	Swagger             string                            `json:"swagger"`
+	BasePath            string                            `json:"basePath"`
+	Host                string                            `json:"host"`
+	Schemes             []string                          `json:"schemes"`
+	Security            []SecurityRequirement             `json:"security"`
+	// NullableKeyword emits "nullable": true for nullable schemas instead of a ["type", "null"]
+	// type union, for clients validating against OpenAPI rather than JSON Schema drafts
+	NullableKeyword bool `json:"-"`
 }
 
 type SwaggerInfo struct {
@@ -31,19 +46,71 @@ type SwaggerPath map[string]SwaggerOperation
 
 type SwaggerOperation struct {
 	Responses   map[string]SwaggerResponse `json:"responses"`
+	Extensions  map[string]any             `json:"-"`
+	XMCPTimeout any                        `json:"x-mcp-timeout,omitempty"`
 	Summary     string                     `json:"summary"`
 	Description string                     `json:"description"`
+	OperationID string                     `json:"operationId"`
 	Tags        []string                   `json:"tags"`
+	Consumes    []string                   `json:"consumes"`
+	Produces    []string                   `json:"produces"`
 	Parameters  []SwaggerParameter         `json:"parameters"`
+	Security    []SecurityRequirement      `json:"security"`
+	Deprecated  bool                       `json:"deprecated"`
+}
+
+const (
+	// ToolNameExtension is the swagger operation extension overriding the generated tool name
+	ToolNameExtension = "x-mcp-tool-name"
+	// ExcludeExtension is the swagger operation extension hiding an operation from MCP when true
+	ExcludeExtension = "x-mcp-exclude"
+	// RawPathExtension is the swagger path parameter extension keeping slashes in the value unescaped when true
+	RawPathExtension = "x-mcp-raw-path"
+)
+
+// UnmarshalJSON decodes a swagger operation and collects its vendor extensions (x-* keys)
+func (op *SwaggerOperation) UnmarshalJSON(data []byte) error {
+	type plainOperation SwaggerOperation
+	if err := sonic.Unmarshal(data, (*plainOperation)(op)); err != nil {
+		return err
+	}
+
+	var raw map[string]any
+	if err := sonic.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	op.Extensions = extensions(raw)
+	return nil
+}
+
+// extensions returns the vendor extensions (x-* keys) of a decoded object, or nil if it has none
+func extensions(raw map[string]any) map[string]any {
+	var found map[string]any
+	for key, value := range raw {
+		if !strings.HasPrefix(key, "x-") {
+			continue
+		}
+		if found == nil {
+			found = make(map[string]any)
+		}
+		found[key] = value
+	}
+	return found
 }
 
 type SwaggerParameter struct {
 	Schema      *SwaggerSchema `json:"schema,omitempty"`
+	Items       *SwaggerSchema `json:"items,omitempty"`
+	Default     any            `json:"default,omitempty"`
+	Example     any            `json:"example,omitempty"`
 	Name        string         `json:"name"`
 	In          string         `json:"in"`
 	Type        string         `json:"type"`
 	Description string         `json:"description"`
+	Format      string         `json:"format,omitempty"`
+	Enum        []any          `json:"enum,omitempty"`
 	Required    bool           `json:"required"`
+	RawPath     bool           `json:"x-mcp-raw-path,omitempty"`
 }
 
 type SwaggerResponse struct {
@@ -55,24 +122,70 @@ type SwaggerSchema struct {
 	Ref                  string                    `json:"$ref,omitempty"`
 	Properties           map[string]*SwaggerSchema `json:"properties,omitempty"`
 	AdditionalProperties *SwaggerSchema            `json:"additionalProperties,omitempty"`
+	Items                *SwaggerSchema            `json:"items,omitempty"`
 	Minimum              *float64                  `json:"minimum,omitempty"`
 	Maximum              *float64                  `json:"maximum,omitempty"`
+	Default              any                       `json:"default,omitempty"`
+	Example              any                       `json:"example,omitempty"`
 	Type                 string                    `json:"type,omitempty"`
 	Description          string                    `json:"description,omitempty"`
 	Format               string                    `json:"format,omitempty"`
 	Required             []string                  `json:"required,omitempty"`
+	AllOf                []*SwaggerSchema          `json:"allOf,omitempty"`
+	AnyOf                []*SwaggerSchema          `json:"anyOf,omitempty"`
+	OneOf                []*SwaggerSchema          `json:"oneOf,omitempty"`
+	Enum                 []any                     `json:"enum,omitempty"`
+	Nullable             bool                      `json:"x-nullable,omitempty"`
+}
+
+// ErrSpecNotFound is returned by GetSwaggerSpec when no swaggo documentation is available.
+var ErrSpecNotFound = errors.New("swagger documentation not found")
+
+// specCache holds the swaggo specification parsed by GetSwaggerSpec. The documentation
+// is compiled into the binary, so it is parsed once and shared until ResetSwaggerCache.
+var specCache struct {
+	spec *SwaggerSpec
+	err  error
+	once sync.Once
+	mu   sync.Mutex
 }
 
-// GetSwaggerSpec retrieves the swagger specification from swaggo
+// GetSwaggerSpec retrieves the swagger specification from swaggo. The specification is
+// parsed on the first call and cached; the returned spec is shared and must not be modified.
 func GetSwaggerSpec() (*SwaggerSpec, error) {
+	specCache.mu.Lock()
+	defer specCache.mu.Unlock()
+
+	specCache.once.Do(func() {
+		specCache.spec, specCache.err = parseSwaggerSpec()
+	})
+	return specCache.spec, specCache.err
+}
+
+// ResetSwaggerCache drops the cached swaggo specification and the specifications fetched by
+// GetSwaggerSpecFromURL, so they are parsed again. It is meant for tests that register
+// different documentation.
+func ResetSwaggerCache() {
+	specCache.mu.Lock()
+	specCache.spec, specCache.err = nil, nil
+	specCache.once = sync.Once{}
+	specCache.mu.Unlock()
+
+	remoteSpecs.mu.Lock()
+	clear(remoteSpecs.specs)
+	remoteSpecs.mu.Unlock()
+}
+
+// parseSwaggerSpec reads and parses the swagger specification registered with swaggo
+func parseSwaggerSpec() (*SwaggerSpec, error) {
 	info := swag.GetSwagger("swagger")
 	if info == nil {
-		return nil, errors.New("swagger documentation not found - make sure to import docs package and generate swagger")
+		return nil, fmt.Errorf("%w - make sure to import docs package and generate swagger", ErrSpecNotFound)
 	}
 
 	swaggerJSON := info.ReadDoc()
 	if swaggerJSON == "" {
-		return nil, errors.New("swagger documentation is empty")
+		return nil, fmt.Errorf("%w - swagger documentation is empty", ErrSpecNotFound)
 	}
 
 	var spec SwaggerSpec
@@ -111,15 +224,77 @@ func echoPathToSwaggerPath(echoPath string) string {
 	return re.ReplaceAllString(echoPath, "{$1}")
 }
 
+// lookupPath finds the swagger path matching an Echo route path, returning the swagger path.
+// Routes registered under the spec basePath (e.g. /api/v1/users/:id for basePath /api/v1)
+// match the relative swagger path (/users/{id}).
+func (spec *SwaggerSpec) lookupPath(echoPath string) (SwaggerPath, string, bool) {
+	swaggerPath := echoPathToSwaggerPath(echoPath)
+	if pathSpec, exists := spec.Paths[swaggerPath]; exists {
+		return pathSpec, swaggerPath, true
+	}
+
+	if relativePath, hasBasePath := spec.trimBasePath(swaggerPath); hasBasePath {
+		if pathSpec, exists := spec.Paths[relativePath]; exists {
+			return pathSpec, relativePath, true
+		}
+	}
+
+	return nil, swaggerPath, false
+}
+
+// BaseURL returns the URL of the server described by the spec host and schemes (e.g.
+// "https://api.example.com"), preferring https when it is listed and defaulting to http.
+// The basePath is not included. It returns an empty string when the spec declares no host,
+// and an error when the host does not form a valid URL.
+func (spec *SwaggerSpec) BaseURL() (string, error) {
+	if spec.Host == "" {
+		return "", nil
+	}
+
+	scheme := "http"
+	if slices.Contains(spec.Schemes, "https") {
+		scheme = "https"
+	} else if len(spec.Schemes) > 0 {
+		scheme = spec.Schemes[0]
+	}
+
+	u, err := url.Parse(scheme + "://" + spec.Host)
+	if err != nil || u.Host != spec.Host || u.Path != "" || (u.Port() != "" && !isPort(u.Port())) {
+		return "", fmt.Errorf("invalid host %q", spec.Host)
+	}
+	return u.String(), nil
+}
+
+// isPort reports whether port is a decimal port number
+func isPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n <= 65535
+}
+
+// trimBasePath removes the spec basePath from the start of path
+func (spec *SwaggerSpec) trimBasePath(path string) (string, bool) {
+	basePath := strings.TrimSuffix(spec.BasePath, "/")
+	if basePath == "" {
+		return path, false
+	}
+
+	relativePath, hasBasePath := strings.CutPrefix(path, basePath)
+	if !hasBasePath || (relativePath != "" && !strings.HasPrefix(relativePath, "/")) {
+		return path, false
+	}
+	if relativePath == "" {
+		relativePath = "/"
+	}
+	return relativePath, true
+}
+
 // GetOperationSchema returns the MCP schema for a specific operation
 func (spec *SwaggerSpec) GetOperationSchema(method, path string) (map[string]any, error) {
 	// Normalize method
 	method = strings.ToLower(method)
 
 	// Convert Echo path to Swagger path format
-	swaggerPath := echoPathToSwaggerPath(path)
-
-	pathSpec, exists := spec.Paths[swaggerPath]
+	pathSpec, swaggerPath, exists := spec.lookupPath(path)
 	if !exists {
 		return nil, fmt.Errorf("path %s not found in swagger spec", swaggerPath)
 	}
@@ -139,16 +314,18 @@ func (spec *SwaggerSpec) GetOperationSchema(method, path string) (map[string]any
 	if !ok {
 		return schema, nil
 	}
-	var required []string
+	var required, order []string
 
 	// Process parameters
 	for _, param := range operation.Parameters {
 		if param.In == "path" || param.In == "query" || param.In == "header" || param.In == "formData" {
+			order = append(order, param.Name)
+
 			// Normalize Swagger types to valid JSON Schema types.
 			// Swagger's "file" type has no JSON Schema equivalent;
 			// represent it as "string" with format "binary".
 			paramType := param.Type
-			paramFormat := ""
+			paramFormat := param.Format
 			if paramType == "file" {
 				paramType = "string"
 				paramFormat = "binary"
@@ -158,10 +335,28 @@ func (spec *SwaggerSpec) GetOperationSchema(method, path string) (map[string]any
 				"type": paramType,
 			}
 
+			// Parameters typed through a schema (e.g. a $ref to an enum or integer definition)
+			// take the type and constraints of the resolved schema
+			if paramType == "" && param.Schema != nil {
+				if resolved, ok := spec.convertSwaggerSchemaToMCP(param.Schema).(map[string]any); ok {
+					propSchema = resolved
+					paramType, _ = resolved["type"].(string)
+				}
+			}
+
 			if paramFormat != "" {
 				propSchema["format"] = paramFormat
 			}
 
+			// Array parameters are sent as repeated keys (e.g. ?ids=1&ids=2)
+			if paramType == "array" {
+				if param.Items != nil {
+					propSchema["items"] = spec.convertSwaggerSchemaToMCP(param.Items)
+				} else {
+					propSchema["items"] = map[string]any{"type": "string"}
+				}
+			}
+
 			if param.Description != "" {
 				propSchema["description"] = param.Description
 			} else if param.In == "header" {
@@ -170,6 +365,8 @@ func (spec *SwaggerSpec) GetOperationSchema(method, path string) (map[string]any
 				propSchema["description"] = fmt.Sprintf("Form data parameter: %s", param.Name)
 			}
 
+			applyValueHints(propSchema, param.Default, param.Example, param.Enum)
+
 			properties[param.Name] = propSchema
 
 			if param.Required {
@@ -184,6 +381,7 @@ func (spec *SwaggerSpec) GetOperationSchema(method, path string) (map[string]any
 			// Handle request body as a nested object under "body" property
 			bodySchema := spec.convertSwaggerSchemaToMCP(param.Schema)
 			properties["body"] = bodySchema
+			order = append(order, "body")
 
 			if param.Required {
 				required = append(required, "body")
@@ -195,11 +393,192 @@ func (spec *SwaggerSpec) GetOperationSchema(method, path string) (map[string]any
 		schema["required"] = required
 	}
 
+	// Present path parameters first, then query parameters and the body
+	if len(order) > 0 {
+		locations := make(map[string]string, len(operation.Parameters))
+		for _, param := range operation.Parameters {
+			locations[param.Name] = param.In
+		}
+		locations["body"] = "body"
+		slices.SortStableFunc(order, func(a, b string) int {
+			return parameterRank(locations[a]) - parameterRank(locations[b])
+		})
+		schema[types.PropertyOrderKey] = slices.Compact(order)
+	}
+
 	return schema, nil
 }
 
+// parameterRank orders parameter locations in input schemas
+func parameterRank(in string) int {
+	switch in {
+	case "path":
+		return 0
+	case "query":
+		return 1
+	case "header", "formData":
+		return 2
+	default:
+		return 3
+	}
+}
+
+// GetParameterDefaults returns the declared default values of the path, query,
+// header, and formData parameters of an operation, keyed by parameter name
+func (spec *SwaggerSpec) GetParameterDefaults(method, path string) map[string]any {
+	operation, exists := spec.GetOperation(method, path)
+	if !exists {
+		return nil
+	}
+
+	var defaults map[string]any
+	for _, param := range operation.Parameters {
+		if param.In == "body" || param.Default == nil {
+			continue
+		}
+		if defaults == nil {
+			defaults = map[string]any{}
+		}
+		defaults[param.Name] = param.Default
+	}
+
+	return defaults
+}
+
+// IsDeprecated reports whether the operation for an Echo route is marked deprecated.
+// Routes that are not documented in the spec are never deprecated.
+func (spec *SwaggerSpec) IsDeprecated(method, path string) bool {
+	operation, exists := spec.GetOperation(method, path)
+	return exists && operation.Deprecated
+}
+
+// HasBodyParameter reports whether the operation for an Echo route declares a body parameter
+// (or an OpenAPI 3 request body)
+func (spec *SwaggerSpec) HasBodyParameter(method, path string) bool {
+	operation, exists := spec.GetOperation(method, path)
+	return exists && slices.ContainsFunc(operation.Parameters, func(param SwaggerParameter) bool { return param.In == "body" })
+}
+
+// GetOperationID returns the operationId declared for an Echo route, or an empty
+// string if it is missing or shared with another operation in the spec
+func (spec *SwaggerSpec) GetOperationID(method, path string) string {
+	operation, exists := spec.GetOperation(method, path)
+	if !exists || operation.OperationID == "" {
+		return ""
+	}
+
+	count := 0
+	for _, pathSpec := range spec.Paths {
+		for _, op := range pathSpec {
+			if op.OperationID == operation.OperationID {
+				count++
+			}
+		}
+	}
+	if count > 1 {
+		return ""
+	}
+
+	return operation.OperationID
+}
+
+// IsExcluded reports whether the operation for an Echo route sets x-mcp-exclude to true.
+// Routes that are not documented in the spec are never excluded.
+func (spec *SwaggerSpec) IsExcluded(method, path string) bool {
+	operation, exists := spec.GetOperation(method, path)
+	return exists && operation.Extensions[ExcludeExtension] == true
+}
+
+// GetRawPathParameters returns the names of the path parameters of an operation that set
+// x-mcp-raw-path to true, whose values may contain slashes
+func (spec *SwaggerSpec) GetRawPathParameters(method, path string) []string {
+	operation, exists := spec.GetOperation(method, path)
+	if !exists {
+		return nil
+	}
+
+	var names []string
+	for _, param := range operation.Parameters {
+		if param.In == "path" && param.RawPath {
+			names = append(names, param.Name)
+		}
+	}
+	return names
+}
+
+// GetToolName returns the tool name set with the x-mcp-tool-name extension of an operation,
+// or an empty string if the operation does not set one
+func (spec *SwaggerSpec) GetToolName(method, path string) string {
+	operation, exists := spec.GetOperation(method, path)
+	if !exists {
+		return ""
+	}
+
+	name, _ := operation.Extensions[ToolNameExtension].(string)
+	return name
+}
+
+// GetTimeout returns the tool timeout declared with the x-mcp-timeout extension,
+// either as a duration string ("2m30s") or a number of seconds. It returns zero
+// if the operation declares no valid timeout.
+func (spec *SwaggerSpec) GetTimeout(method, path string) time.Duration {
+	operation, exists := spec.GetOperation(method, path)
+	if !exists {
+		return 0
+	}
+
+	switch timeout := operation.XMCPTimeout.(type) {
+	case string:
+		if d, err := time.ParseDuration(timeout); err == nil && d > 0 {
+			return d
+		}
+	case float64:
+		if timeout > 0 {
+			return time.Duration(timeout * float64(time.Second))
+		}
+	case int:
+		if timeout > 0 {
+			return time.Duration(timeout) * time.Second
+		}
+	}
+
+	return 0
+}
+
+// GetOperation looks up the swagger operation for an Echo method and path
+func (spec *SwaggerSpec) GetOperation(method, path string) (SwaggerOperation, bool) {
+	pathSpec, _, exists := spec.lookupPath(path)
+	if !exists {
+		return SwaggerOperation{}, false
+	}
+
+	operation, exists := pathSpec[strings.ToLower(method)]
+	return operation, exists
+}
+
+// applyValueHints copies default, example, and enum values into an MCP property schema
+func applyValueHints(propSchema map[string]any, defaultValue, example any, enum []any) {
+	if defaultValue != nil {
+		propSchema["default"] = defaultValue
+	}
+
+	if example != nil {
+		propSchema["examples"] = []any{example}
+	}
+
+	if len(enum) > 0 {
+		propSchema["enum"] = enum
+	}
+}
+
 // convertSwaggerSchemaToMCP converts swagger schema to MCP-compatible schema
 func (spec *SwaggerSpec) convertSwaggerSchemaToMCP(schema *SwaggerSchema) any {
+	return spec.convertSwaggerSchema(schema, map[string]bool{})
+}
+
+// convertSwaggerSchema converts a swagger schema, tracking the definitions being resolved
+// in visiting so recursive $refs terminate instead of expanding forever
+func (spec *SwaggerSpec) convertSwaggerSchema(schema *SwaggerSchema, visiting map[string]bool) any {
 	if schema == nil {
 		return map[string]any{"type": "object"}
 	}
@@ -210,9 +589,15 @@ func (spec *SwaggerSpec) convertSwaggerSchemaToMCP(schema *SwaggerSchema) any {
 		refParts := strings.Split(schema.Ref, "/")
 		if len(refParts) >= 3 && refParts[0] == "#" && (refParts[1] == "definitions" || refParts[1] == "components") {
 			defName := refParts[2]
+			if visiting[defName] {
+				// Cyclic reference, stop expanding
+				return map[string]any{"type": "object"}
+			}
 			if refSchema, exists := spec.Definitions[defName]; exists {
 				// Recursively convert the referenced schema
-				return spec.convertSwaggerSchemaToMCP(refSchema)
+				visiting[defName] = true
+				defer delete(visiting, defName)
+				return spec.convertSwaggerSchema(refSchema, visiting)
 			}
 		}
 		// If $ref cannot be resolved, return a basic object
@@ -225,6 +610,10 @@ func (spec *SwaggerSpec) convertSwaggerSchemaToMCP(schema *SwaggerSchema) any {
 		result["type"] = schema.Type
 	}
 
+	if schema.Nullable {
+		spec.applyNullable(result, schema.Type)
+	}
+
 	if schema.Description != "" {
 		result["description"] = schema.Description
 	}
@@ -241,21 +630,105 @@ func (spec *SwaggerSpec) convertSwaggerSchemaToMCP(schema *SwaggerSchema) any {
 		result["maximum"] = *schema.Maximum
 	}
 
+	applyValueHints(result, schema.Default, schema.Example, schema.Enum)
+
 	if schema.Properties != nil {
 		properties := map[string]any{}
 		for key, prop := range schema.Properties {
-			properties[key] = spec.convertSwaggerSchemaToMCP(prop)
+			properties[key] = spec.convertSwaggerSchema(prop, visiting)
 		}
 		result["properties"] = properties
 	}
 
 	if schema.AdditionalProperties != nil {
-		result["additionalProperties"] = spec.convertSwaggerSchemaToMCP(schema.AdditionalProperties)
+		result["additionalProperties"] = spec.convertSwaggerSchema(schema.AdditionalProperties, visiting)
+	}
+
+	if schema.Type == "array" && schema.Items != nil {
+		result["items"] = spec.convertSwaggerSchema(schema.Items, visiting)
 	}
 
 	if len(schema.Required) > 0 {
 		result["required"] = schema.Required
 	}
 
+	// allOf is flattened into a single schema since every member must hold
+	if len(schema.AllOf) > 0 {
+		spec.mergeAllOf(result, schema.AllOf, visiting)
+	}
+
+	// anyOf and oneOf are supported natively by JSON Schema
+	if len(schema.AnyOf) > 0 {
+		result["anyOf"] = spec.convertSwaggerSchemas(schema.AnyOf, visiting)
+	}
+
+	if len(schema.OneOf) > 0 {
+		result["oneOf"] = spec.convertSwaggerSchemas(schema.OneOf, visiting)
+	}
+
 	return result
 }
+
+// applyNullable marks a converted schema of the given type as accepting null, either as a
+// ["type", "null"] type union or with the OpenAPI "nullable" keyword (see NullableKeyword)
+func (spec *SwaggerSpec) applyNullable(result map[string]any, schemaType string) {
+	if spec.NullableKeyword || schemaType == "" {
+		result["nullable"] = true
+		return
+	}
+	result["type"] = []any{schemaType, "null"}
+}
+
+// convertSwaggerSchemas converts a list of swagger schemas to MCP-compatible schemas
+func (spec *SwaggerSpec) convertSwaggerSchemas(schemas []*SwaggerSchema, visiting map[string]bool) []any {
+	converted := make([]any, 0, len(schemas))
+	for _, schema := range schemas {
+		converted = append(converted, spec.convertSwaggerSchema(schema, visiting))
+	}
+	return converted
+}
+
+// mergeAllOf merges the properties and required fields of every allOf member into result
+func (spec *SwaggerSpec) mergeAllOf(result map[string]any, members []*SwaggerSchema, visiting map[string]bool) {
+	properties, _ := result["properties"].(map[string]any)
+	if properties == nil {
+		properties = map[string]any{}
+	}
+	required, _ := result["required"].([]string)
+	required = slices.Clone(required)
+
+	for _, member := range members {
+		converted, ok := spec.convertSwaggerSchema(member, visiting).(map[string]any)
+		if !ok {
+			continue
+		}
+
+		if memberProps, ok := converted["properties"].(map[string]any); ok {
+			maps.Copy(properties, memberProps)
+		}
+
+		if memberRequired, ok := converted["required"].([]string); ok {
+			for _, field := range memberRequired {
+				if !slices.Contains(required, field) {
+					required = append(required, field)
+				}
+			}
+		}
+
+		if _, exists := result["description"]; !exists {
+			if description, ok := converted["description"]; ok {
+				result["description"] = description
+			}
+		}
+	}
+
+	if _, exists := result["type"]; !exists {
+		result["type"] = "object"
+	}
+
+	result["properties"] = properties
+
+	if len(required) > 0 {
+		result["required"] = required
+	}
+}
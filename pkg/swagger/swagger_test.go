@@ -1,9 +1,12 @@
 package swagger
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggo/swag"
 )
 
 func TestGetOperationSchemaBodyParameters(t *testing.T) {
@@ -331,6 +334,50 @@ func TestGetSwaggerSpec(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "swagger documentation not found")
 	})
+
+	t.Run("Should parse the spec once until the cache is reset", func(t *testing.T) {
+		registerCountingDoc.Do(func() { swag.Register(swag.Name, countingSwagger) })
+		doc := countingSwagger
+		doc.doc, doc.reads = `{"swagger": "2.0", "info": {"title": "Users API"}}`, 0
+		ResetSwaggerCache()
+		t.Cleanup(func() {
+			doc.doc = ""
+			ResetSwaggerCache()
+		})
+
+		first, err := GetSwaggerSpec()
+		require.NoError(t, err)
+		second, err := GetSwaggerSpec()
+		require.NoError(t, err)
+
+		assert.Same(t, first, second)
+		assert.Equal(t, "Users API", first.Info.Title)
+		assert.Equal(t, 1, doc.reads)
+
+		doc.doc = `{"swagger": "2.0", "info": {"title": "Orders API"}}`
+		ResetSwaggerCache()
+
+		third, err := GetSwaggerSpec()
+		require.NoError(t, err)
+		assert.Equal(t, "Orders API", third.Info.Title)
+		assert.Equal(t, 2, doc.reads)
+	})
+}
+
+// countingDoc is a swaggo document that counts how often it is read
+type countingDoc struct {
+	doc   string
+	reads int
+}
+
+var (
+	countingSwagger     = &countingDoc{}
+	registerCountingDoc sync.Once
+)
+
+func (d *countingDoc) ReadDoc() string {
+	d.reads++
+	return d.doc
 }
 
 func TestOpenAPIConversion(t *testing.T) {
@@ -394,3 +441,882 @@ components:
 		assert.Contains(t, infoProps, "name")
 	})
 }
+
+func TestSchemaComposition(t *testing.T) {
+	spec := &SwaggerSpec{
+		Definitions: map[string]*SwaggerSchema{
+			"Base": {
+				Type: "object",
+				Properties: map[string]*SwaggerSchema{
+					"id": {Type: "string"},
+				},
+				Required: []string{"id"},
+			},
+			"Cat": {
+				Type: "object",
+				Properties: map[string]*SwaggerSchema{
+					"meows": {Type: "boolean"},
+				},
+			},
+			"Dog": {
+				Type: "object",
+				Properties: map[string]*SwaggerSchema{
+					"barks": {Type: "boolean"},
+				},
+			},
+		},
+	}
+
+	t.Run("Should flatten allOf into a single schema", func(t *testing.T) {
+		schema := &SwaggerSchema{
+			AllOf: []*SwaggerSchema{
+				{Ref: "#/definitions/Base"},
+				{
+					Type: "object",
+					Properties: map[string]*SwaggerSchema{
+						"name": {Type: "string"},
+					},
+					Required: []string{"name"},
+				},
+			},
+		}
+
+		result := spec.convertSwaggerSchemaToMCP(schema).(map[string]any)
+
+		assert.Equal(t, "object", result["type"])
+		properties := result["properties"].(map[string]any)
+		assert.Contains(t, properties, "id")
+		assert.Contains(t, properties, "name")
+		assert.ElementsMatch(t, []string{"id", "name"}, result["required"])
+		assert.NotContains(t, result, "allOf")
+	})
+
+	t.Run("Should emit anyOf and oneOf as-is", func(t *testing.T) {
+		schema := &SwaggerSchema{
+			OneOf: []*SwaggerSchema{{Ref: "#/definitions/Cat"}, {Ref: "#/definitions/Dog"}},
+			AnyOf: []*SwaggerSchema{{Type: "string"}, {Type: "integer"}},
+		}
+
+		result := spec.convertSwaggerSchemaToMCP(schema).(map[string]any)
+
+		oneOf := result["oneOf"].([]any)
+		assert.Len(t, oneOf, 2)
+		assert.Contains(t, oneOf[0].(map[string]any)["properties"], "meows")
+		assert.Contains(t, oneOf[1].(map[string]any)["properties"], "barks")
+
+		anyOf := result["anyOf"].([]any)
+		assert.Equal(t, map[string]any{"type": "string"}, anyOf[0])
+		assert.Equal(t, map[string]any{"type": "integer"}, anyOf[1])
+	})
+
+	t.Run("Should convert OpenAPI 3.0 allOf", func(t *testing.T) {
+		openAPIYAML := `
+openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              allOf:
+                - $ref: '#/components/schemas/Base'
+                - type: object
+                  properties:
+                    name:
+                      type: string
+      responses:
+        '201':
+          description: Created
+components:
+  schemas:
+    Base:
+      type: object
+      required: [id]
+      properties:
+        id:
+          type: string
+`
+		parsed, err := ParseOpenAPISchema(openAPIYAML)
+		assert.NoError(t, err)
+
+		schema, err := parsed.GetOperationSchema("POST", "/users")
+		assert.NoError(t, err)
+
+		body := schema["properties"].(map[string]any)["body"].(map[string]any)
+		bodyProps := body["properties"].(map[string]any)
+		assert.Contains(t, bodyProps, "id")
+		assert.Contains(t, bodyProps, "name")
+		assert.Equal(t, []string{"id"}, body["required"])
+	})
+}
+
+func TestListTags(t *testing.T) {
+	t.Run("Should return unique sorted tags across operations", func(t *testing.T) {
+		spec := &SwaggerSpec{
+			Paths: map[string]SwaggerPath{
+				"/users": {
+					"get":  SwaggerOperation{Tags: []string{"users"}},
+					"post": SwaggerOperation{Tags: []string{"users", "admin"}},
+				},
+				"/orders": {
+					"get": SwaggerOperation{Tags: []string{"orders"}},
+				},
+				"/health": {
+					"get": SwaggerOperation{},
+				},
+			},
+		}
+
+		assert.Equal(t, []string{"admin", "orders", "users"}, spec.ListTags())
+	})
+
+	t.Run("Should return no tags for an empty spec", func(t *testing.T) {
+		assert.Empty(t, (&SwaggerSpec{}).ListTags())
+	})
+}
+
+func TestGetTaggedOperations(t *testing.T) {
+	spec := &SwaggerSpec{
+		Paths: map[string]SwaggerPath{
+			"/users": {
+				"get":  SwaggerOperation{Tags: []string{"users"}},
+				"post": SwaggerOperation{Tags: []string{"users", "admin"}},
+			},
+			"/orders": {
+				"get": SwaggerOperation{Tags: []string{"orders"}},
+			},
+			"/health": {
+				"get": SwaggerOperation{},
+			},
+		},
+	}
+
+	t.Run("Should return operations matching any tag", func(t *testing.T) {
+		operations := spec.GetTaggedOperations([]string{"admin", "orders"})
+
+		assert.Len(t, operations, 2)
+		assert.Equal(t, "/orders", operations[0].Path)
+		assert.Equal(t, "GET", operations[0].Method)
+		assert.Equal(t, "/users", operations[1].Path)
+		assert.Equal(t, "POST", operations[1].Method)
+	})
+
+	t.Run("Should not require operations to carry every tag", func(t *testing.T) {
+		operations := spec.GetTaggedOperations([]string{"users", "admin"})
+
+		// Union: GET /users only has "users" but is still included
+		assert.Len(t, operations, 2)
+		assert.Equal(t, "GET", operations[0].Method)
+		assert.Equal(t, "POST", operations[1].Method)
+		assert.Equal(t, []string{"users", "admin"}, operations[1].Operation.Tags)
+	})
+
+	t.Run("Should return no operations without tags", func(t *testing.T) {
+		assert.Empty(t, spec.GetTaggedOperations(nil))
+		assert.Empty(t, spec.GetTaggedOperations([]string{"unknown"}))
+	})
+
+	t.Run("Should require every tag with intersection semantics", func(t *testing.T) {
+		operations := spec.GetOperationsWithAllTags([]string{"users", "admin"})
+
+		// Intersection: GET /users only has "users" and is left out
+		assert.Len(t, operations, 1)
+		assert.Equal(t, "/users", operations[0].Path)
+		assert.Equal(t, "POST", operations[0].Method)
+	})
+
+	t.Run("Should match a single tag the same way in both modes", func(t *testing.T) {
+		assert.Equal(t, spec.GetTaggedOperations([]string{"users"}), spec.GetOperationsWithAllTags([]string{"users"}))
+		assert.Len(t, spec.GetOperationsWithAllTags([]string{"users"}), 2)
+	})
+
+	t.Run("Should return no operations without tags in intersection mode", func(t *testing.T) {
+		assert.Empty(t, spec.GetOperationsWithAllTags(nil))
+		assert.Empty(t, spec.GetOperationsWithAllTags([]string{"users", "unknown"}))
+	})
+}
+
+func TestGetSecurityParameters(t *testing.T) {
+	spec := &SwaggerSpec{
+		SecurityDefinitions: map[string]*SwaggerSecurityScheme{
+			"ApiKey":      {Type: "apiKey", Name: "X-API-Key", In: "header"},
+			"QueryKey":    {Type: "apiKey", Name: "api_key", In: "query"},
+			"BearerToken": {Type: "http", Scheme: "bearer"},
+		},
+		Security: []SecurityRequirement{{"BearerToken": {}}},
+		Paths: map[string]SwaggerPath{
+			"/users": {
+				"get":  SwaggerOperation{Security: []SecurityRequirement{{"ApiKey": {}}, {"QueryKey": {}}}},
+				"post": SwaggerOperation{},
+			},
+			"/health": {
+				"get": SwaggerOperation{Security: []SecurityRequirement{}},
+			},
+		},
+	}
+
+	t.Run("Should map apiKey schemes to header and query parameters", func(t *testing.T) {
+		params := spec.GetSecurityParameters("GET", "/users")
+
+		assert.Len(t, params, 2)
+		assert.Equal(t, "X-API-Key", params[0].Name)
+		assert.Equal(t, "header", params[0].In)
+		assert.Equal(t, "api_key", params[1].Name)
+		assert.Equal(t, "query", params[1].In)
+	})
+
+	t.Run("Should fall back to global security requirements", func(t *testing.T) {
+		params := spec.GetSecurityParameters("POST", "/users")
+
+		assert.Len(t, params, 1)
+		assert.Equal(t, "Authorization", params[0].Name)
+		assert.Equal(t, "header", params[0].In)
+	})
+
+	t.Run("Should honor an empty operation security override", func(t *testing.T) {
+		assert.Empty(t, spec.GetSecurityParameters("GET", "/health"))
+	})
+
+	t.Run("Should list the headers of apiKey schemes", func(t *testing.T) {
+		assert.Equal(t, []string{"X-API-Key"}, spec.GetAPIKeyHeaders())
+	})
+
+	t.Run("Should parse OpenAPI 3.0 security schemes", func(t *testing.T) {
+		openAPIYAML := `
+openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      security:
+        - ApiKey: []
+      responses:
+        '200':
+          description: OK
+components:
+  securitySchemes:
+    ApiKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+`
+		parsed, err := ParseOpenAPISchema(openAPIYAML)
+		assert.NoError(t, err)
+
+		params := parsed.GetSecurityParameters("GET", "/users")
+		assert.Len(t, params, 1)
+		assert.Equal(t, "X-API-Key", params[0].Name)
+	})
+}
+
+func TestArraySchemas(t *testing.T) {
+	t.Run("Should convert array items in swagger response body", func(t *testing.T) {
+		spec := &SwaggerSpec{
+			Definitions: map[string]*SwaggerSchema{
+				"User": {
+					Type: "object",
+					Properties: map[string]*SwaggerSchema{
+						"id": {Type: "string"},
+					},
+				},
+			},
+			Paths: map[string]SwaggerPath{
+				"/users": {
+					"get": SwaggerOperation{
+						Responses: map[string]SwaggerResponse{
+							"200": {
+								Description: "OK",
+								Schema: &SwaggerSchema{
+									Type:  "array",
+									Items: &SwaggerSchema{Ref: "#/definitions/User"},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		responseSchema := spec.Paths["/users"]["get"].Responses["200"].Schema
+		result := spec.convertSwaggerSchemaToMCP(responseSchema).(map[string]any)
+
+		assert.Equal(t, "array", result["type"])
+		items := result["items"].(map[string]any)
+		assert.Equal(t, "object", items["type"])
+		assert.Contains(t, items["properties"], "id")
+	})
+
+	t.Run("Should ignore items on non-array schemas", func(t *testing.T) {
+		spec := &SwaggerSpec{}
+
+		result := spec.convertSwaggerSchemaToMCP(&SwaggerSchema{
+			Type:  "object",
+			Items: &SwaggerSchema{Type: "string"},
+		}).(map[string]any)
+
+		assert.NotContains(t, result, "items")
+	})
+
+	t.Run("Should convert OpenAPI 3.0 array items", func(t *testing.T) {
+		openAPIYAML := `
+openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                tags:
+                  type: array
+                  items:
+                    type: string
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/User'
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        id:
+          type: string
+`
+		parsed, err := ParseOpenAPISchema(openAPIYAML)
+		assert.NoError(t, err)
+
+		schema, err := parsed.GetOperationSchema("POST", "/users")
+		assert.NoError(t, err)
+
+		body := schema["properties"].(map[string]any)["body"].(map[string]any)
+		tags := body["properties"].(map[string]any)["tags"].(map[string]any)
+		assert.Equal(t, "array", tags["type"])
+		assert.Equal(t, map[string]any{"type": "string"}, tags["items"])
+
+		response := parsed.Paths["/users"]["post"].Responses["200"].Schema
+		result := parsed.convertSwaggerSchemaToMCP(response).(map[string]any)
+		assert.Equal(t, "array", result["type"])
+		assert.Contains(t, result["items"].(map[string]any)["properties"], "id")
+	})
+}
+
+func TestRecursiveSchemas(t *testing.T) {
+	spec := &SwaggerSpec{
+		Definitions: map[string]*SwaggerSchema{
+			"Entity": {
+				Type:       "object",
+				Properties: map[string]*SwaggerSchema{"id": {Type: "string"}},
+				Required:   []string{"id"},
+			},
+			"Audit": {
+				Type:       "object",
+				Properties: map[string]*SwaggerSchema{"createdAt": {Type: "string"}},
+			},
+			"Node": {
+				Type: "object",
+				Properties: map[string]*SwaggerSchema{
+					"name":     {Type: "string"},
+					"children": {Type: "array", Items: &SwaggerSchema{Ref: "#/definitions/Node"}},
+				},
+			},
+			"Loop": {
+				AllOf: []*SwaggerSchema{{Ref: "#/definitions/Loop"}, {Ref: "#/definitions/Entity"}},
+			},
+		},
+	}
+
+	t.Run("Should merge allOf of two definitions", func(t *testing.T) {
+		result := spec.convertSwaggerSchemaToMCP(&SwaggerSchema{
+			AllOf: []*SwaggerSchema{{Ref: "#/definitions/Entity"}, {Ref: "#/definitions/Audit"}},
+		}).(map[string]any)
+
+		properties := result["properties"].(map[string]any)
+		assert.Contains(t, properties, "id")
+		assert.Contains(t, properties, "createdAt")
+		assert.Equal(t, []string{"id"}, result["required"])
+	})
+
+	t.Run("Should pass oneOf of primitive types through", func(t *testing.T) {
+		result := spec.convertSwaggerSchemaToMCP(&SwaggerSchema{
+			OneOf: []*SwaggerSchema{{Type: "string"}, {Type: "integer"}},
+		}).(map[string]any)
+
+		assert.Equal(t, []any{
+			map[string]any{"type": "string"},
+			map[string]any{"type": "integer"},
+		}, result["oneOf"])
+	})
+
+	t.Run("Should stop expanding self-referencing definitions", func(t *testing.T) {
+		result := spec.convertSwaggerSchemaToMCP(&SwaggerSchema{Ref: "#/definitions/Node"}).(map[string]any)
+
+		children := result["properties"].(map[string]any)["children"].(map[string]any)
+		assert.Equal(t, map[string]any{"type": "object"}, children["items"])
+	})
+
+	t.Run("Should stop expanding cyclic allOf references", func(t *testing.T) {
+		result := spec.convertSwaggerSchemaToMCP(&SwaggerSchema{Ref: "#/definitions/Loop"}).(map[string]any)
+
+		assert.Contains(t, result["properties"], "id")
+	})
+
+	t.Run("Should expand the same definition in sibling properties", func(t *testing.T) {
+		result := spec.convertSwaggerSchemaToMCP(&SwaggerSchema{
+			Type: "object",
+			Properties: map[string]*SwaggerSchema{
+				"owner":  {Ref: "#/definitions/Entity"},
+				"parent": {Ref: "#/definitions/Entity"},
+			},
+		}).(map[string]any)
+
+		properties := result["properties"].(map[string]any)
+		assert.Contains(t, properties["owner"].(map[string]any)["properties"], "id")
+		assert.Contains(t, properties["parent"].(map[string]any)["properties"], "id")
+	})
+}
+
+func TestArrayQueryParameters(t *testing.T) {
+	spec := &SwaggerSpec{
+		Paths: map[string]SwaggerPath{
+			"/users": {
+				"get": SwaggerOperation{
+					Parameters: []SwaggerParameter{
+						{Name: "ids", In: "query", Type: "array", Items: &SwaggerSchema{Type: "integer"}},
+						{Name: "tags", In: "query", Type: "array"},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("Should describe array query parameters with items", func(t *testing.T) {
+		schema, err := spec.GetOperationSchema("GET", "/users")
+		assert.NoError(t, err)
+
+		properties := schema["properties"].(map[string]any)
+		assert.Equal(t, map[string]any{"type": "array", "items": map[string]any{"type": "integer"}}, properties["ids"])
+		assert.Equal(t, map[string]any{"type": "array", "items": map[string]any{"type": "string"}}, properties["tags"])
+	})
+}
+
+func TestRefTypedParameters(t *testing.T) {
+	t.Run("Should resolve the type of $ref query parameters", func(t *testing.T) {
+		spec := &SwaggerSpec{
+			Definitions: map[string]*SwaggerSchema{
+				"models.Status": {Type: "string", Enum: []any{"active", "inactive"}},
+				"models.Page":   {Type: "integer", Format: "int32"},
+			},
+			Paths: map[string]SwaggerPath{
+				"/users": {
+					"get": SwaggerOperation{
+						Parameters: []SwaggerParameter{
+							{Name: "status", In: "query", Schema: &SwaggerSchema{Ref: "#/definitions/models.Status"}},
+							{Name: "page", In: "query", Description: "Page number", Schema: &SwaggerSchema{Ref: "#/definitions/models.Page"}},
+							{Name: "limit", In: "query", Type: "integer"},
+						},
+					},
+				},
+			},
+		}
+
+		schema, err := spec.GetOperationSchema("GET", "/users")
+		assert.NoError(t, err)
+
+		properties := schema["properties"].(map[string]any)
+		assert.Equal(t, map[string]any{"type": "string", "enum": []any{"active", "inactive"}}, properties["status"])
+		assert.Equal(t, map[string]any{"type": "integer", "format": "int32", "description": "Page number"}, properties["page"])
+		assert.Equal(t, map[string]any{"type": "integer"}, properties["limit"])
+	})
+
+	t.Run("Should resolve $ref parameters from OpenAPI 3 components", func(t *testing.T) {
+		spec, err := ParseOpenAPISchema(`
+openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            $ref: '#/components/schemas/UserID'
+      responses:
+        '200':
+          description: OK
+components:
+  schemas:
+    UserID:
+      type: integer
+`)
+		assert.NoError(t, err)
+
+		schema, err := spec.GetOperationSchema("GET", "/users/:id")
+		assert.NoError(t, err)
+
+		properties := schema["properties"].(map[string]any)
+		assert.Equal(t, map[string]any{"type": "integer"}, properties["id"])
+		assert.Equal(t, []string{"id"}, schema["required"])
+	})
+}
+
+func TestRequestContentType(t *testing.T) {
+	t.Run("Should select XML only for operations that do not consume JSON", func(t *testing.T) {
+		spec := &SwaggerSpec{
+			Paths: map[string]SwaggerPath{
+				"/legacy": {"post": SwaggerOperation{Consumes: []string{"application/xml"}}},
+				"/both":   {"post": SwaggerOperation{Consumes: []string{"application/json", "application/xml"}}},
+				"/json":   {"post": SwaggerOperation{}},
+			},
+		}
+
+		assert.Equal(t, "application/xml", spec.GetRequestContentType("POST", "/legacy"))
+		assert.Empty(t, spec.GetRequestContentType("POST", "/both"))
+		assert.Empty(t, spec.GetRequestContentType("POST", "/json"))
+		assert.Empty(t, spec.GetRequestContentType("POST", "/missing"))
+	})
+
+	t.Run("Should select multipart only for operations that do not consume URL-encoded forms", func(t *testing.T) {
+		spec := &SwaggerSpec{
+			Paths: map[string]SwaggerPath{
+				"/upload": {"post": SwaggerOperation{Consumes: []string{"multipart/form-data"}}},
+				"/form":   {"post": SwaggerOperation{Consumes: []string{"application/x-www-form-urlencoded", "multipart/form-data"}}},
+			},
+		}
+
+		assert.Equal(t, "multipart/form-data", spec.GetRequestContentType("POST", "/upload"))
+		assert.Empty(t, spec.GetRequestContentType("POST", "/form"))
+	})
+
+	t.Run("Should read consumes and produces from OpenAPI 3 content", func(t *testing.T) {
+		spec, err := ParseOpenAPISchema(`
+openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /notes:
+    post:
+      requestBody:
+        content:
+          application/xml:
+            schema:
+              type: object
+              properties:
+                to:
+                  type: string
+      responses:
+        '200':
+          description: OK
+          content:
+            application/xml:
+              schema:
+                type: object
+`)
+		assert.NoError(t, err)
+
+		operation, exists := spec.GetOperation("POST", "/notes")
+		assert.True(t, exists)
+		assert.Equal(t, []string{"application/xml"}, operation.Consumes)
+		assert.Equal(t, []string{"application/xml"}, operation.Produces)
+		assert.Equal(t, "application/xml", spec.GetRequestContentType("POST", "/notes"))
+
+		schema, err := spec.GetOperationSchema("POST", "/notes")
+		assert.NoError(t, err)
+		assert.Contains(t, schema["properties"], "body")
+	})
+
+	t.Run("Should recognize XML media types", func(t *testing.T) {
+		assert.True(t, IsXMLContentType("application/xml; charset=UTF-8"))
+		assert.True(t, IsXMLContentType("text/xml"))
+		assert.True(t, IsXMLContentType("application/soap+xml"))
+		assert.False(t, IsXMLContentType("application/json"))
+		assert.False(t, IsXMLContentType(""))
+	})
+
+	t.Run("Should recognize JSON media types", func(t *testing.T) {
+		assert.True(t, IsJSONContentType("application/json; charset=UTF-8"))
+		assert.True(t, IsJSONContentType("application/problem+json"))
+		assert.False(t, IsJSONContentType("text/plain"))
+		assert.False(t, IsJSONContentType(""))
+	})
+}
+
+func TestSwaggerBaseURL(t *testing.T) {
+	t.Run("Should prefer https when it is listed", func(t *testing.T) {
+		baseURL, err := (&SwaggerSpec{Host: "api.example.com", Schemes: []string{"http", "https"}}).BaseURL()
+		require.NoError(t, err)
+		assert.Equal(t, "https://api.example.com", baseURL)
+	})
+
+	t.Run("Should default to http without schemes", func(t *testing.T) {
+		baseURL, err := (&SwaggerSpec{Host: "localhost:8080", BasePath: "/api/v1"}).BaseURL()
+		require.NoError(t, err)
+		assert.Equal(t, "http://localhost:8080", baseURL)
+	})
+
+	t.Run("Should return nothing without a host", func(t *testing.T) {
+		baseURL, err := (&SwaggerSpec{}).BaseURL()
+		require.NoError(t, err)
+		assert.Empty(t, baseURL)
+	})
+
+	t.Run("Should reject invalid hosts", func(t *testing.T) {
+		for _, host := range []string{"local host", "localhost:99999", "example.com/api", "http://example.com"} {
+			_, err := (&SwaggerSpec{Host: host}).BaseURL()
+			assert.Error(t, err, host)
+		}
+	})
+}
+
+func TestSwaggerBasePath(t *testing.T) {
+	spec := &SwaggerSpec{
+		BasePath: "/api/v1/",
+		Paths: map[string]SwaggerPath{
+			"/users/{id}": {"get": SwaggerOperation{OperationID: "getUser"}},
+			"/":           {"get": SwaggerOperation{OperationID: "index"}},
+		},
+	}
+
+	t.Run("Should match Echo routes registered under the basePath", func(t *testing.T) {
+		operation, exists := spec.GetOperation("GET", "/api/v1/users/:id")
+		require.True(t, exists)
+		assert.Equal(t, "getUser", operation.OperationID)
+
+		operation, exists = spec.GetOperation("GET", "/api/v1")
+		require.True(t, exists)
+		assert.Equal(t, "index", operation.OperationID)
+
+		_, err := spec.GetOperationSchema("GET", "/api/v1/users/:id")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should keep matching routes without the basePath", func(t *testing.T) {
+		_, exists := spec.GetOperation("GET", "/users/:id")
+		assert.True(t, exists)
+	})
+
+	t.Run("Should not match paths that only share a prefix with the basePath", func(t *testing.T) {
+		_, exists := spec.GetOperation("GET", "/api/v10/users/:id")
+		assert.False(t, exists)
+	})
+}
+
+func TestFormatAndNullable(t *testing.T) {
+	swaggerJSON := `{
+		"swagger": "2.0",
+		"paths": {
+			"/users/{id}": {
+				"patch": {
+					"parameters": [
+						{"name": "Request", "in": "body", "required": true, "schema": {"$ref": "#/definitions/main.UserPatchRequest"}},
+						{"type": "string", "format": "uuid", "description": "User ID", "name": "id", "in": "path", "required": true}
+					]
+				}
+			}
+		},
+		"definitions": {
+			"main.UserPatchRequest": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"nickname": {"type": "string", "x-nullable": true},
+					"updated_at": {"type": "string", "format": "date-time"}
+				}
+			}
+		}
+	}`
+
+	bodyProperty := func(t *testing.T, spec *SwaggerSpec, name string) map[string]any {
+		t.Helper()
+		schema, err := spec.GetOperationSchema("PATCH", "/users/:id")
+		require.NoError(t, err)
+		body := schema["properties"].(map[string]any)["body"].(map[string]any)
+		return body["properties"].(map[string]any)[name].(map[string]any)
+	}
+
+	t.Run("Should carry parameter and property formats", func(t *testing.T) {
+		spec, err := parseSpecDocument([]byte(swaggerJSON))
+		require.NoError(t, err)
+
+		schema, err := spec.GetOperationSchema("PATCH", "/users/:id")
+		require.NoError(t, err)
+
+		id := schema["properties"].(map[string]any)["id"].(map[string]any)
+		assert.Equal(t, "uuid", id["format"])
+		assert.Equal(t, "date-time", bodyProperty(t, spec, "updated_at")["format"])
+	})
+
+	t.Run("Should translate x-nullable into a type union", func(t *testing.T) {
+		spec, err := parseSpecDocument([]byte(swaggerJSON))
+		require.NoError(t, err)
+
+		assert.Equal(t, []any{"string", "null"}, bodyProperty(t, spec, "nickname")["type"])
+		assert.Equal(t, "string", bodyProperty(t, spec, "name")["type"])
+	})
+
+	t.Run("Should emit the nullable keyword when requested", func(t *testing.T) {
+		spec, err := parseSpecDocument([]byte(swaggerJSON))
+		require.NoError(t, err)
+		spec.NullableKeyword = true
+
+		nickname := bodyProperty(t, spec, "nickname")
+		assert.Equal(t, "string", nickname["type"])
+		assert.Equal(t, true, nickname["nullable"])
+	})
+
+	t.Run("Should read nullable from OpenAPI 3.0 schemas", func(t *testing.T) {
+		openAPIYAML := `
+openapi: 3.0.3
+paths:
+  /users:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                nickname:
+                  type: string
+                  nullable: true
+                birthday:
+                  type: string
+                  format: date
+`
+		spec, err := ParseOpenAPISchema(openAPIYAML)
+		require.NoError(t, err)
+
+		schema, err := spec.GetOperationSchema("POST", "/users")
+		require.NoError(t, err)
+
+		body := schema["properties"].(map[string]any)["body"].(map[string]any)
+		properties := body["properties"].(map[string]any)
+		assert.Equal(t, []any{"string", "null"}, properties["nickname"].(map[string]any)["type"])
+		assert.Equal(t, "date", properties["birthday"].(map[string]any)["format"])
+	})
+}
+
+func TestToolNameExtension(t *testing.T) {
+	t.Run("Should collect vendor extensions from swagger JSON", func(t *testing.T) {
+		spec, err := parseSpecDocument([]byte(`{
+			"swagger": "2.0",
+			"paths": {
+				"/users/{id}": {
+					"get": {"operationId": "getUserByID", "x-mcp-tool-name": "fetch_user", "x-internal": true}
+				}
+			}
+		}`))
+		require.NoError(t, err)
+
+		operation, _ := spec.GetOperation("GET", "/users/:id")
+		assert.Equal(t, "getUserByID", operation.OperationID)
+		assert.Equal(t, map[string]any{"x-mcp-tool-name": "fetch_user", "x-internal": true}, operation.Extensions)
+		assert.Equal(t, "fetch_user", spec.GetToolName("GET", "/users/:id"))
+	})
+
+	t.Run("Should collect vendor extensions from OpenAPI 3.0", func(t *testing.T) {
+		spec, err := ParseOpenAPISchema(`
+openapi: 3.0.3
+paths:
+  /users:
+    post:
+      operationId: createUser
+      x-mcp-tool-name: add_user
+`)
+		require.NoError(t, err)
+
+		assert.Equal(t, "add_user", spec.GetToolName("POST", "/users"))
+	})
+
+	t.Run("Should return an empty name without the extension", func(t *testing.T) {
+		spec := &SwaggerSpec{Paths: map[string]SwaggerPath{"/users": {"get": SwaggerOperation{}}}}
+
+		assert.Empty(t, spec.GetToolName("GET", "/users"))
+		assert.Empty(t, spec.GetToolName("GET", "/missing"))
+	})
+}
+
+func TestRawPathExtension(t *testing.T) {
+	t.Run("Should read raw path parameters from swagger JSON", func(t *testing.T) {
+		spec, err := parseSpecDocument([]byte(`{
+			"swagger": "2.0",
+			"paths": {
+				"/files/{path}/{rev}": {
+					"get": {"parameters": [
+						{"name": "path", "in": "path", "type": "string", "x-mcp-raw-path": true},
+						{"name": "rev", "in": "path", "type": "string"}
+					]}
+				}
+			}
+		}`))
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"path"}, spec.GetRawPathParameters("GET", "/files/:path/:rev"))
+		assert.Empty(t, spec.GetRawPathParameters("GET", "/missing"))
+	})
+
+	t.Run("Should read raw path parameters from OpenAPI 3.0", func(t *testing.T) {
+		spec, err := ParseOpenAPISchema(`
+openapi: 3.0.3
+paths:
+  /files/{path}:
+    get:
+      parameters:
+        - name: path
+          in: path
+          required: true
+          x-mcp-raw-path: true
+          schema:
+            type: string
+`)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"path"}, spec.GetRawPathParameters("GET", "/files/:path"))
+	})
+}
+
+func TestExcludeExtension(t *testing.T) {
+	spec, err := parseSpecDocument([]byte(`{
+		"swagger": "2.0",
+		"paths": {
+			"/admin/reset": {
+				"post": {"x-mcp-exclude": true}
+			},
+			"/users": {
+				"get": {"x-mcp-exclude": false},
+				"post": {}
+			}
+		}
+	}`))
+	require.NoError(t, err)
+
+	t.Run("Should exclude operations setting x-mcp-exclude to true", func(t *testing.T) {
+		assert.True(t, spec.IsExcluded("POST", "/admin/reset"))
+	})
+
+	t.Run("Should keep other operations", func(t *testing.T) {
+		assert.False(t, spec.IsExcluded("GET", "/users"))
+		assert.False(t, spec.IsExcluded("POST", "/users"))
+		assert.False(t, spec.IsExcluded("GET", "/undocumented"))
+	})
+}
@@ -0,0 +1,79 @@
+package swagger
+
+import (
+	"slices"
+	"strings"
+)
+
+// TaggedOperation is a swagger operation together with the method and path it is defined on
+type TaggedOperation struct {
+	Operation SwaggerOperation
+	Method    string
+	Path      string
+}
+
+// GetTaggedOperations returns every operation tagged with at least one of the given tags
+// (union semantics). Operations are sorted by path and then by method; an empty tag list
+// returns no operations. It enumerates the spec only: the IncludeTags and ExcludeTags route
+// filters also consider the tags of registered schemas.
+func (spec *SwaggerSpec) GetTaggedOperations(tags []string) []TaggedOperation {
+	return spec.taggedOperations(tags, func(operationTags []string) bool {
+		return slices.ContainsFunc(operationTags, func(tag string) bool { return slices.Contains(tags, tag) })
+	})
+}
+
+// GetOperationsWithAllTags returns every operation tagged with all of the given tags
+// (intersection semantics), sorted like GetTaggedOperations. An empty tag list returns
+// no operations.
+func (spec *SwaggerSpec) GetOperationsWithAllTags(tags []string) []TaggedOperation {
+	return spec.taggedOperations(tags, func(operationTags []string) bool {
+		return !slices.ContainsFunc(tags, func(tag string) bool { return !slices.Contains(operationTags, tag) })
+	})
+}
+
+// taggedOperations returns the operations whose tags satisfy match, sorted by path and method
+func (spec *SwaggerSpec) taggedOperations(tags []string, match func(operationTags []string) bool) []TaggedOperation {
+	var operations []TaggedOperation
+
+	if len(tags) == 0 {
+		return operations
+	}
+
+	for path, pathSpec := range spec.Paths {
+		for method, operation := range pathSpec {
+			if match(operation.Tags) {
+				operations = append(operations, TaggedOperation{
+					Method:    strings.ToUpper(method),
+					Path:      path,
+					Operation: operation,
+				})
+			}
+		}
+	}
+
+	slices.SortFunc(operations, func(a, b TaggedOperation) int {
+		if c := strings.Compare(a.Path, b.Path); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Method, b.Method)
+	})
+
+	return operations
+}
+
+// ListTags returns the unique tags used by any operation of the spec, sorted alphabetically.
+func (spec *SwaggerSpec) ListTags() []string {
+	var tags []string
+	for _, pathSpec := range spec.Paths {
+		for _, operation := range pathSpec {
+			for _, tag := range operation.Tags {
+				if !slices.Contains(tags, tag) {
+					tags = append(tags, tag)
+				}
+			}
+		}
+	}
+
+	slices.Sort(tags)
+	return tags
+}
@@ -0,0 +1,137 @@
+package swagger
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Severity indicates how a ValidationWarning affects the generated MCP tools
+type Severity string
+
+const (
+	// SeverityWarning marks a spec problem that degrades tool descriptions
+	SeverityWarning Severity = "warning"
+	// SeverityError marks a spec problem that yields incomplete or wrong tool schemas
+	SeverityError Severity = "error"
+)
+
+// ValidationWarning is a problem found by ValidateForMCP. Path locates it in the spec,
+// either as "METHOD /path" for operations or "#/definitions/Name" for definitions.
+type ValidationWarning struct {
+	Severity Severity
+	Message  string
+	Path     string
+}
+
+// String formats the warning as "severity: path: message"
+func (w ValidationWarning) String() string {
+	return fmt.Sprintf("%s: %s: %s", w.Severity, w.Path, w.Message)
+}
+
+// swaggerPathParameter matches the {name} path parameters of a swagger path
+var swaggerPathParameter = regexp.MustCompile(`\{([^}]+)\}`)
+
+// ValidateForMCP checks that a swagger spec describes its operations well enough to be
+// exposed as MCP tools: every operation has a summary or description, every path parameter
+// is declared, every $ref resolves to a definition and every 2xx response schema resolves.
+// Warnings are sorted by path for stable output; a nil spec returns none.
+func ValidateForMCP(spec *SwaggerSpec) []ValidationWarning {
+	if spec == nil {
+		return nil
+	}
+
+	var warnings []ValidationWarning
+	add := func(severity Severity, path, format string, args ...any) {
+		warnings = append(warnings, ValidationWarning{Severity: severity, Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for path, pathSpec := range spec.Paths {
+		for method, operation := range pathSpec {
+			location := strings.ToUpper(method) + " " + path
+
+			if operation.Summary == "" && operation.Description == "" {
+				add(SeverityWarning, location, "operation has no summary or description")
+			}
+
+			for _, match := range swaggerPathParameter.FindAllStringSubmatch(path, -1) {
+				declared := slices.ContainsFunc(operation.Parameters, func(param SwaggerParameter) bool {
+					return param.In == "path" && param.Name == match[1]
+				})
+				if !declared {
+					add(SeverityError, location, "path parameter '%s' is not declared in parameters", match[1])
+				}
+			}
+
+			for _, param := range operation.Parameters {
+				for _, ref := range spec.unresolvedRefs(param.Schema) {
+					add(SeverityError, location, "parameter '%s' references missing definition %s", param.Name, ref)
+				}
+			}
+
+			for code, response := range operation.Responses {
+				unresolved := spec.unresolvedRefs(response.Schema)
+				if strings.HasPrefix(code, "2") && len(unresolved) > 0 {
+					add(SeverityError, location, "response %s schema is not resolvable: missing definition %s", code, strings.Join(unresolved, ", "))
+					continue
+				}
+				for _, ref := range unresolved {
+					add(SeverityError, location, "response %s references missing definition %s", code, ref)
+				}
+			}
+		}
+	}
+
+	for name, definition := range spec.Definitions {
+		for _, ref := range spec.unresolvedRefs(definition) {
+			add(SeverityError, "#/definitions/"+name, "references missing definition %s", ref)
+		}
+	}
+
+	slices.SortStableFunc(warnings, func(a, b ValidationWarning) int {
+		if c := strings.Compare(a.Path, b.Path); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Message, b.Message)
+	})
+	return warnings
+}
+
+// unresolvedRefs returns the $ref values in a schema, including nested schemas, that do
+// not point to an existing definition. Referenced definitions are not followed; they are
+// validated on their own.
+func (spec *SwaggerSpec) unresolvedRefs(schema *SwaggerSchema) []string {
+	if schema == nil {
+		return nil
+	}
+
+	var unresolved []string
+	if schema.Ref != "" && !spec.hasDefinition(schema.Ref) {
+		unresolved = append(unresolved, schema.Ref)
+	}
+
+	nested := []*SwaggerSchema{schema.Items, schema.AdditionalProperties}
+	for _, property := range schema.Properties {
+		nested = append(nested, property)
+	}
+	nested = append(nested, schema.AllOf...)
+	nested = append(nested, schema.AnyOf...)
+	nested = append(nested, schema.OneOf...)
+	for _, child := range nested {
+		unresolved = append(unresolved, spec.unresolvedRefs(child)...)
+	}
+
+	slices.Sort(unresolved)
+	return slices.Compact(unresolved)
+}
+
+// hasDefinition reports whether a local $ref (e.g. "#/definitions/main.User") resolves
+func (spec *SwaggerSpec) hasDefinition(ref string) bool {
+	refParts := strings.Split(ref, "/")
+	if len(refParts) < 3 || refParts[0] != "#" || (refParts[1] != "definitions" && refParts[1] != "components") {
+		return false
+	}
+	_, exists := spec.Definitions[refParts[2]]
+	return exists
+}
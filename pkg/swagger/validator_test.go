@@ -0,0 +1,63 @@
+package swagger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateForMCP(t *testing.T) {
+	t.Run("Should report nothing for a complete spec", func(t *testing.T) {
+		spec := &SwaggerSpec{
+			Paths: map[string]SwaggerPath{
+				"/users/{id}": {
+					"get": SwaggerOperation{
+						Summary:    "Get user",
+						Parameters: []SwaggerParameter{{Name: "id", In: "path", Type: "string"}},
+						Responses:  map[string]SwaggerResponse{"200": {Schema: &SwaggerSchema{Ref: "#/definitions/User"}}},
+					},
+				},
+			},
+			Definitions: map[string]*SwaggerSchema{
+				"User": {Type: "object", Properties: map[string]*SwaggerSchema{"name": {Type: "string"}}},
+			},
+		}
+
+		assert.Empty(t, ValidateForMCP(spec))
+	})
+
+	t.Run("Should report problems that degrade MCP tools", func(t *testing.T) {
+		spec := &SwaggerSpec{
+			Paths: map[string]SwaggerPath{
+				"/users/{id}": {
+					"put": SwaggerOperation{
+						Description: "Update user",
+						Parameters:  []SwaggerParameter{{Name: "body", In: "body", Schema: &SwaggerSchema{Ref: "#/definitions/UserInput"}}},
+						Responses: map[string]SwaggerResponse{
+							"200": {Schema: &SwaggerSchema{Type: "array", Items: &SwaggerSchema{Ref: "#/definitions/User"}}},
+							"400": {Schema: &SwaggerSchema{Ref: "#/definitions/Problem"}},
+						},
+					},
+				},
+				"/health": {
+					"get": SwaggerOperation{},
+				},
+			},
+			Definitions: map[string]*SwaggerSchema{
+				"UserInput": {Type: "object", Properties: map[string]*SwaggerSchema{"address": {Ref: "#/definitions/Address"}}},
+			},
+		}
+
+		assert.Equal(t, []ValidationWarning{
+			{Severity: SeverityError, Path: "#/definitions/UserInput", Message: "references missing definition #/definitions/Address"},
+			{Severity: SeverityWarning, Path: "GET /health", Message: "operation has no summary or description"},
+			{Severity: SeverityError, Path: "PUT /users/{id}", Message: "path parameter 'id' is not declared in parameters"},
+			{Severity: SeverityError, Path: "PUT /users/{id}", Message: "response 200 schema is not resolvable: missing definition #/definitions/User"},
+			{Severity: SeverityError, Path: "PUT /users/{id}", Message: "response 400 references missing definition #/definitions/Problem"},
+		}, ValidateForMCP(spec))
+	})
+
+	t.Run("Should accept a nil spec", func(t *testing.T) {
+		assert.Empty(t, ValidateForMCP(nil))
+	})
+}
@@ -0,0 +1,104 @@
+// Package testing provides an integration test harness that serves Echo routes and
+// their MCP endpoint over a real HTTP server, so tests exercise the full MCP round trip.
+package testing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	gotesting "testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/labstack/echo/v4"
+
+	server "github.com/BrunoKrugel/echo-mcp"
+	"github.com/BrunoKrugel/echo-mcp/pkg/client"
+)
+
+// MountPath is the path the MCP endpoint is mounted at by StartTestServer
+const MountPath = "/mcp"
+
+// TestRoute is a route registered by StartTestServer. Routes without a Handler get a
+// fake handler echoing the request method, path, path parameters and query parameters.
+type TestRoute struct {
+	Handler echo.HandlerFunc
+	Method  string
+	Path    string
+}
+
+// StartTestServer registers routes on a new Echo instance, mounts an MCP server on it at
+// MountPath and serves both through an httptest.Server closed when the test ends. It
+// returns the MCP server and the base URL of the HTTP server.
+//
+// Example:
+//
+//	mcp, baseURL := mcptesting.StartTestServer(t, []mcptesting.TestRoute{
+//		{Method: http.MethodGet, Path: "/users/:id"},
+//	})
+//	result := mcptesting.CallTool(t, baseURL, "GET_users_id", map[string]any{"id": "1"})
+func StartTestServer(t *gotesting.T, routes []TestRoute) (*server.EchoMCP, string) {
+	t.Helper()
+
+	e := echo.New()
+	for _, route := range routes {
+		handler := route.Handler
+		if handler == nil {
+			handler = fakeHandler
+		}
+		e.Add(route.Method, route.Path, handler)
+	}
+
+	mcp := server.New(e)
+	if err := mcp.Mount(MountPath); err != nil {
+		t.Fatalf("failed to mount MCP server: %v", err)
+	}
+
+	httpServer := httptest.NewServer(e)
+	t.Cleanup(httpServer.Close)
+
+	return mcp, httpServer.URL
+}
+
+// CallTool initializes an MCP session with the server at baseURL, calls the named tool and
+// returns the tool call result (content, structuredContent) as a map. It fails the test on
+// transport or JSON-RPC errors.
+func CallTool(t *gotesting.T, baseURL, toolName string, args map[string]any) map[string]any {
+	t.Helper()
+
+	ctx := context.Background()
+	c := client.NewClient(baseURL + MountPath)
+	if _, err := c.Initialize(ctx); err != nil {
+		t.Fatalf("failed to initialize MCP session: %v", err)
+	}
+
+	result, err := c.CallTool(ctx, toolName, args)
+	if err != nil {
+		t.Fatalf("failed to call tool %s: %v", toolName, err)
+	}
+
+	data, err := sonic.Marshal(result)
+	if err != nil {
+		t.Fatalf("failed to encode tool %s result: %v", toolName, err)
+	}
+	var resultMap map[string]any
+	if err := sonic.Unmarshal(data, &resultMap); err != nil {
+		t.Fatalf("failed to decode tool %s result: %v", toolName, err)
+	}
+
+	return resultMap
+}
+
+// fakeHandler responds with the request it received
+func fakeHandler(c echo.Context) error {
+	params := make(map[string]string, len(c.ParamNames()))
+	for i, name := range c.ParamNames() {
+		params[name] = c.ParamValues()[i]
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"method": c.Request().Method,
+		"path":   c.Path(),
+		"params": params,
+		"query":  c.QueryParams(),
+	})
+}
@@ -0,0 +1,48 @@
+package testing
+
+import (
+	"net/http"
+	gotesting "testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartTestServer(t *gotesting.T) {
+	t.Run("Should expose the routes as tools", func(t *gotesting.T) {
+		mcp, baseURL := StartTestServer(t, []TestRoute{
+			{Method: http.MethodGet, Path: "/users/:id"},
+			{Method: http.MethodPost, Path: "/users"},
+		})
+
+		assert.NotEmpty(t, baseURL)
+		assert.Contains(t, mcp.GetOperations(), "GET_users_id")
+		assert.Contains(t, mcp.GetOperations(), "POST_users")
+	})
+
+	t.Run("Should call tools through the MCP endpoint", func(t *gotesting.T) {
+		_, baseURL := StartTestServer(t, []TestRoute{
+			{Method: http.MethodGet, Path: "/users/:id", Handler: func(c echo.Context) error {
+				return c.String(http.StatusOK, "user "+c.Param("id"))
+			}},
+		})
+
+		result := CallTool(t, baseURL, "GET_users_id", map[string]any{"id": "42"})
+
+		content, ok := result["content"].([]any)
+		require.True(t, ok)
+		require.Len(t, content, 1)
+		assert.Equal(t, "user 42", content[0].(map[string]any)["text"])
+	})
+
+	t.Run("Should serve fake handlers echoing the request", func(t *gotesting.T) {
+		_, baseURL := StartTestServer(t, []TestRoute{{Method: http.MethodGet, Path: "/orders/:id"}})
+
+		result := CallTool(t, baseURL, "GET_orders_id", map[string]any{"id": "7"})
+
+		text := result["content"].([]any)[0].(map[string]any)["text"]
+		assert.Contains(t, text, "/orders/:id")
+		assert.Contains(t, text, "id:7")
+	})
+}
@@ -0,0 +1,54 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+)
+
+type contextKey int
+
+const (
+	sessionIDKey contextKey = iota
+	requestKey
+	notifierKey
+)
+
+// Notifier sends a server-initiated message (such as a progress notification)
+// to the client while a request is still being handled
+type Notifier func(msg *types.MCPMessage) error
+
+// WithSessionID returns a copy of ctx carrying the MCP session ID
+func WithSessionID(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionIDKey, sessionID)
+}
+
+// SessionIDFromContext returns the MCP session ID carried by ctx, or an empty string
+func SessionIDFromContext(ctx context.Context) string {
+	sessionID, _ := ctx.Value(sessionIDKey).(string)
+	return sessionID
+}
+
+// WithRequest returns a copy of ctx carrying the inbound MCP HTTP request
+func WithRequest(ctx context.Context, req *http.Request) context.Context {
+	return context.WithValue(ctx, requestKey, req)
+}
+
+// RequestFromContext returns the inbound MCP HTTP request carried by ctx, or nil
+func RequestFromContext(ctx context.Context) *http.Request {
+	req, _ := ctx.Value(requestKey).(*http.Request)
+	return req
+}
+
+// WithNotifier returns a copy of ctx carrying a notifier for the current request
+func WithNotifier(ctx context.Context, notifier Notifier) context.Context {
+	return context.WithValue(ctx, notifierKey, notifier)
+}
+
+// NotifierFromContext returns the notifier carried by ctx, or nil if the
+// transport cannot send messages before the response
+func NotifierFromContext(ctx context.Context) Notifier {
+	notifier, _ := ctx.Value(notifierKey).(Notifier)
+	return notifier
+}
@@ -1,35 +1,49 @@
 package transport
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+	"github.com/bytedance/sonic"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	log "github.com/sirupsen/logrus"
 )
 
 type HTTPTransport struct {
-	handlers  map[string]MessageHandler
-	sessions  map[string]*Session
-	mountPath string
-	mu        sync.RWMutex
+	handlers        map[string]MessageHandler
+	contextHandlers map[string]ContextMessageHandler
+	sessions        map[string]*Session
+	inFlight        map[string]context.CancelFunc
+	inFlightCounts  map[string]int
+	discovery       func() any
+	mountPath       string
+	sessionClosed   []func(sessionID string)
+	sessionTTL      time.Duration
+	mu              sync.RWMutex
 }
 
 type Session struct {
-	ID      string
-	Created int64
+	ID         string
+	Created    int64
+	LastActive int64
 }
 
 // NewHTTPTransport creates a new HTTP transport
 func NewHTTPTransport(mountPath string) *HTTPTransport {
 	return &HTTPTransport{
-		mountPath: mountPath,
-		handlers:  make(map[string]MessageHandler),
-		sessions:  make(map[string]*Session),
+		mountPath:       mountPath,
+		handlers:        make(map[string]MessageHandler),
+		contextHandlers: make(map[string]ContextMessageHandler),
+		sessions:        make(map[string]*Session),
+		inFlight:        make(map[string]context.CancelFunc),
+		inFlightCounts:  make(map[string]int),
 	}
 }
 
@@ -38,6 +52,30 @@ func (h *HTTPTransport) RegisterHandler(method string, handler MessageHandler) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	h.handlers[method] = handler
+	delete(h.contextHandlers, method)
+}
+
+// RegisterContextHandler registers a context-aware message handler
+func (h *HTTPTransport) RegisterContextHandler(method string, handler ContextMessageHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.contextHandlers[method] = handler
+	delete(h.handlers, method)
+}
+
+// SetSessionTTL sets how long a session may stay idle before it expires.
+// A zero TTL (the default) keeps sessions alive for the lifetime of the transport.
+func (h *HTTPTransport) SetSessionTTL(ttl time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sessionTTL = ttl
+}
+
+// OnSessionClosed registers a callback invoked when a session expires or is terminated
+func (h *HTTPTransport) OnSessionClosed(callback func(sessionID string)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sessionClosed = append(h.sessionClosed, callback)
 }
 
 // MountPath returns the mount path
@@ -45,18 +83,45 @@ func (h *HTTPTransport) MountPath() string {
 	return h.mountPath
 }
 
-// HandleConnection handles incoming MCP connections (not used in HTTP transport)
+// SetDiscovery sets the function building the server metadata document
+// returned to GET requests on the MCP endpoint
+func (h *HTTPTransport) SetDiscovery(discovery func() any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.discovery = discovery
+}
+
+// HandleConnection handles GET requests to the MCP endpoint. Requests without an
+// Upgrade: websocket header receive the server discovery metadata as JSON when it is
+// configured, without creating a session; any other GET is not allowed.
 func (h *HTTPTransport) HandleConnection(c echo.Context) error {
+	h.mu.RLock()
+	discovery := h.discovery
+	h.mu.RUnlock()
+
+	if discovery != nil && !isWebSocketUpgrade(c.Request()) {
+		return c.JSON(http.StatusOK, discovery())
+	}
+
 	return echo.NewHTTPError(http.StatusMethodNotAllowed, "GET method not supported for HTTP transport")
 }
 
+// isWebSocketUpgrade reports whether the request asks to upgrade to a WebSocket connection
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get(echo.HeaderUpgrade), "websocket")
+}
+
 // HandleMessage processes incoming MCP messages via POST
 func (h *HTTPTransport) HandleMessage(c echo.Context) error {
 	sessionID := c.Request().Header.Get("Mcp-Session-Id")
 
 	var msg types.MCPMessage
 	if err := c.Bind(&msg); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid message format")
+		// Answer with a JSON-RPC parse error as the body of the 400 response
+		return echo.NewHTTPError(http.StatusBadRequest, &types.MCPMessage{
+			Jsonrpc: "2.0",
+			Error:   types.NewMCPError(types.ErrCodeParseError, "Invalid message format", nil),
+		})
 	}
 
 	if msg.Method == "initialize" {
@@ -67,25 +132,111 @@ func (h *HTTPTransport) HandleMessage(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound, "Session not found")
 	}
 
-	response := h.processMessage(&msg)
+	// Cancellation notifications abort the matching in-flight request
+	if msg.Method == "notifications/cancelled" {
+		h.cancelRequest(sessionID, msg.Params)
+		return c.NoContent(http.StatusAccepted)
+	}
+
+	ctx := WithSessionID(WithRequest(c.Request().Context(), c.Request()), sessionID)
+
+	// Track requests so a later notifications/cancelled can abort them
+	if len(msg.ID) > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		h.trackRequest(sessionID, string(msg.ID), cancel)
+		defer func() {
+			h.untrackRequest(sessionID, string(msg.ID))
+			cancel()
+		}()
+	}
+
+	// Clients accepting SSE can receive notifications before the response
+	if acceptsEventStream(c.Request()) {
+		stream := &eventStream{w: c.Response()}
+		response := h.processMessage(WithNotifier(ctx, stream.send), &msg)
+		if stream.isStarted() {
+			return stream.send(response)
+		}
+		return c.JSON(http.StatusOK, response)
+	}
+
+	response := h.processMessage(ctx, &msg)
 
 	return c.JSON(http.StatusOK, response)
 }
 
+// trackRequest registers the cancel function of an in-flight request
+func (h *HTTPTransport) trackRequest(sessionID, requestID string, cancel context.CancelFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.inFlight[inFlightKey(sessionID, requestID)] = cancel
+	h.inFlightCounts[sessionID]++
+}
+
+// untrackRequest removes a completed request
+func (h *HTTPTransport) untrackRequest(sessionID, requestID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.inFlight, inFlightKey(sessionID, requestID))
+	if h.inFlightCounts[sessionID]--; h.inFlightCounts[sessionID] <= 0 {
+		delete(h.inFlightCounts, sessionID)
+	}
+}
+
+// InFlight returns the number of requests of a session (an empty ID for requests sent
+// without a session) that are still being handled, for metrics and audit logs
+func (h *HTTPTransport) InFlight(sessionID string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.inFlightCounts[sessionID]
+}
+
+// cancelRequest cancels the in-flight request named by the requestId of a
+// notifications/cancelled message, ignoring unknown or completed requests
+func (h *HTTPTransport) cancelRequest(sessionID string, params any) {
+	paramMap, ok := params.(map[string]any)
+	if !ok {
+		return
+	}
+
+	requestID, err := sonic.Marshal(paramMap["requestId"])
+	if err != nil {
+		return
+	}
+
+	h.mu.RLock()
+	cancel, exists := h.inFlight[inFlightKey(sessionID, string(requestID))]
+	h.mu.RUnlock()
+
+	if exists {
+		log.Debugf("[HTTP] Cancelling request %s: %v", requestID, paramMap["reason"])
+		cancel()
+	}
+}
+
+// inFlightKey identifies a request ID within a session
+func inFlightKey(sessionID, requestID string) string {
+	return sessionID + "/" + requestID
+}
+
 // handleInitialize specifically handles initialize requests
 func (h *HTTPTransport) handleInitialize(c echo.Context, msg *types.MCPMessage) error {
-	response := h.processMessage(msg)
-
 	sessionID := h.createSession()
+
+	ctx := WithSessionID(WithRequest(c.Request().Context(), c.Request()), sessionID)
+	response := h.processMessage(ctx, msg)
+
 	c.Response().Header().Set("Mcp-Session-Id", sessionID)
 
 	return c.JSON(http.StatusOK, response)
 }
 
 // processMessage handles an incoming MCP message and returns a response
-func (h *HTTPTransport) processMessage(msg *types.MCPMessage) *types.MCPMessage {
+func (h *HTTPTransport) processMessage(ctx context.Context, msg *types.MCPMessage) *types.MCPMessage {
 	h.mu.RLock()
 	handler, exists := h.handlers[msg.Method]
+	contextHandler, contextExists := h.contextHandlers[msg.Method]
 	h.mu.RUnlock()
 
 	response := &types.MCPMessage{
@@ -93,20 +244,29 @@ func (h *HTTPTransport) processMessage(msg *types.MCPMessage) *types.MCPMessage
 		ID:      msg.ID,
 	}
 
-	if !exists {
-		response.Error = &types.MCPError{
-			Code:    -32601,
-			Message: fmt.Sprintf("Method '%s' not found", msg.Method),
-		}
+	if msg.Method == "" {
+		response.Error = types.NewMCPError(types.ErrCodeInvalidRequest, "Request has no method", nil)
 		return response
 	}
 
-	result, err := handler(msg.Params)
-	if err != nil {
-		response.Error = &types.MCPError{
-			Code:    -32603,
-			Message: err.Error(),
-		}
+	if !exists && !contextExists {
+		response.Error = types.NewMCPError(types.ErrCodeMethodNotFound, fmt.Sprintf("Method '%s' not found", msg.Method), nil)
+		return response
+	}
+
+	var result any
+	var err error
+	if contextExists {
+		result, err = contextHandler(ctx, msg.Params)
+	} else {
+		result, err = handler(msg.Params)
+	}
+
+	var mcpErr *types.MCPError
+	if errors.As(err, &mcpErr) {
+		response.Error = mcpErr
+	} else if err != nil {
+		response.Error = types.NewMCPError(types.ErrCodeInternalError, err.Error(), nil)
 	} else {
 		response.Result = result
 	}
@@ -114,26 +274,120 @@ func (h *HTTPTransport) processMessage(msg *types.MCPMessage) *types.MCPMessage
 	return response
 }
 
-// createSession creates a new session
+// createSession creates a new session and expires idle ones
 func (h *HTTPTransport) createSession() string {
 	h.mu.Lock()
-	defer h.mu.Unlock()
+
+	now := time.Now().Unix()
+	expired := h.expireSessionsLocked(now)
 
 	sessionID := uuid.New().String()
 	h.sessions[sessionID] = &Session{
-		ID:      sessionID,
-		Created: time.Now().Unix(),
+		ID:         sessionID,
+		Created:    now,
+		LastActive: now,
 	}
 
+	callbacks := h.sessionClosed
+	h.mu.Unlock()
+
+	notifySessionsClosed(callbacks, expired)
+
 	return sessionID
 }
 
-// isValidSession checks if a session ID is valid
+// isValidSession checks if a session ID is valid and refreshes its activity timestamp
 func (h *HTTPTransport) isValidSession(sessionID string) bool {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
-	_, exists := h.sessions[sessionID]
-	return exists
+	h.mu.Lock()
+
+	session, exists := h.sessions[sessionID]
+	if !exists {
+		h.mu.Unlock()
+		return false
+	}
+
+	now := time.Now().Unix()
+	if h.isExpired(session, now) {
+		delete(h.sessions, sessionID)
+		callbacks := h.sessionClosed
+		h.mu.Unlock()
+
+		notifySessionsClosed(callbacks, []string{sessionID})
+		return false
+	}
+
+	session.LastActive = now
+	h.mu.Unlock()
+
+	return true
+}
+
+// isExpired reports whether a session has been idle for longer than the session TTL
+func (h *HTTPTransport) isExpired(session *Session, now int64) bool {
+	if h.sessionTTL <= 0 {
+		return false
+	}
+	return time.Duration(now-session.LastActive)*time.Second > h.sessionTTL
+}
+
+// expireSessionsLocked removes idle sessions and returns their IDs; h.mu must be held
+func (h *HTTPTransport) expireSessionsLocked(now int64) []string {
+	var expired []string
+	for id, session := range h.sessions {
+		if h.isExpired(session, now) {
+			delete(h.sessions, id)
+			expired = append(expired, id)
+		}
+	}
+	return expired
+}
+
+// notifySessionsClosed invokes the session closed callbacks for each expired session
+func notifySessionsClosed(callbacks []func(sessionID string), sessionIDs []string) {
+	for _, sessionID := range sessionIDs {
+		for _, callback := range callbacks {
+			callback(sessionID)
+		}
+	}
+}
+
+// HandleSessionClose terminates the session named by the Mcp-Session-Id header of a DELETE
+// request, invoking the session closed callbacks so its state is released
+func (h *HTTPTransport) HandleSessionClose(c echo.Context) error {
+	sessionID := c.Request().Header.Get("Mcp-Session-Id")
+	if sessionID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing Mcp-Session-Id header")
+	}
+
+	h.mu.Lock()
+	if _, exists := h.sessions[sessionID]; !exists {
+		h.mu.Unlock()
+		return echo.NewHTTPError(http.StatusNotFound, "Session not found")
+	}
+	delete(h.sessions, sessionID)
+	callbacks := h.sessionClosed
+	h.mu.Unlock()
+
+	notifySessionsClosed(callbacks, []string{sessionID})
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Close clears every session, invoking the session closed callbacks. Event streams are
+// bound to their POST request and end with its response, so there is nothing else to close.
+func (h *HTTPTransport) Close(ctx context.Context) error {
+	h.mu.Lock()
+	closed := make([]string, 0, len(h.sessions))
+	for id := range h.sessions {
+		closed = append(closed, id)
+	}
+	clear(h.sessions)
+	callbacks := h.sessionClosed
+	h.mu.Unlock()
+
+	notifySessionsClosed(callbacks, closed)
+
+	return nil
 }
 
 // NotifyToolsChanged sends a tools changed notification (not applicable for HTTP transport)
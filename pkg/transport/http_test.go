@@ -2,6 +2,7 @@ package transport
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -121,6 +122,39 @@ func TestHTTPTransport_HandleConnection(t *testing.T) {
 		assert.Equal(t, http.StatusMethodNotAllowed, httpErr.Code)
 		assert.Contains(t, httpErr.Message.(string), "GET method not supported")
 	})
+
+	t.Run("Should return discovery metadata to GET requests", func(t *testing.T) {
+		transport := NewHTTPTransport("/mcp")
+		transport.SetDiscovery(func() any {
+			return map[string]string{"name": "test-server"}
+		})
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/mcp", http.NoBody)
+		req.Header.Set("Accept", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		require.NoError(t, transport.HandleConnection(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"name":"test-server"}`, rec.Body.String())
+	})
+
+	t.Run("Should not allow WebSocket upgrade requests", func(t *testing.T) {
+		transport := NewHTTPTransport("/mcp")
+		transport.SetDiscovery(func() any { return map[string]string{} })
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/mcp", http.NoBody)
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		httpErr := &echo.HTTPError{}
+		require.ErrorAs(t, transport.HandleConnection(c), &httpErr)
+		assert.Equal(t, http.StatusMethodNotAllowed, httpErr.Code)
+	})
 }
 
 func TestHTTPTransport_HandleMessage(t *testing.T) {
@@ -260,6 +294,21 @@ func TestHTTPTransport_HandleMessage(t *testing.T) {
 		err := transport.HandleMessage(c)
 
 		assert.Error(t, err)
+
+		e.HTTPErrorHandler(err, c)
+		var response types.MCPMessage
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, types.ErrCodeParseError, response.Error.Code)
+	})
+
+	t.Run("Should reject requests without a method", func(t *testing.T) {
+		transport := NewHTTPTransport("/mcp")
+
+		response := transport.processMessage(context.Background(), &types.MCPMessage{Jsonrpc: "2.0", ID: json.RawMessage(`1`)})
+
+		require.NotNil(t, response.Error)
+		assert.Equal(t, types.ErrCodeInvalidRequest, response.Error.Code)
 	})
 
 	t.Run("Should handle missing handler", func(t *testing.T) {
@@ -470,3 +519,271 @@ func TestHTTPTransport_Integration(t *testing.T) {
 		assert.Contains(t, content["text"], "Executed test_tool successfully")
 	})
 }
+
+func TestHTTPTransport_ContextHandler(t *testing.T) {
+	t.Run("Should pass session ID and inbound request to context handlers", func(t *testing.T) {
+		transport := NewHTTPTransport("/mcp")
+		sessionID := transport.createSession()
+
+		var gotSessionID string
+		var gotCookie string
+		transport.RegisterContextHandler("tools/call", func(ctx context.Context, params any) (any, error) {
+			gotSessionID = SessionIDFromContext(ctx)
+			gotCookie = RequestFromContext(ctx).Header.Get("Cookie")
+			return "ok", nil
+		})
+
+		message := types.MCPMessage{
+			Jsonrpc: "2.0",
+			ID:      json.RawMessage(`"call-id"`),
+			Method:  "tools/call",
+			Params:  map[string]any{},
+		}
+		msgBytes, err := sonic.Marshal(message)
+		require.NoError(t, err)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/mcp", bytes.NewBuffer(msgBytes))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("Mcp-Session-Id", sessionID)
+		req.Header.Set("Cookie", "session=abc")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err = transport.HandleMessage(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, sessionID, gotSessionID)
+		assert.Equal(t, "session=abc", gotCookie)
+	})
+}
+
+func TestHTTPTransport_SessionExpiry(t *testing.T) {
+	t.Run("Should expire idle sessions and notify callbacks", func(t *testing.T) {
+		transport := NewHTTPTransport("/mcp")
+		transport.SetSessionTTL(time.Second)
+
+		var closed []string
+		transport.OnSessionClosed(func(sessionID string) {
+			closed = append(closed, sessionID)
+		})
+
+		sessionID := transport.createSession()
+		assert.True(t, transport.isValidSession(sessionID))
+
+		transport.mu.Lock()
+		transport.sessions[sessionID].LastActive -= 10
+		transport.mu.Unlock()
+
+		assert.False(t, transport.isValidSession(sessionID))
+		assert.Equal(t, []string{sessionID}, closed)
+	})
+
+	t.Run("Should keep sessions alive without TTL", func(t *testing.T) {
+		transport := NewHTTPTransport("/mcp")
+
+		sessionID := transport.createSession()
+
+		transport.mu.Lock()
+		transport.sessions[sessionID].LastActive -= 3600
+		transport.mu.Unlock()
+
+		assert.True(t, transport.isValidSession(sessionID))
+	})
+}
+
+func TestHTTPTransport_MCPError(t *testing.T) {
+	t.Run("Should preserve code and data of MCP errors returned by handlers", func(t *testing.T) {
+		transport := NewHTTPTransport("/mcp")
+		transport.RegisterHandler("tools/call", func(params any) (any, error) {
+			return nil, &types.MCPError{
+				Code:    types.ErrCodeInvalidParams,
+				Message: "unknown tool",
+				Data:    map[string]any{"suggestions": []string{"GET_users"}},
+			}
+		})
+
+		response := transport.processMessage(context.Background(), &types.MCPMessage{
+			Jsonrpc: "2.0",
+			ID:      json.RawMessage(`"1"`),
+			Method:  "tools/call",
+		})
+
+		require.NotNil(t, response.Error)
+		assert.Equal(t, types.ErrCodeInvalidParams, response.Error.Code)
+		assert.Equal(t, "unknown tool", response.Error.Message)
+		assert.NotNil(t, response.Error.Data)
+	})
+}
+
+func TestHTTPTransport_Notifications(t *testing.T) {
+	newTransport := func() *HTTPTransport {
+		transport := NewHTTPTransport("/mcp")
+		transport.RegisterContextHandler("tools/call", func(ctx context.Context, params any) (any, error) {
+			notifier := NotifierFromContext(ctx)
+			if notifier == nil {
+				return "no notifier", nil
+			}
+			for i := 1; i <= 2; i++ {
+				err := notifier(&types.MCPMessage{
+					Jsonrpc: "2.0",
+					Method:  "notifications/progress",
+					Params:  map[string]any{"progressToken": "abc", "progress": i},
+				})
+				if err != nil {
+					return nil, err
+				}
+			}
+			return "done", nil
+		})
+		return transport
+	}
+
+	call := func(transport *HTTPTransport, accept string) *httptest.ResponseRecorder {
+		e := echo.New()
+		body := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{}}`
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		require.NoError(t, transport.HandleMessage(e.NewContext(req, rec)))
+		return rec
+	}
+
+	t.Run("Should stream notifications before the response when SSE is accepted", func(t *testing.T) {
+		rec := call(newTransport(), "application/json, text/event-stream")
+
+		assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+
+		events := strings.Split(strings.TrimSpace(rec.Body.String()), "\n\n")
+		require.Len(t, events, 3)
+		assert.Contains(t, events[0], `"method":"notifications/progress"`)
+		assert.Contains(t, events[0], `"progress":1`)
+		assert.Contains(t, events[1], `"progress":2`)
+		assert.Contains(t, events[2], `"result":"done"`)
+		assert.True(t, strings.HasPrefix(events[2], "event: message\ndata: "))
+	})
+
+	t.Run("Should not provide a notifier without SSE support", func(t *testing.T) {
+		rec := call(newTransport(), "")
+
+		assert.Contains(t, rec.Header().Get("Content-Type"), "application/json")
+		assert.Contains(t, rec.Body.String(), `"result":"no notifier"`)
+	})
+}
+
+func TestHTTPTransport_Cancellation(t *testing.T) {
+	newTransport := func(started chan<- struct{}) *HTTPTransport {
+		transport := NewHTTPTransport("/mcp")
+		transport.RegisterContextHandler("tools/call", func(ctx context.Context, params any) (any, error) {
+			close(started)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(5 * time.Second):
+				return "finished", nil
+			}
+		})
+		return transport
+	}
+
+	send := func(transport *HTTPTransport, body string) *httptest.ResponseRecorder {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		require.NoError(t, transport.HandleMessage(e.NewContext(req, rec)))
+		return rec
+	}
+
+	t.Run("Should cancel the in-flight request matching the requestId", func(t *testing.T) {
+		started := make(chan struct{})
+		transport := newTransport(started)
+
+		done := make(chan *httptest.ResponseRecorder)
+		go func() {
+			done <- send(transport, `{"jsonrpc":"2.0","id":"call-1","method":"tools/call","params":{}}`)
+		}()
+		<-started
+		assert.Equal(t, 1, transport.InFlight(""))
+
+		rec := send(transport, `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":"call-1","reason":"user aborted"}}`)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+
+		select {
+		case rec := <-done:
+			var response types.MCPMessage
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			require.NotNil(t, response.Error)
+			assert.Contains(t, response.Error.Message, "context canceled")
+		case <-time.After(2 * time.Second):
+			t.Fatal("request was not cancelled")
+		}
+
+		assert.Empty(t, transport.inFlight)
+		assert.Zero(t, transport.InFlight(""))
+	})
+
+	t.Run("Should ignore cancellations for unknown requests", func(t *testing.T) {
+		transport := NewHTTPTransport("/mcp")
+
+		rec := send(transport, `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":42}}`)
+
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+	})
+}
+
+func TestHTTPTransport_Close(t *testing.T) {
+	t.Run("Should clear sessions and notify callbacks", func(t *testing.T) {
+		transport := NewHTTPTransport("/mcp")
+		sessionID := transport.createSession()
+
+		var closed []string
+		transport.OnSessionClosed(func(id string) { closed = append(closed, id) })
+
+		require.NoError(t, transport.Close(context.Background()))
+
+		assert.Equal(t, []string{sessionID}, closed)
+		assert.False(t, transport.isValidSession(sessionID))
+	})
+}
+
+func TestHTTPTransport_HandleSessionClose(t *testing.T) {
+	send := func(transport *HTTPTransport, sessionID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodDelete, "/mcp", http.NoBody)
+		if sessionID != "" {
+			req.Header.Set("Mcp-Session-Id", sessionID)
+		}
+		rec := httptest.NewRecorder()
+		if err := transport.HandleSessionClose(echo.New().NewContext(req, rec)); err != nil {
+			var httpErr *echo.HTTPError
+			if errors.As(err, &httpErr) {
+				rec.Code = httpErr.Code
+			}
+		}
+		return rec
+	}
+
+	t.Run("Should close the session and notify callbacks", func(t *testing.T) {
+		transport := NewHTTPTransport("/mcp")
+		sessionID := transport.createSession()
+
+		var closed []string
+		transport.OnSessionClosed(func(id string) { closed = append(closed, id) })
+
+		rec := send(transport, sessionID)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, []string{sessionID}, closed)
+		assert.False(t, transport.isValidSession(sessionID))
+	})
+
+	t.Run("Should reject unknown and missing sessions", func(t *testing.T) {
+		transport := NewHTTPTransport("/mcp")
+
+		assert.Equal(t, http.StatusNotFound, send(transport, "unknown").Code)
+		assert.Equal(t, http.StatusBadRequest, send(transport, "").Code)
+	})
+}
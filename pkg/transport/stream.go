@@ -0,0 +1,59 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+)
+
+// eventStream lazily upgrades a POST response to a text/event-stream so
+// notifications can be sent before the final JSON-RPC response (Streamable HTTP).
+// Requests that send no notifications are answered with plain JSON.
+type eventStream struct {
+	w       http.ResponseWriter
+	mu      sync.Mutex
+	started bool
+}
+
+// acceptsEventStream reports whether the client accepts text/event-stream responses
+func acceptsEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// send writes msg as an SSE message event, starting the stream on first use
+func (s *eventStream) send(msg *types.MCPMessage) error {
+	data, err := sonic.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.started {
+		s.w.Header().Set("Content-Type", "text/event-stream")
+		s.w.Header().Set("Cache-Control", "no-cache")
+		s.w.WriteHeader(http.StatusOK)
+		s.started = true
+	}
+
+	if _, err := fmt.Fprintf(s.w, "event: message\ndata: %s\n\n", data); err != nil {
+		return err
+	}
+	if flusher, ok := s.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+// isStarted reports whether any event has been written
+func (s *eventStream) isStarted() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.started
+}
@@ -1,15 +1,31 @@
 package transport
 
-import "github.com/labstack/echo/v4"
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+)
 
 // MessageHandler defines the function signature for handling MCP messages
 type MessageHandler func(params any) (any, error)
 
+// ContextMessageHandler defines the function signature for handling MCP messages
+// that need access to the request context (session ID, inbound HTTP request)
+type ContextMessageHandler func(ctx context.Context, params any) (any, error)
+
 // Transport defines the interface for MCP transport mechanisms
 type Transport interface {
 	// RegisterHandler registers a message handler for a specific method
 	RegisterHandler(method string, handler MessageHandler)
 
+	// RegisterContextHandler registers a context-aware message handler for a specific method
+	RegisterContextHandler(method string, handler ContextMessageHandler)
+
+	// OnSessionClosed registers a callback invoked when a session expires or is terminated
+	OnSessionClosed(callback func(sessionID string))
+
 	// HandleConnection handles incoming MCP connections
 	HandleConnection(c echo.Context) error
 
@@ -21,4 +37,28 @@ type Transport interface {
 
 	// MountPath returns the path where this transport is mounted
 	MountPath() string
+
+	// Close releases the transport resources such as sessions and open streams
+	Close(ctx context.Context) error
+}
+
+// StreamingTransport is implemented by transports that can push server-initiated
+// notifications, such as tools/list_changed, to connected clients
+type StreamingTransport interface {
+	Transport
+
+	// SupportsStreaming reports whether server-initiated notifications reach the clients
+	SupportsStreaming() bool
+}
+
+// PushTransport is implemented by streaming transports that can send server-initiated
+// messages, such as log notifications, to a session outside of any request
+type PushTransport interface {
+	StreamingTransport
+
+	// Sessions returns the IDs of the active sessions
+	Sessions() []string
+
+	// Push sends msg to the client of a session
+	Push(sessionID string, msg *types.MCPMessage) error
 }
@@ -1,6 +1,7 @@
 package transport
 
 import (
+	"context"
 	"testing"
 
 	"github.com/labstack/echo/v4"
@@ -9,15 +10,19 @@ import (
 
 // MockTransport implements the Transport interface for testing
 type MockTransport struct {
-	handlers    map[string]MessageHandler
-	mountPath   string
-	toolsNotify bool
+	handlers        map[string]MessageHandler
+	contextHandlers map[string]ContextMessageHandler
+	mountPath       string
+	sessionClosed   []func(sessionID string)
+	toolsNotify     bool
+	closed          bool
 }
 
 func NewMockTransport(path string) *MockTransport {
 	return &MockTransport{
-		handlers:  make(map[string]MessageHandler),
-		mountPath: path,
+		handlers:        make(map[string]MessageHandler),
+		contextHandlers: make(map[string]ContextMessageHandler),
+		mountPath:       path,
 	}
 }
 
@@ -25,6 +30,14 @@ func (m *MockTransport) RegisterHandler(method string, handler MessageHandler) {
 	m.handlers[method] = handler
 }
 
+func (m *MockTransport) RegisterContextHandler(method string, handler ContextMessageHandler) {
+	m.contextHandlers[method] = handler
+}
+
+func (m *MockTransport) OnSessionClosed(callback func(sessionID string)) {
+	m.sessionClosed = append(m.sessionClosed, callback)
+}
+
 func (m *MockTransport) HandleConnection(c echo.Context) error {
 	// Mock implementation
 	return nil
@@ -43,11 +56,20 @@ func (m *MockTransport) MountPath() string {
 	return m.mountPath
 }
 
+func (m *MockTransport) Close(ctx context.Context) error {
+	m.closed = true
+	return nil
+}
+
 // Helper method for testing
 func (m *MockTransport) GetHandler(method string) MessageHandler {
 	return m.handlers[method]
 }
 
+func (m *MockTransport) GetContextHandler(method string) ContextMessageHandler {
+	return m.contextHandlers[method]
+}
+
 func (m *MockTransport) GetToolsNotified() bool {
 	return m.toolsNotify
 }
@@ -129,6 +151,18 @@ func TestTransportInterface(t *testing.T) {
 		assert.Equal(t, "test", result)
 	})
 
+	t.Run("Should register context handlers", func(t *testing.T) {
+		transport := NewMockTransport("/test")
+
+		transport.RegisterContextHandler("test/method", func(ctx context.Context, params any) (any, error) {
+			return SessionIDFromContext(ctx), nil
+		})
+
+		result, err := transport.GetContextHandler("test/method")(WithSessionID(context.Background(), "session-1"), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "session-1", result)
+	})
+
 	t.Run("Should track mount path", func(t *testing.T) {
 		transport := NewMockTransport("/custom/path")
 
@@ -0,0 +1,128 @@
+package types
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// SchemaDiffKind classifies a difference between two schemas
+type SchemaDiffKind string
+
+// Kinds of differences reported by DiffSchemas
+const (
+	SchemaDiffAdded             SchemaDiffKind = "added"
+	SchemaDiffRemoved           SchemaDiffKind = "removed"
+	SchemaDiffTypeChanged       SchemaDiffKind = "type changed"
+	SchemaDiffConstraintChanged SchemaDiffKind = "constraint changed"
+)
+
+// SchemaDiff describes one difference between two schemas. Path is the dotted path of the
+// property (e.g. "body.name", with "[]" for array items), empty for the root schema. Keyword
+// names the changed constraint of a constraint change. Old and New hold the values before and
+// after the change (the property schemas for added and removed properties).
+type SchemaDiff struct {
+	Old     any
+	New     any
+	Kind    SchemaDiffKind
+	Path    string
+	Keyword string
+}
+
+// String renders the difference for log messages, e.g. `body.name: constraint changed (maxLength 50 -> 100)`
+func (d SchemaDiff) String() string {
+	path := d.Path
+	if path == "" {
+		path = "(root)"
+	}
+
+	switch d.Kind {
+	case SchemaDiffAdded, SchemaDiffRemoved:
+		return fmt.Sprintf("%s: %s", path, d.Kind)
+	case SchemaDiffConstraintChanged:
+		return fmt.Sprintf("%s: %s (%s %v -> %v)", path, d.Kind, d.Keyword, d.Old, d.New)
+	default:
+		return fmt.Sprintf("%s: %s (%v -> %v)", path, d.Kind, d.Old, d.New)
+	}
+}
+
+// schemaConstraintKeywords are the keywords compared by DiffSchemas besides type, properties and items
+var schemaConstraintKeywords = []string{
+	"required", "enum", "const", "format", "pattern", "default", "nullable", "additionalProperties",
+	"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf",
+	"minLength", "maxLength", "minItems", "maxItems", "uniqueItems",
+}
+
+// DiffSchemas compares two JSON Schema objects and returns the properties added to or removed
+// from b, and the type and constraint changes of properties present in both, recursing into
+// nested objects and array items. Annotations such as description, title and examples are
+// ignored, and required lists are compared regardless of order. The result is sorted by path.
+func DiffSchemas(a, b map[string]any) []SchemaDiff {
+	var diffs []SchemaDiff
+	diffSchema(a, b, "", &diffs)
+
+	slices.SortStableFunc(diffs, func(x, y SchemaDiff) int {
+		return cmp.Or(strings.Compare(x.Path, y.Path), strings.Compare(x.Keyword, y.Keyword))
+	})
+	return diffs
+}
+
+// diffSchema appends the differences between the schemas of the property at path to diffs
+func diffSchema(a, b map[string]any, path string, diffs *[]SchemaDiff) {
+	if !reflect.DeepEqual(a["type"], b["type"]) {
+		*diffs = append(*diffs, SchemaDiff{Kind: SchemaDiffTypeChanged, Path: path, Old: a["type"], New: b["type"]})
+	}
+
+	for _, keyword := range schemaConstraintKeywords {
+		oldValue, newValue := a[keyword], b[keyword]
+		if keyword == "required" {
+			oldValue, newValue = sortedNames(oldValue), sortedNames(newValue)
+		}
+		if !reflect.DeepEqual(oldValue, newValue) {
+			*diffs = append(*diffs, SchemaDiff{Kind: SchemaDiffConstraintChanged, Path: path, Keyword: keyword, Old: a[keyword], New: b[keyword]})
+		}
+	}
+
+	oldProperties, _ := a["properties"].(map[string]any)
+	newProperties, _ := b["properties"].(map[string]any)
+	for _, name := range slices.Sorted(maps.Keys(oldProperties)) {
+		if _, exists := newProperties[name]; !exists {
+			*diffs = append(*diffs, SchemaDiff{Kind: SchemaDiffRemoved, Path: joinSchemaPath(path, name), Old: oldProperties[name]})
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(newProperties)) {
+		oldProperty, exists := oldProperties[name]
+		if !exists {
+			*diffs = append(*diffs, SchemaDiff{Kind: SchemaDiffAdded, Path: joinSchemaPath(path, name), New: newProperties[name]})
+			continue
+		}
+
+		oldSchema, _ := oldProperty.(map[string]any)
+		newSchema, _ := newProperties[name].(map[string]any)
+		diffSchema(oldSchema, newSchema, joinSchemaPath(path, name), diffs)
+	}
+
+	oldItems, oldHasItems := a["items"].(map[string]any)
+	newItems, newHasItems := b["items"].(map[string]any)
+	if oldHasItems || newHasItems {
+		diffSchema(oldItems, newItems, path+"[]", diffs)
+	}
+}
+
+// joinSchemaPath appends a property name to a dotted schema path
+func joinSchemaPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// sortedNames returns a required list as sorted strings, or nil when it is empty
+func sortedNames(list any) []string {
+	names := unionRequired(list)
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,75 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	t.Run("Should report added, removed and changed properties", func(t *testing.T) {
+		before := map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"id":   map[string]any{"type": "string"},
+				"page": map[string]any{"type": "integer"},
+				"body": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"name": map[string]any{"type": "string", "maxLength": 50},
+					},
+				},
+			},
+			"required": []string{"id"},
+		}
+		after := map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"id":    map[string]any{"type": "integer", "description": "The user ID"},
+				"limit": map[string]any{"type": "integer"},
+				"body": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"name": map[string]any{"type": "string", "maxLength": 100},
+					},
+				},
+			},
+			"required": []any{"id", "limit"},
+		}
+
+		diffs := DiffSchemas(before, after)
+
+		assert.Equal(t, []SchemaDiff{
+			{Kind: SchemaDiffConstraintChanged, Path: "", Keyword: "required", Old: []string{"id"}, New: []any{"id", "limit"}},
+			{Kind: SchemaDiffConstraintChanged, Path: "body.name", Keyword: "maxLength", Old: 50, New: 100},
+			{Kind: SchemaDiffTypeChanged, Path: "id", Old: "string", New: "integer"},
+			{Kind: SchemaDiffAdded, Path: "limit", New: map[string]any{"type": "integer"}},
+			{Kind: SchemaDiffRemoved, Path: "page", Old: map[string]any{"type": "integer"}},
+		}, diffs)
+		assert.Equal(t, "body.name: constraint changed (maxLength 50 -> 100)", diffs[1].String())
+		assert.Equal(t, "limit: added", diffs[3].String())
+	})
+
+	t.Run("Should ignore annotations and required order", func(t *testing.T) {
+		before := map[string]any{
+			"properties": map[string]any{"tags": map[string]any{"type": "array", "items": map[string]any{"type": "string"}}},
+			"required":   []string{"a", "b"},
+		}
+		after := map[string]any{
+			"description": "Search users",
+			"properties":  map[string]any{"tags": map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "examples": []any{"x"}}},
+			"required":    []string{"b", "a"},
+		}
+
+		assert.Empty(t, DiffSchemas(before, after))
+	})
+
+	t.Run("Should compare array items", func(t *testing.T) {
+		before := map[string]any{"properties": map[string]any{"ids": map[string]any{"type": "array", "items": map[string]any{"type": "string"}}}}
+		after := map[string]any{"properties": map[string]any{"ids": map[string]any{"type": "array", "items": map[string]any{"type": "integer"}}}}
+
+		assert.Equal(t, []SchemaDiff{
+			{Kind: SchemaDiffTypeChanged, Path: "ids[]", Old: "string", New: "integer"},
+		}, DiffSchemas(before, after))
+	})
+}
@@ -0,0 +1,68 @@
+package types
+
+import (
+	"maps"
+	"slices"
+)
+
+// bodyProperty is the property wrapping the request body in generated input schemas
+const bodyProperty = "body"
+
+// FlattenSchema hoists the properties of the nested body object to the top level of an
+// input schema and removes the body wrapper, returning the result without modifying schema.
+// Top-level properties (path, query and header parameters) win over body properties with
+// the same name, and required body properties stay required when the body is. Schemas without a body
+// object, or whose body declares no properties, are returned unchanged.
+//
+// Example:
+//
+//	{"properties": {"id": {...}, "body": {"properties": {"name": {...}}, "required": ["name"]}}, "required": ["id", "body"]}
+//
+// becomes
+//
+//	{"properties": {"id": {...}, "name": {...}}, "required": ["id", "name"]}
+func FlattenSchema(schema map[string]any) map[string]any {
+	properties, _ := schema["properties"].(map[string]any)
+	body, _ := properties[bodyProperty].(map[string]any)
+	bodyProperties, _ := body["properties"].(map[string]any)
+	if len(bodyProperties) == 0 {
+		return schema
+	}
+
+	flattened := maps.Clone(schema)
+
+	hoisted := maps.Clone(properties)
+	delete(hoisted, bodyProperty)
+	for name, property := range bodyProperties {
+		if _, exists := hoisted[name]; !exists {
+			hoisted[name] = property
+		}
+	}
+	flattened["properties"] = hoisted
+
+	// Body fields take the place of the body wrapper in the property order
+	if order := PropertyOrder(schema); len(order) > 0 {
+		bodyAt := slices.Index(order, bodyProperty)
+		if bodyAt < 0 {
+			bodyAt = len(order)
+		}
+		bodyOrder := slices.DeleteFunc(PropertyOrder(body), func(name string) bool { return slices.Contains(order, name) })
+		flattened[PropertyOrderKey] = slices.DeleteFunc(slices.Insert(slices.Clone(order), bodyAt, bodyOrder...), func(name string) bool {
+			return name == bodyProperty
+		})
+	}
+
+	// Body properties are only required when the body itself is
+	required := unionRequired(schema["required"])
+	if slices.Contains(required, bodyProperty) {
+		required = unionRequired(required, body["required"])
+	}
+	required = slices.DeleteFunc(required, func(name string) bool { return name == bodyProperty })
+	if len(required) > 0 {
+		flattened["required"] = required
+	} else {
+		delete(flattened, "required")
+	}
+
+	return flattened
+}
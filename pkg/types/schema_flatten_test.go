@@ -0,0 +1,80 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlattenSchema(t *testing.T) {
+	t.Run("Should hoist body properties next to path and query parameters", func(t *testing.T) {
+		schema := map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"id": map[string]any{"type": "string"},
+				"body": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"name": map[string]any{"type": "string"},
+						"age":  map[string]any{"type": "integer"},
+					},
+					"required": []any{"name"},
+				},
+			},
+			"required": []string{"id", "body"},
+		}
+
+		flattened := FlattenSchema(schema)
+
+		assert.Equal(t, "object", flattened["type"])
+		assert.Equal(t, map[string]any{
+			"id":   map[string]any{"type": "string"},
+			"name": map[string]any{"type": "string"},
+			"age":  map[string]any{"type": "integer"},
+		}, flattened["properties"])
+		assert.Equal(t, []string{"id", "name"}, flattened["required"])
+		assert.Contains(t, schema["properties"], "body", "input schema must not be modified")
+	})
+
+	t.Run("Should keep top-level properties on name conflicts", func(t *testing.T) {
+		schema := map[string]any{
+			"properties": map[string]any{
+				"id": map[string]any{"type": "string", "description": "Path parameter: id"},
+				"body": map[string]any{
+					"properties": map[string]any{"id": map[string]any{"type": "integer"}},
+				},
+			},
+		}
+
+		flattened := FlattenSchema(schema)
+
+		assert.Equal(t, map[string]any{
+			"id": map[string]any{"type": "string", "description": "Path parameter: id"},
+		}, flattened["properties"])
+	})
+
+	t.Run("Should not require body properties of an optional body", func(t *testing.T) {
+		schema := map[string]any{
+			"properties": map[string]any{
+				"body": map[string]any{
+					"properties": map[string]any{"name": map[string]any{"type": "string"}},
+					"required":   []string{"name"},
+				},
+			},
+		}
+
+		flattened := FlattenSchema(schema)
+
+		assert.NotContains(t, flattened, "required")
+	})
+
+	t.Run("Should leave schemas without body properties unchanged", func(t *testing.T) {
+		schema := map[string]any{
+			"properties": map[string]any{
+				"body": map[string]any{"type": "object", "description": "Request body"},
+			},
+		}
+
+		assert.Equal(t, schema, FlattenSchema(schema))
+	})
+}
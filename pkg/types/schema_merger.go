@@ -0,0 +1,83 @@
+package types
+
+import "maps"
+
+// MergeSchemas deep-merges two JSON Schema objects and returns the result without
+// modifying either input. Properties from override win, but when a property is an
+// object schema on both sides it is merged recursively so nested structure is kept.
+// Required lists and property orders are unioned, and every other keyword (including
+// type) is taken from override when present.
+func MergeSchemas(base, override map[string]any) map[string]any {
+	merged := maps.Clone(base)
+	if merged == nil {
+		merged = map[string]any{}
+	}
+
+	for key, value := range override {
+		switch key {
+		case "properties":
+			merged[key] = mergeProperties(base[key], value)
+		case "required", PropertyOrderKey:
+			if names := unionRequired(base[key], value); len(names) > 0 {
+				merged[key] = names
+			}
+		default:
+			merged[key] = value
+		}
+	}
+
+	return merged
+}
+
+// mergeProperties merges two properties maps, recursing into properties defined on both sides
+func mergeProperties(base, override any) map[string]any {
+	baseProps, _ := base.(map[string]any)
+	overrideProps, _ := override.(map[string]any)
+
+	merged := maps.Clone(baseProps)
+	if merged == nil {
+		merged = map[string]any{}
+	}
+
+	for name, overrideProp := range overrideProps {
+		baseSchema, baseIsSchema := merged[name].(map[string]any)
+		overrideSchema, overrideIsSchema := overrideProp.(map[string]any)
+		if baseIsSchema && overrideIsSchema {
+			merged[name] = MergeSchemas(baseSchema, overrideSchema)
+			continue
+		}
+		merged[name] = overrideProp
+	}
+
+	return merged
+}
+
+// unionRequired returns the union of two required lists, keeping first-seen order
+func unionRequired(lists ...any) []string {
+	var required []string
+	seen := make(map[string]bool)
+
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			required = append(required, name)
+		}
+	}
+
+	for _, list := range lists {
+		switch names := list.(type) {
+		case []string:
+			for _, name := range names {
+				add(name)
+			}
+		case []any:
+			for _, name := range names {
+				if name, ok := name.(string); ok {
+					add(name)
+				}
+			}
+		}
+	}
+
+	return required
+}
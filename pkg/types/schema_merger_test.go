@@ -0,0 +1,85 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeSchemas(t *testing.T) {
+	t.Run("Should let override properties win and union required", func(t *testing.T) {
+		base := map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"id":   map[string]any{"type": "string", "description": "Path parameter: id"},
+				"page": map[string]any{"type": "integer"},
+			},
+			"required": []string{"id"},
+		}
+		override := map[string]any{
+			"properties": map[string]any{
+				"id":   map[string]any{"type": "string", "format": "uuid"},
+				"name": map[string]any{"type": "string"},
+			},
+			"required": []any{"id", "name"},
+		}
+
+		merged := MergeSchemas(base, override)
+
+		assert.Equal(t, "object", merged["type"])
+		assert.Equal(t, []string{"id", "name"}, merged["required"])
+
+		properties := merged["properties"].(map[string]any)
+		assert.Equal(t, map[string]any{"type": "string", "description": "Path parameter: id", "format": "uuid"}, properties["id"])
+		assert.Contains(t, properties, "page")
+		assert.Contains(t, properties, "name")
+	})
+
+	t.Run("Should merge nested object schemas", func(t *testing.T) {
+		base := map[string]any{
+			"properties": map[string]any{
+				"body": map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"name": map[string]any{"type": "string"}},
+					"required":   []string{"name"},
+				},
+			},
+		}
+		override := map[string]any{
+			"properties": map[string]any{
+				"body": map[string]any{
+					"properties": map[string]any{"email": map[string]any{"type": "string"}},
+					"required":   []string{"email"},
+				},
+			},
+		}
+
+		merged := MergeSchemas(base, override)
+
+		body := merged["properties"].(map[string]any)["body"].(map[string]any)
+		assert.Equal(t, "object", body["type"])
+		assert.Len(t, body["properties"], 2)
+		assert.Equal(t, []string{"name", "email"}, body["required"])
+	})
+
+	t.Run("Should take type from override", func(t *testing.T) {
+		merged := MergeSchemas(map[string]any{"type": "object"}, map[string]any{"type": "array"})
+
+		assert.Equal(t, "array", merged["type"])
+	})
+
+	t.Run("Should not modify the inputs", func(t *testing.T) {
+		base := map[string]any{"properties": map[string]any{"a": map[string]any{"type": "string"}}}
+		override := map[string]any{"properties": map[string]any{"b": map[string]any{"type": "string"}}}
+
+		MergeSchemas(base, override)
+
+		assert.Len(t, base["properties"], 1)
+		assert.Len(t, override["properties"], 1)
+	})
+
+	t.Run("Should handle nil schemas", func(t *testing.T) {
+		assert.Equal(t, map[string]any{}, MergeSchemas(nil, nil))
+		assert.Equal(t, map[string]any{"type": "object"}, MergeSchemas(nil, map[string]any{"type": "object"}))
+	})
+}
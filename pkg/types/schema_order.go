@@ -0,0 +1,47 @@
+package types
+
+import (
+	"maps"
+	"slices"
+)
+
+// PropertyOrderKey is the schema keyword listing property names in declaration order.
+// JSON objects (and map[string]any) do not keep key order, so clients that want to show
+// arguments as declared (path parameters first, then query parameters, then body fields
+// in struct order) read it instead of sorting the properties alphabetically.
+const PropertyOrderKey = "x-order"
+
+// PropertyOrder returns the property order recorded on a schema, accepting both generated
+// ([]string) and JSON-decoded ([]any) lists
+func PropertyOrder(schema map[string]any) []string {
+	return unionRequired(schema[PropertyOrderKey])
+}
+
+// NormalizePropertyOrder returns a copy of schema whose property order lists every property
+// exactly once: recorded names that are still properties keep their position, and properties
+// missing from the order (e.g. added after the schema was generated) follow alphabetically.
+// Schemas without properties are returned unchanged.
+func NormalizePropertyOrder(schema map[string]any) map[string]any {
+	properties, ok := schema["properties"].(map[string]any)
+	if !ok {
+		return schema
+	}
+
+	order := slices.DeleteFunc(PropertyOrder(schema), func(name string) bool {
+		_, exists := properties[name]
+		return !exists
+	})
+	for _, name := range slices.Sorted(maps.Keys(properties)) {
+		if !slices.Contains(order, name) {
+			order = append(order, name)
+		}
+	}
+
+	normalized := maps.Clone(schema)
+	if len(order) > 0 {
+		normalized[PropertyOrderKey] = order
+	} else {
+		delete(normalized, PropertyOrderKey)
+	}
+	return normalized
+}
@@ -0,0 +1,70 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPropertyOrder(t *testing.T) {
+	type Address struct {
+		Street string `json:"street"`
+		City   string `json:"city"`
+	}
+	type UpdateUserRequest struct {
+		Zip     string  `json:"zip"`
+		Name    string  `json:"name"`
+		Address Address `json:"address"`
+		Age     int     `json:"age"`
+	}
+
+	t.Run("Should record struct field order", func(t *testing.T) {
+		schema := GetSchema(UpdateUserRequest{})
+
+		assert.Equal(t, []string{"zip", "name", "address", "age"}, schema[PropertyOrderKey])
+		address := schema["properties"].(map[string]any)["address"].(map[string]any)
+		assert.Equal(t, []string{"street", "city"}, address[PropertyOrderKey])
+	})
+
+	t.Run("Should keep base properties first when merging", func(t *testing.T) {
+		base := map[string]any{
+			"properties":     map[string]any{"id": map[string]any{"type": "string"}},
+			PropertyOrderKey: []string{"id"},
+		}
+
+		merged := MergeSchemas(base, GetSchema(UpdateUserRequest{}))
+
+		assert.Equal(t, []string{"id", "zip", "name", "address", "age"}, merged[PropertyOrderKey])
+	})
+
+	t.Run("Should drop removed properties and append unlisted ones alphabetically", func(t *testing.T) {
+		schema := map[string]any{
+			"properties": map[string]any{
+				"zip":   map[string]any{"type": "string"},
+				"token": map[string]any{"type": "string"},
+				"api":   map[string]any{"type": "string"},
+			},
+			PropertyOrderKey: []any{"zip", "name"},
+		}
+
+		normalized := NormalizePropertyOrder(schema)
+
+		assert.Equal(t, []string{"zip", "api", "token"}, normalized[PropertyOrderKey])
+		assert.Equal(t, []any{"zip", "name"}, schema[PropertyOrderKey])
+	})
+
+	t.Run("Should replace the body wrapper with body fields when flattening", func(t *testing.T) {
+		schema := map[string]any{
+			"properties": map[string]any{
+				"id":   map[string]any{"type": "string"},
+				"body": GetSchema(UpdateUserRequest{}),
+				"page": map[string]any{"type": "integer"},
+			},
+			PropertyOrderKey: []string{"id", "body", "page"},
+		}
+
+		flattened := FlattenSchema(schema)
+
+		assert.Equal(t, []string{"id", "zip", "name", "address", "age", "page"}, flattened[PropertyOrderKey])
+	})
+}
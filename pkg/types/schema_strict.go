@@ -0,0 +1,65 @@
+package types
+
+import (
+	"maps"
+	"slices"
+)
+
+// CloseSchema sets "additionalProperties": false on every object schema declaring
+// properties, recursing into properties and array items, and returns the result without
+// modifying schema. Objects that already set additionalProperties, such as map-typed
+// fields, and objects without properties, such as the generic body, are left open.
+func CloseSchema(schema map[string]any) map[string]any {
+	closed := maps.Clone(schema)
+
+	if properties, ok := closed["properties"].(map[string]any); ok {
+		closedProperties := make(map[string]any, len(properties))
+		for name, property := range properties {
+			if propertySchema, isSchema := property.(map[string]any); isSchema {
+				property = CloseSchema(propertySchema)
+			}
+			closedProperties[name] = property
+		}
+		closed["properties"] = closedProperties
+
+		if _, exists := closed["additionalProperties"]; !exists {
+			closed["additionalProperties"] = false
+		}
+	}
+
+	if items, ok := closed["items"].(map[string]any); ok {
+		closed["items"] = CloseSchema(items)
+	}
+
+	return closed
+}
+
+// UnexpectedArguments returns the arguments, as dotted paths (e.g. "body.nickname"), that
+// are not declared by an object schema closed with "additionalProperties": false. Nested
+// object arguments are checked against their property schemas. The result is sorted.
+func UnexpectedArguments(schema, arguments map[string]any) []string {
+	var unexpected []string
+	collectUnexpectedArguments(schema, arguments, "", &unexpected)
+	slices.Sort(unexpected)
+	return unexpected
+}
+
+// collectUnexpectedArguments appends the undeclared arguments of one object to unexpected
+func collectUnexpectedArguments(schema, arguments map[string]any, prefix string, unexpected *[]string) {
+	properties, _ := schema["properties"].(map[string]any)
+	closed := schema["additionalProperties"] == false
+
+	for name, value := range arguments {
+		propertySchema, declared := properties[name].(map[string]any)
+		if !declared {
+			if closed {
+				*unexpected = append(*unexpected, prefix+name)
+			}
+			continue
+		}
+
+		if nested, isObject := value.(map[string]any); isObject {
+			collectUnexpectedArguments(propertySchema, nested, prefix+name+".", unexpected)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloseSchema(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"id": map[string]any{"type": "string"},
+			"body": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"labels":   map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}},
+					"contacts": map[string]any{"type": "array", "items": map[string]any{"type": "object", "properties": map[string]any{"email": map[string]any{"type": "string"}}}},
+				},
+			},
+			"payload": map[string]any{"type": "object", "description": "Request body"},
+		},
+	}
+
+	t.Run("Should close objects declaring properties", func(t *testing.T) {
+		closed := CloseSchema(schema)
+		properties := closed["properties"].(map[string]any)
+		body := properties["body"].(map[string]any)
+		contacts := body["properties"].(map[string]any)["contacts"].(map[string]any)
+
+		assert.Equal(t, false, closed["additionalProperties"])
+		assert.Equal(t, false, body["additionalProperties"])
+		assert.Equal(t, false, contacts["items"].(map[string]any)["additionalProperties"])
+	})
+
+	t.Run("Should leave maps and free-form objects open", func(t *testing.T) {
+		closed := CloseSchema(schema)
+		properties := closed["properties"].(map[string]any)
+		labels := properties["body"].(map[string]any)["properties"].(map[string]any)["labels"].(map[string]any)
+
+		assert.Equal(t, map[string]any{"type": "string"}, labels["additionalProperties"])
+		assert.NotContains(t, properties["payload"], "additionalProperties")
+	})
+
+	t.Run("Should not modify the input schema", func(t *testing.T) {
+		CloseSchema(schema)
+
+		assert.NotContains(t, schema, "additionalProperties")
+		assert.NotContains(t, schema["properties"].(map[string]any)["body"], "additionalProperties")
+	})
+}
+
+func TestUnexpectedArguments(t *testing.T) {
+	schema := CloseSchema(map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"id": map[string]any{"type": "string"},
+			"body": map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"name": map[string]any{"type": "string"}},
+			},
+			"labels": map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}},
+		},
+	})
+
+	t.Run("Should accept declared arguments", func(t *testing.T) {
+		assert.Empty(t, UnexpectedArguments(schema, map[string]any{
+			"id":     "1",
+			"body":   map[string]any{"name": "Jane"},
+			"labels": map[string]any{"team": "core"},
+		}))
+	})
+
+	t.Run("Should report undeclared arguments at every level", func(t *testing.T) {
+		unexpected := UnexpectedArguments(schema, map[string]any{
+			"id":    "1",
+			"force": true,
+			"body":  map[string]any{"name": "Jane", "nickname": "JJ"},
+		})
+
+		assert.Equal(t, []string{"body.nickname", "force"}, unexpected)
+	})
+
+	t.Run("Should accept anything for open schemas", func(t *testing.T) {
+		assert.Empty(t, UnexpectedArguments(map[string]any{"type": "object"}, map[string]any{"force": true}))
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type MCPMessage struct {
@@ -26,29 +27,120 @@ type MCPError struct {
 	Code    int    `json:"code"`
 }
 
+// JSON-RPC error codes used in MCP responses. ErrCodeNotFound and ErrCodeServerBusy are
+// server errors from the implementation-defined -32000 to -32099 range; ErrCodeServerBusy
+// reports a rejection that clients may retry later.
+const (
+	ErrCodeParseError     = -32700
+	ErrCodeInvalidRequest = -32600
+	ErrCodeMethodNotFound = -32601
+	ErrCodeInvalidParams  = -32602
+	ErrCodeInternalError  = -32603
+	ErrCodeNotFound       = -32002
+	ErrCodeServerBusy     = -32003
+)
+
+// NewMCPError returns an MCPError with one of the ErrCode constants, a message and
+// optional data describing the problem
+func NewMCPError(code int, message string, data any) *MCPError {
+	return &MCPError{
+		Code:    code,
+		Message: message,
+		Data:    data,
+	}
+}
+
+// Error implements the error interface so handlers can return an MCPError
+// with a specific code and data instead of a generic internal error.
+func (e *MCPError) Error() string {
+	return e.Message
+}
+
+// ToolsListRequest holds the params of a tools/list request. Cursor is the opaque
+// nextCursor of the previous page, empty for the first page.
+type ToolsListRequest struct {
+	Cursor string `json:"cursor,omitempty"`
+}
+
 type Tool struct {
-	InputSchema any    `json:"inputSchema"`
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
+	InputSchema    any            `json:"inputSchema"`
+	ResponseSchema any            `json:"responseSchema,omitempty"`
+	Annotations    map[string]any `json:"annotations,omitempty"`
+	Name           string         `json:"name"`
+	Description    string         `json:"description,omitempty"`
+	Deprecated     bool           `json:"deprecated,omitempty"`
 }
 
 type Operation struct {
 	Parameters     map[string]any
+	Defaults       map[string]any
+	StaticQuery    map[string]string
+	StaticHeaders  map[string]string
+	BoundArgs      map[string]any
 	Method         string
 	Path           string
 	Description    string
+	ContentType    string
 	HeaderParams   []string
 	QueryParams    []string
 	FormDataParams []string
+	Timeout        time.Duration
+	// ResponseHeaders overrides Config.IncludeResponseHeaders for the operation when not nil
+	ResponseHeaders []string
+	// RawPathParams are the path parameters whose values keep their slashes unescaped
+	RawPathParams []string
+	// HasBody is set for POST, PUT and PATCH operations, and DELETE operations that send a body
+	HasBody bool
 }
 
 type RegisteredSchemaInfo struct {
-	QuerySchema any
-	BodySchema  any
+	QuerySchema     any
+	BodySchema      any
+	HeaderSchema    any
+	StaticQuery     map[string]string
+	StaticHeaders   map[string]string
+	GenericBody     *bool
+	Description     string
+	Tags            []string
+	ResponseHeaders []string
+	RawPathParams   []string
+}
+
+// SchemaTitler is implemented by request structs that provide the "title" of their schema.
+type SchemaTitler interface {
+	SchemaTitle() string
+}
+
+// SchemaDescriber is implemented by request structs that provide the "description" of their schema.
+type SchemaDescriber interface {
+	SchemaDescription() string
+}
+
+// applySchemaMetadata sets the title and description of a struct schema from the
+// SchemaTitler and SchemaDescriber methods of its type, called on a zero value
+func applySchemaMetadata(schema map[string]any, typ reflect.Type) {
+	value := reflect.New(typ).Interface()
+
+	if titler, ok := value.(SchemaTitler); ok {
+		if title := titler.SchemaTitle(); title != "" {
+			schema["title"] = title
+		}
+	}
+	if describer, ok := value.(SchemaDescriber); ok {
+		if description := describer.SchemaDescription(); description != "" {
+			schema["description"] = description
+		}
+	}
 }
 
-// GetSchema generates a JSON schema from a Go type using reflection and struct tags
+// GetSchema generates a JSON schema from a Go type using reflection and struct tags.
+// Structs implementing SchemaTitler or SchemaDescriber also get a title and description.
 func GetSchema(input any) map[string]any {
+	// Schemas that were already generated are used as-is
+	if schema, ok := input.(map[string]any); ok {
+		return schema
+	}
+
 	if input == nil {
 		return map[string]any{
 			"type":       "object",
@@ -78,7 +170,7 @@ func GetSchema(input any) map[string]any {
 	}
 
 	properties := make(map[string]any)
-	var required []string
+	var required, order []string
 
 	for i := range typ.NumField() {
 		field := typ.Field(i)
@@ -86,31 +178,23 @@ func GetSchema(input any) map[string]any {
 			continue
 		}
 
-		jsonTag := field.Tag.Get("json")
-		if jsonTag == "-" {
+		fieldName := schemaFieldName(field)
+		if fieldName == "" {
 			continue
 		}
 
-		fieldName := field.Name
-		if jsonTag != "" {
-			parts := strings.Split(jsonTag, ",")
-			if parts[0] != "" {
-				fieldName = parts[0]
-			}
-		}
-
 		fieldSchema := reflectType(field.Type)
 
 		if schemaTag := field.Tag.Get("jsonschema"); schemaTag != "" {
 			applySchemaTag(fieldSchema, schemaTag)
 		}
 
-		formTag := field.Tag.Get("form")
-		if strings.Contains(jsonTag, "required") || strings.Contains(formTag, "required") || strings.Contains(field.Tag.Get("jsonschema"), "required") {
+		if isRequiredField(field) {
 			required = append(required, fieldName)
 		}
 
 		properties[fieldName] = fieldSchema
+		order = append(order, fieldName)
 	}
 
 	schema := map[string]any{
@@ -121,10 +205,213 @@ func GetSchema(input any) map[string]any {
 	if len(required) > 0 {
 		schema["required"] = required
 	}
+	if len(order) > 0 {
+		schema[PropertyOrderKey] = order
+	}
+
+	applySchemaMetadata(schema, typ)
+
+	return schema
+}
+
+// SplitSchema generates separate query and body JSON schemas from a single request struct.
+// Fields tagged with form, query, or header go to the query schema, fields tagged with
+// json (and no form or query tag) go to the body schema. Other fields are ignored.
+func SplitSchema(input any) (querySchema, bodySchema map[string]any) {
+	querySchema = map[string]any{"type": "object", "properties": map[string]any{}}
+	bodySchema = map[string]any{"type": "object", "properties": map[string]any{}}
+
+	if input == nil {
+		return querySchema, bodySchema
+	}
+
+	typ := getUnderlyingType(reflect.TypeOf(input))
+	if typ.Kind() != reflect.Struct {
+		return querySchema, bodySchema
+	}
+
+	var queryRequired, bodyRequired, queryOrder, bodyOrder []string
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldSchema := reflectType(field.Type)
+		if schemaTag := field.Tag.Get("jsonschema"); schemaTag != "" {
+			applySchemaTag(fieldSchema, schemaTag)
+		}
+
+		if name := queryFieldName(field); name != "" {
+			querySchema["properties"].(map[string]any)[name] = fieldSchema
+			queryOrder = append(queryOrder, name)
+			if isRequiredField(field) {
+				queryRequired = append(queryRequired, name)
+			}
+			continue
+		}
+
+		jsonTag := field.Tag.Get("json")
+		name := strings.Split(jsonTag, ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		bodySchema["properties"].(map[string]any)[name] = fieldSchema
+		bodyOrder = append(bodyOrder, name)
+		if isRequiredField(field) {
+			bodyRequired = append(bodyRequired, name)
+		}
+	}
+
+	if len(queryRequired) > 0 {
+		querySchema["required"] = queryRequired
+	}
+	if len(bodyRequired) > 0 {
+		bodySchema["required"] = bodyRequired
+	}
+	if len(queryOrder) > 0 {
+		querySchema[PropertyOrderKey] = queryOrder
+	}
+	if len(bodyOrder) > 0 {
+		bodySchema[PropertyOrderKey] = bodyOrder
+	}
+	applySchemaMetadata(bodySchema, typ)
+
+	return querySchema, bodySchema
+}
+
+// GetHeaderSchema generates a JSON schema for the header parameters of a request struct.
+// Properties are named after the header tag of each field; fields without one are ignored.
+func GetHeaderSchema(input any) map[string]any {
+	schema := map[string]any{"type": "object", "properties": map[string]any{}}
+
+	if input == nil {
+		return schema
+	}
+
+	typ := getUnderlyingType(reflect.TypeOf(input))
+	if typ.Kind() != reflect.Struct {
+		return schema
+	}
+
+	var required, order []string
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		name := strings.Split(field.Tag.Get("header"), ",")[0]
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+
+		fieldSchema := reflectType(field.Type)
+		if schemaTag := field.Tag.Get("jsonschema"); schemaTag != "" {
+			applySchemaTag(fieldSchema, schemaTag)
+		}
+
+		schema["properties"].(map[string]any)[name] = fieldSchema
+		order = append(order, name)
+		if isRequiredField(field) {
+			required = append(required, name)
+		}
+	}
+
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	if len(order) > 0 {
+		schema[PropertyOrderKey] = order
+	}
 
 	return schema
 }
 
+// schemaFieldName returns the property name GetSchema gives a struct field: its json tag name,
+// or the field name without one. Fields excluded with json:"-" return an empty string.
+func schemaFieldName(field reflect.StructField) string {
+	jsonTag := field.Tag.Get("json")
+	if jsonTag == "-" {
+		return ""
+	}
+
+	if name := strings.Split(jsonTag, ",")[0]; name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// FormFieldNames returns the GetSchema property names of the exported struct fields tagged
+// with form, in declaration order. Non-struct inputs, including generated schemas, have none.
+func FormFieldNames(input any) []string {
+	if input == nil {
+		return nil
+	}
+
+	typ := getUnderlyingType(reflect.TypeOf(input))
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var names []string
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		formName := strings.Split(field.Tag.Get("form"), ",")[0]
+		if !field.IsExported() || formName == "" || formName == "-" {
+			continue
+		}
+		if name := schemaFieldName(field); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// IsFormStruct reports whether input is a struct whose exported GetSchema fields are all
+// tagged with form, such as a form-encoded request body. Structs without fields are not.
+func IsFormStruct(input any) bool {
+	if input == nil {
+		return false
+	}
+
+	typ := getUnderlyingType(reflect.TypeOf(input))
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+
+	fields := 0
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if !field.IsExported() || schemaFieldName(field) == "" {
+			continue
+		}
+		formName := strings.Split(field.Tag.Get("form"), ",")[0]
+		if formName == "" || formName == "-" {
+			return false
+		}
+		fields++
+	}
+	return fields > 0
+}
+
+// queryFieldName returns the parameter name from the form, query, or header tag of a field
+func queryFieldName(field reflect.StructField) string {
+	for _, key := range []string{"form", "query", "header"} {
+		if name := strings.Split(field.Tag.Get(key), ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+	return ""
+}
+
+// isRequiredField reports whether a struct field is marked as required in its tags
+func isRequiredField(field reflect.StructField) bool {
+	for _, key := range []string{"json", "form", "query", "header", "jsonschema"} {
+		if strings.Contains(field.Tag.Get(key), "required") {
+			return true
+		}
+	}
+	return false
+}
+
 // getUnderlyingType returns the underlying type, following pointers
 func getUnderlyingType(t reflect.Type) reflect.Type {
 	for t.Kind() == reflect.Pointer {
@@ -165,21 +452,90 @@ func reflectType(t reflect.Type) map[string]any {
 	}
 }
 
-// applySchemaTag applies jsonschema tag attributes to field schema
+// applySchemaTag applies jsonschema tag attributes to field schema. Attributes are separated
+// by commas; a comma inside a value is escaped with a backslash (description=Name\, as shown).
+// Enum values are separated by pipes (enum=draft|published). Numeric attributes, defaults,
+// examples and enum values are converted to the JSON type of the field.
 func applySchemaTag(fieldSchema map[string]any, tag string) {
-	parts := strings.SplitSeq(tag, ",")
-	for part := range parts {
-		part = strings.TrimSpace(part)
-		if after, ok := strings.CutPrefix(part, "description="); ok {
-			fieldSchema["description"] = after
-		} else if after0, ok0 := strings.CutPrefix(part, "minimum="); ok0 {
-			if minimum, err := strconv.ParseFloat(after0, 64); err == nil {
-				fieldSchema["minimum"] = minimum
+	for _, part := range splitSchemaTag(tag) {
+		key, value, hasValue := strings.Cut(strings.TrimSpace(part), "=")
+		if !hasValue {
+			continue
+		}
+
+		switch key {
+		case "description", "pattern", "format":
+			fieldSchema[key] = value
+		case "minimum", "maximum":
+			if number, err := strconv.ParseFloat(value, 64); err == nil {
+				fieldSchema[key] = number
+			}
+		case "minLength", "maxLength", "minItems", "maxItems":
+			if length, err := strconv.Atoi(value); err == nil && length >= 0 {
+				fieldSchema[key] = length
+			}
+		case "default":
+			if typed, ok := parseSchemaValue(fieldSchema, value); ok {
+				fieldSchema["default"] = typed
+			}
+		case "example":
+			if typed, ok := parseSchemaValue(fieldSchema, value); ok {
+				examples, _ := fieldSchema["examples"].([]any)
+				fieldSchema["examples"] = append(examples, typed)
+			}
+		case "enum":
+			// Enums of slices constrain their items
+			target := fieldSchema
+			if items, isArray := fieldSchema["items"].(map[string]any); isArray && fieldSchema["type"] == "array" {
+				target = items
+			}
+
+			var enum []any
+			for option := range strings.SplitSeq(value, "|") {
+				if typed, ok := parseSchemaValue(target, option); ok {
+					enum = append(enum, typed)
+				}
 			}
-		} else if after1, ok1 := strings.CutPrefix(part, "maximum="); ok1 {
-			if maximum, err := strconv.ParseFloat(after1, 64); err == nil {
-				fieldSchema["maximum"] = maximum
+			if len(enum) > 0 {
+				target["enum"] = enum
 			}
 		}
 	}
 }
+
+// splitSchemaTag splits a jsonschema tag on commas, keeping commas escaped as "\,"
+func splitSchemaTag(tag string) []string {
+	var parts []string
+	var part strings.Builder
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			part.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			parts = append(parts, part.String())
+			part.Reset()
+		default:
+			part.WriteByte(tag[i])
+		}
+	}
+	return append(parts, part.String())
+}
+
+// parseSchemaValue converts a tag value to the JSON type of the schema, reporting false
+// when the value does not match it
+func parseSchemaValue(schema map[string]any, value string) (any, bool) {
+	switch schema["type"] {
+	case "integer":
+		number, err := strconv.ParseInt(value, 10, 64)
+		return number, err == nil
+	case "number":
+		number, err := strconv.ParseFloat(value, 64)
+		return number, err == nil
+	case "boolean":
+		boolean, err := strconv.ParseBool(value)
+		return boolean, err == nil
+	default:
+		return value, true
+	}
+}
@@ -169,6 +169,47 @@ func TestGetSchema(t *testing.T) {
 	})
 }
 
+func TestSplitSchema(t *testing.T) {
+	type PatchRequest struct {
+		ID      string `json:"-" param:"id"`
+		Notify  bool   `query:"notify"`
+		TraceID string `header:"X-Trace-ID"`
+		Page    int    `form:"page" json:"page"`
+		Name    string `json:"name" jsonschema:"required"`
+		Status  string `json:"status,omitempty"`
+		Other   string
+	}
+
+	t.Run("Should split query and body fields", func(t *testing.T) {
+		querySchema, bodySchema := SplitSchema(&PatchRequest{})
+
+		queryProps := querySchema["properties"].(map[string]any)
+		assert.Len(t, queryProps, 3)
+		assert.Contains(t, queryProps, "notify")
+		assert.Contains(t, queryProps, "X-Trace-ID")
+		assert.Contains(t, queryProps, "page")
+
+		bodyProps := bodySchema["properties"].(map[string]any)
+		assert.Len(t, bodyProps, 2)
+		assert.Contains(t, bodyProps, "name")
+		assert.Contains(t, bodyProps, "status")
+		assert.Equal(t, []string{"name"}, bodySchema["required"])
+	})
+
+	t.Run("Should return empty schemas for non-struct input", func(t *testing.T) {
+		querySchema, bodySchema := SplitSchema("not a struct")
+
+		assert.Empty(t, querySchema["properties"])
+		assert.Empty(t, bodySchema["properties"])
+	})
+
+	t.Run("Should pass generated schemas through GetSchema", func(t *testing.T) {
+		querySchema, _ := SplitSchema(PatchRequest{})
+
+		assert.Equal(t, querySchema, GetSchema(querySchema))
+	})
+}
+
 func TestApplySchemaTag(t *testing.T) {
 	t.Run("Should apply minimum constraint", func(t *testing.T) {
 		schema := map[string]any{"type": "integer"}
@@ -216,6 +257,67 @@ func TestApplySchemaTag(t *testing.T) {
 
 		assert.Len(t, schema, originalLen)
 	})
+
+	t.Run("Should apply each keyword with its JSON type", func(t *testing.T) {
+		testCases := []struct {
+			expected any
+			name     string
+			typ      string
+			tag      string
+			keyword  string
+		}{
+			{name: "minLength", typ: "string", tag: "minLength=3", keyword: "minLength", expected: 3},
+			{name: "maxLength", typ: "string", tag: "maxLength=50", keyword: "maxLength", expected: 50},
+			{name: "pattern", typ: "string", tag: "pattern=^[a-z]+$", keyword: "pattern", expected: "^[a-z]+$"},
+			{name: "format", typ: "string", tag: "format=email", keyword: "format", expected: "email"},
+			{name: "string default", typ: "string", tag: "default=active", keyword: "default", expected: "active"},
+			{name: "integer default", typ: "integer", tag: "default=10", keyword: "default", expected: int64(10)},
+			{name: "boolean default", typ: "boolean", tag: "default=true", keyword: "default", expected: true},
+			{name: "example", typ: "number", tag: "example=1.5", keyword: "examples", expected: []any{1.5}},
+			{name: "string enum", typ: "string", tag: "enum=draft|published", keyword: "enum", expected: []any{"draft", "published"}},
+			{name: "integer enum", typ: "integer", tag: "enum=1|2|3", keyword: "enum", expected: []any{int64(1), int64(2), int64(3)}},
+			{name: "minItems", typ: "array", tag: "minItems=1", keyword: "minItems", expected: 1},
+			{name: "maxItems", typ: "array", tag: "maxItems=5", keyword: "maxItems", expected: 5},
+		}
+
+		for _, tc := range testCases {
+			schema := map[string]any{"type": tc.typ}
+			applySchemaTag(schema, tc.tag)
+
+			assert.Equal(t, tc.expected, schema[tc.keyword], tc.name)
+		}
+	})
+
+	t.Run("Should combine string constraints", func(t *testing.T) {
+		schema := map[string]any{"type": "string"}
+		applySchemaTag(schema, "minLength=3,maxLength=50,pattern=^[a-z]+$")
+
+		assert.Equal(t, map[string]any{"type": "string", "minLength": 3, "maxLength": 50, "pattern": "^[a-z]+$"}, schema)
+	})
+
+	t.Run("Should keep escaped commas in values", func(t *testing.T) {
+		schema := map[string]any{"type": "string"}
+		applySchemaTag(schema, `description=Last name\, first name,pattern=^\w+\, \w+$`)
+
+		assert.Equal(t, "Last name, first name", schema["description"])
+		assert.Equal(t, `^\w+, \w+$`, schema["pattern"])
+	})
+
+	t.Run("Should apply enums of slices to their items", func(t *testing.T) {
+		schema := map[string]any{"type": "array", "items": map[string]any{"type": "string"}}
+		applySchemaTag(schema, "enum=red|green,maxItems=2")
+
+		assert.Equal(t, []any{"red", "green"}, schema["items"].(map[string]any)["enum"])
+		assert.Equal(t, 2, schema["maxItems"])
+		assert.NotContains(t, schema, "enum")
+	})
+
+	t.Run("Should skip values that do not match the field type", func(t *testing.T) {
+		schema := map[string]any{"type": "integer"}
+		applySchemaTag(schema, "default=ten,enum=one|two,minLength=-1")
+
+		assert.Equal(t, map[string]any{"type": "integer"}, schema)
+	})
 }
 
 func TestReflectType(t *testing.T) {
@@ -382,3 +484,131 @@ func TestMCPMessage(t *testing.T) {
 		assert.Equal(t, params, message.Params)
 	})
 }
+
+func TestNewMCPError(t *testing.T) {
+	t.Run("Should build an error with code, message and data", func(t *testing.T) {
+		err := NewMCPError(ErrCodeInvalidParams, "id is required", map[string]any{"field": "id"})
+
+		assert.Equal(t, -32602, err.Code)
+		assert.Equal(t, "id is required", err.Message)
+		assert.Equal(t, map[string]any{"field": "id"}, err.Data)
+	})
+
+	t.Run("Should use the JSON-RPC error code values", func(t *testing.T) {
+		assert.Equal(t, -32700, ErrCodeParseError)
+		assert.Equal(t, -32600, ErrCodeInvalidRequest)
+		assert.Equal(t, -32601, ErrCodeMethodNotFound)
+		assert.Equal(t, -32602, ErrCodeInvalidParams)
+		assert.Equal(t, -32603, ErrCodeInternalError)
+	})
+}
+
+func TestGetHeaderSchema(t *testing.T) {
+	t.Run("Should name properties after header tags", func(t *testing.T) {
+		type Headers struct {
+			TenantID  string `header:"X-Tenant-Id" jsonschema:"required"`
+			RequestID string `header:"X-Request-Id"`
+			Body      string `json:"body"`
+		}
+
+		schema := GetHeaderSchema(Headers{})
+
+		properties := schema["properties"].(map[string]any)
+		assert.Len(t, properties, 2)
+		assert.Contains(t, properties, "X-Tenant-Id")
+		assert.Contains(t, properties, "X-Request-Id")
+		assert.Equal(t, []string{"X-Tenant-Id"}, schema["required"])
+	})
+
+	t.Run("Should return an empty schema for nil input", func(t *testing.T) {
+		schema := GetHeaderSchema(nil)
+
+		assert.Empty(t, schema["properties"])
+	})
+}
+
+// createOrderRequest describes its schema through SchemaTitler and SchemaDescriber
+type createOrderRequest struct {
+	Item     string `json:"item"`
+	Quantity int    `json:"quantity"`
+}
+
+func (createOrderRequest) SchemaTitle() string { return "Create order" }
+
+func (*createOrderRequest) SchemaDescription() string {
+	return "Places an order for a single item"
+}
+
+func TestSchemaMetadata(t *testing.T) {
+	t.Run("Should emit the title and description of a struct", func(t *testing.T) {
+		for _, input := range []any{createOrderRequest{}, &createOrderRequest{}} {
+			schema := GetSchema(input)
+
+			assert.Equal(t, "Create order", schema["title"])
+			assert.Equal(t, "Places an order for a single item", schema["description"])
+			assert.Contains(t, schema["properties"], "item")
+		}
+	})
+
+	t.Run("Should describe nested structs unless the field tag overrides it", func(t *testing.T) {
+		schema := GetSchema(struct {
+			Order   createOrderRequest `json:"order"`
+			Reorder createOrderRequest `json:"reorder" jsonschema:"description=Order to repeat"`
+		}{})
+		properties := schema["properties"].(map[string]any)
+
+		assert.Equal(t, "Places an order for a single item", properties["order"].(map[string]any)["description"])
+		assert.Equal(t, "Order to repeat", properties["reorder"].(map[string]any)["description"])
+		assert.NotContains(t, schema, "title")
+	})
+
+	t.Run("Should describe the body of a split schema", func(t *testing.T) {
+		_, bodySchema := SplitSchema(createOrderRequest{})
+
+		assert.Equal(t, "Create order", bodySchema["title"])
+	})
+}
+
+func TestFormFieldNames(t *testing.T) {
+	type uploadForm struct {
+		Title    string `form:"title" json:"title"`
+		Category string `form:"category"`
+		Internal string `form:"internal" json:"-"`
+		Page     int    `query:"page" json:"page"`
+		Skipped  string `form:"-" json:"skipped"`
+	}
+
+	t.Run("Should name form fields like GetSchema properties", func(t *testing.T) {
+		assert.Equal(t, []string{"title", "Category"}, FormFieldNames(&uploadForm{}))
+	})
+
+	t.Run("Should return nothing for generated schemas", func(t *testing.T) {
+		assert.Empty(t, FormFieldNames(map[string]any{"type": "object"}))
+		assert.Empty(t, FormFieldNames(nil))
+	})
+}
+
+func TestIsFormStruct(t *testing.T) {
+	type loginForm struct {
+		Username string `form:"username" json:"username"`
+		Password string `form:"password" json:"password"`
+		Ignored  string `json:"-"`
+		internal string
+	}
+	type mixedForm struct {
+		Username string `form:"username" json:"username"`
+		Remember bool   `json:"remember"`
+	}
+
+	t.Run("Should accept structs made only of form fields", func(t *testing.T) {
+		assert.True(t, IsFormStruct(loginForm{}))
+		assert.True(t, IsFormStruct(&loginForm{}))
+	})
+
+	t.Run("Should reject structs with fields sent otherwise", func(t *testing.T) {
+		assert.False(t, IsFormStruct(mixedForm{}))
+		assert.False(t, IsFormStruct(struct{}{}))
+		assert.False(t, IsFormStruct(map[string]any{"type": "object"}))
+		assert.False(t, IsFormStruct(nil))
+	})
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RedirectPolicy controls how tool calls sent through a custom HTTP client handle redirects.
+// Calls dispatched in-process never follow redirects.
+type RedirectPolicy string
+
+const (
+	// RedirectFollow follows redirects and lists them with the result (the default)
+	RedirectFollow RedirectPolicy = "follow"
+	// RedirectNone returns 3xx responses as they are
+	RedirectNone RedirectPolicy = "none"
+	// RedirectSameHost only follows redirects to the host of the original request
+	RedirectSameHost RedirectPolicy = "same-host"
+)
+
+// maxRedirects is the number of redirects followed before a call fails, as in net/http
+const maxRedirects = 10
+
+// contextKey is the type of the context keys of this package
+type contextKey int
+
+const (
+	// redirectsKey holds the list recording the redirects followed by a tool call
+	redirectsKey contextKey = iota
+	// exchangeKey holds the ProxyExchange recording the last upstream request of a tool call
+	exchangeKey
+)
+
+// withRedirects returns a context recording the URLs of the redirects followed by requests in redirects
+func withRedirects(ctx context.Context, redirects *[]string) context.Context {
+	return context.WithValue(ctx, redirectsKey, redirects)
+}
+
+// checkRedirect applies Config.RedirectPolicy to the custom HTTP client: redirects that are not
+// followed return the 3xx response, and followed ones are recorded in the request context
+func (e *EchoMCP) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+
+	switch e.config.RedirectPolicy {
+	case RedirectNone:
+		return http.ErrUseLastResponse
+	case RedirectSameHost:
+		if req.URL.Host != via[0].URL.Host {
+			return http.ErrUseLastResponse
+		}
+	}
+
+	if redirects, ok := req.Context().Value(redirectsKey).(*[]string); ok {
+		*redirects = append(*redirects, req.URL.String())
+	}
+	return nil
+}
+
+// isRedirect reports whether resp is a redirect that was not followed
+func isRedirect(resp *http.Response) bool {
+	return resp.StatusCode >= http.StatusMultipleChoices && resp.StatusCode < http.StatusBadRequest &&
+		resp.Header.Get(echo.HeaderLocation) != ""
+}
+
+// redirectResult returns the status and headers of a redirect that was not followed, flagged
+// with "redirected": true, e.g. {"status": 302, "headers": {"Location": "/login"}, "redirected": true}
+func redirectResult(resp *http.Response) map[string]any {
+	result := statusResult(resp)
+	result["redirected"] = true
+	return result
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"fmt"
+	"html"
+	"maps"
+	"mime"
+	"net/http"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/labstack/echo/v4"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/swagger"
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+)
+
+var (
+	// htmlHiddenElements matches elements whose content is never rendered as text
+	htmlHiddenElements = regexp.MustCompile(`(?is)<(script|style|noscript)\b.*?</(script|style|noscript)\s*>|<!--.*?-->`)
+	// htmlBlockTags matches tags that start a new line of text
+	htmlBlockTags = regexp.MustCompile(`(?i)</?(address|article|aside|blockquote|br|dd|div|dl|dt|footer|form|h[1-6]|header|hr|li|main|nav|ol|p|pre|section|table|td|th|title|tr|ul)\b[^>]*>`)
+	// htmlTags matches any remaining opening or closing tag
+	htmlTags = regexp.MustCompile(`(?s)<[^>]*>`)
+	// blankSpace matches runs of spaces and tabs
+	blankSpace = regexp.MustCompile(`[ \t\r\f\v]+`)
+	// blankLines matches runs of empty lines
+	blankLines = regexp.MustCompile(`\n\s*\n+`)
+)
+
+// statusResultHeaders are the response headers reported in the result of responses without a body
+var statusResultHeaders = []string{
+	echo.HeaderAllow, echo.HeaderCacheControl, echo.HeaderContentLength, echo.HeaderContentType,
+	echo.HeaderLastModified, echo.HeaderLocation, "ETag",
+}
+
+// isBodylessResponse reports whether a response carries no body to parse: HEAD responses, and
+// empty 204 No Content or OPTIONS responses
+func isBodylessResponse(method string, status int, body []byte) bool {
+	if method == http.MethodHead {
+		return true
+	}
+	return len(body) == 0 && (status == http.StatusNoContent || method == http.MethodOptions)
+}
+
+// statusResult returns the status code and the selected headers of a response without a body,
+// e.g. {"status": 200, "headers": {"Content-Length": "42"}}
+func statusResult(resp *http.Response) map[string]any {
+	result := map[string]any{"status": resp.StatusCode}
+
+	headers := map[string]any{}
+	for _, key := range statusResultHeaders {
+		if value := resp.Header.Get(key); value != "" {
+			headers[key] = value
+		}
+	}
+	if len(headers) > 0 {
+		result["headers"] = headers
+	}
+	return result
+}
+
+// ResponseWithHeaders is the result of a tool call whose response carried headers selected by
+// Config.IncludeResponseHeaders or SetResponseHeaders, e.g. X-Total-Count or Location, or that
+// followed redirects, listed in order. With Config.DebugProxy, calls answered with a non-2xx
+// status also carry the upstream request.
+type ResponseWithHeaders struct {
+	Body      any               `json:"body"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Request   *ProxyExchange    `json:"request,omitempty"`
+	Redirects []string          `json:"redirects,omitempty"`
+}
+
+// String returns the body followed by the headers, redirects and request for text tool content
+func (r *ResponseWithHeaders) String() string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "%v", r.Body)
+	if len(r.Headers) > 0 {
+		text.WriteString("\n\nHeaders:")
+		for _, key := range slices.Sorted(maps.Keys(r.Headers)) {
+			fmt.Fprintf(&text, "\n%s: %s", key, r.Headers[key])
+		}
+	}
+	if len(r.Redirects) > 0 {
+		text.WriteString("\n\nRedirects:")
+		for _, location := range r.Redirects {
+			fmt.Fprintf(&text, "\n%s", location)
+		}
+	}
+	if r.Request != nil {
+		fmt.Fprintf(&text, "\n\nRequest:\n%s %s", r.Request.Method, r.Request.URL)
+	}
+	return text.String()
+}
+
+// responseHeaderPatterns returns the response header patterns of an operation: its own list
+// when set with SetResponseHeaders, Config.IncludeResponseHeaders otherwise
+func (e *EchoMCP) responseHeaderPatterns(operation *types.Operation) []string {
+	if operation.ResponseHeaders != nil {
+		return operation.ResponseHeaders
+	}
+	return e.config.IncludeResponseHeaders
+}
+
+// selectResponseHeaders returns the response headers whose name matches one of the patterns
+// (path.Match globs, compared case-insensitively). Location is included on 201 and 3xx
+// responses whenever there are patterns. It returns nil without patterns or matches.
+func selectResponseHeaders(patterns []string, resp *http.Response) map[string]string {
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	headers := map[string]string{}
+	for key := range resp.Header {
+		if slices.ContainsFunc(patterns, func(pattern string) bool { return matchHeaderName(pattern, key) }) {
+			headers[key] = resp.Header.Get(key)
+		}
+	}
+
+	redirected := resp.StatusCode >= http.StatusMultipleChoices && resp.StatusCode < http.StatusBadRequest
+	if location := resp.Header.Get(echo.HeaderLocation); location != "" && (resp.StatusCode == http.StatusCreated || redirected) {
+		headers[echo.HeaderLocation] = location
+	}
+
+	if len(headers) == 0 {
+		return nil
+	}
+	return headers
+}
+
+// matchHeaderName reports whether a header name matches a glob pattern, ignoring case
+func matchHeaderName(pattern, name string) bool {
+	matched, err := path.Match(strings.ToLower(pattern), strings.ToLower(name))
+	return err == nil && matched
+}
+
+// parseResponseBody converts an upstream response body into a tool result according to its
+// Content-Type: XML becomes a map, JSON is parsed (falling back to the raw text when it is
+// malformed), HTML is reduced to its text with Config.StripHTML, and any other declared type
+// is passed through verbatim. Bodies without a Content-Type are parsed as JSON when possible.
+func (e *EchoMCP) parseResponseBody(contentType string, body []byte) any {
+	switch {
+	case contentType == "":
+		return parseJSONOrText(body)
+	case e.config.StripHTML && isHTMLContentType(contentType):
+		return stripHTML(string(body))
+	case swagger.IsXMLContentType(contentType):
+		if decoded, err := decodeXML(body); err == nil {
+			return decoded
+		}
+		return string(body)
+	case swagger.IsJSONContentType(contentType):
+		return parseJSONOrText(body)
+	default:
+		return string(body)
+	}
+}
+
+// parseJSONOrText parses body as JSON, returning it as a string when it is not valid JSON
+func parseJSONOrText(body []byte) any {
+	var result any
+	if err := sonic.Unmarshal(body, &result); err != nil {
+		return string(body)
+	}
+	return result
+}
+
+// isHTMLContentType reports whether contentType is text/html or application/xhtml+xml
+func isHTMLContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "text/html" || mediaType == "application/xhtml+xml"
+}
+
+// stripHTML returns the visible text of an HTML document: scripts, styles, comments and tags
+// are removed, entities are decoded and whitespace is collapsed
+func stripHTML(document string) string {
+	text := htmlHiddenElements.ReplaceAllString(document, "")
+	text = htmlBlockTags.ReplaceAllString(text, "\n")
+	text = htmlTags.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	text = blankSpace.ReplaceAllString(text, " ")
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	text = blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n")
+
+	return strings.TrimSpace(text)
+}
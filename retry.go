@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// RetryConfig configures retries of transient upstream failures during tool calls.
+//
+// MaxAttempts is the total number of attempts including the first one; values
+// below 2 disable retries. Backoff is the base delay, doubled after every attempt
+// and randomized with jitter (100ms when zero). RetryOn lists the status codes that
+// are retried (502, 503 and 504 when empty). Methods lists the HTTP methods that are
+// retried (GET, HEAD, PUT and DELETE when empty); add POST to opt in for it.
+type RetryConfig struct {
+	RetryOn     []int
+	Methods     []string
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+var (
+	defaultRetryOn      = []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+	defaultRetryMethods = []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete}
+)
+
+const defaultRetryBackoff = 100 * time.Millisecond
+
+// maxAttempts returns how many attempts are allowed for the given method
+func (r *RetryConfig) maxAttempts(method string) int {
+	if r.MaxAttempts < 2 {
+		return 1
+	}
+
+	methods := r.Methods
+	if len(methods) == 0 {
+		methods = defaultRetryMethods
+	}
+	if !slices.ContainsFunc(methods, func(m string) bool { return strings.EqualFold(m, method) }) {
+		return 1
+	}
+
+	return r.MaxAttempts
+}
+
+// shouldRetry reports whether an attempt outcome is a transient failure: a retryable status,
+// or a transport error such as a reset connection or a truncated response. Canceled requests
+// and requests that could not be built are never retried.
+func (r *RetryConfig) shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return isTransientError(err)
+	}
+
+	retryOn := r.RetryOn
+	if len(retryOn) == 0 {
+		retryOn = defaultRetryOn
+	}
+	return slices.Contains(retryOn, resp.StatusCode)
+}
+
+// isTransientError reports whether err is a network failure worth retrying
+func isTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// permanentError marks an attempt failure that must not be retried, such as a request that
+// could not be built
+type permanentError struct {
+	err error
+}
+
+func (p *permanentError) Error() string { return p.err.Error() }
+
+func (p *permanentError) Unwrap() error { return p.err }
+
+// delay returns the jittered exponential backoff before the given retry (1-based)
+func (r *RetryConfig) delay(retry int) time.Duration {
+	base := r.Backoff
+	if base <= 0 {
+		base = defaultRetryBackoff
+	}
+
+	backoff := base << (retry - 1)
+	half := backoff / 2
+	return half + time.Duration(rand.Int64N(int64(half)+1))
+}
+
+// executeWithRetry runs attempt until it succeeds, the retry budget is exhausted,
+// or waiting for the next attempt would exceed the context deadline. Once retries stop,
+// the last response is returned unchanged together with the number of attempts made, so
+// the caller can report both (see retriesExhausted). Errors wrapped in a permanentError
+// are returned as they are, without retrying.
+func (e *EchoMCP) executeWithRetry(ctx context.Context, method string, attempt func() (*http.Response, error)) (*http.Response, int, error) {
+	retry := &e.config.Retry
+	maxAttempts := retry.maxAttempts(method)
+
+	var resp *http.Response
+	var err error
+	attempts := 0
+
+	for {
+		attempts++
+		resp, err = attempt()
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return nil, attempts, permanent.err
+		}
+		if attempts >= maxAttempts || !retry.shouldRetry(resp, err) {
+			break
+		}
+
+		delay := retry.delay(attempts)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			break
+		}
+
+		// Release the connection of a response that is about to be discarded
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, attempts, fmt.Errorf("request canceled after %d attempts: %w", attempts, ctx.Err())
+		case <-timer.C:
+		}
+	}
+
+	if err != nil && maxAttempts > 1 && isTransientError(err) {
+		return nil, attempts, fmt.Errorf("request failed after %d attempts: %w", attempts, err)
+	}
+	return resp, attempts, err
+}
+
+// retriesExhausted reports whether retries of method stopped on a response whose status is
+// still retryable, which is reported as an error rather than a result
+func (r *RetryConfig) retriesExhausted(method string, resp *http.Response) bool {
+	return r.maxAttempts(method) > 1 && r.shouldRetry(resp, nil)
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+)
+
+// RegisterQuery registers T as the query parameter schema for a specific route,
+// keeping any body schema already registered for it.
+//
+// Example:
+//
+//	server.RegisterQuery[UserQuery](mcp, "GET", "/users")
+func RegisterQuery[T any](mcp *EchoMCP, method, path string) {
+	var query T
+	mcp.updateSchema(method, path, func(info *types.RegisteredSchemaInfo) {
+		info.QuerySchema = query
+	})
+}
+
+// RegisterBody registers T as the request body schema for a specific route,
+// keeping any query schema already registered for it.
+//
+// Example:
+//
+//	server.RegisterBody[CreateUserRequest](mcp, "POST", "/users")
+func RegisterBody[T any](mcp *EchoMCP, method, path string) {
+	var body T
+	mcp.updateSchema(method, path, func(info *types.RegisteredSchemaInfo) {
+		info.BodySchema = body
+	})
+}
+
+// RegisterSchemaForRoute registers query parameters and request body for the route returned
+// by Echo when it was registered, so the method and path cannot drift from the actual route.
+// It returns an error if the route is not registered on the Echo instance.
+//
+// Example:
+//
+//	route := e.POST("/users", createUser)
+//	if err := mcp.RegisterSchemaForRoute(route, nil, CreateUserRequest{}); err != nil {
+//		log.Fatal(err)
+//	}
+func (e *EchoMCP) RegisterSchemaForRoute(route *echo.Route, querySchema, bodySchema any) error {
+	if route == nil {
+		return errors.New("route is nil")
+	}
+
+	for _, registered := range e.echo.Routes() {
+		if registered.Method == route.Method && registered.Path == route.Path {
+			e.RegisterSchema(route.Method, route.Path, querySchema, bodySchema)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("route %s %s is not registered", route.Method, route.Path)
+}
+
+// RegisterStaticParams sets constant query parameters and headers sent with every call to a
+// specific route. They are hidden from the tool input schema and override any argument of the
+// same name, so the model can neither see nor change them.
+//
+// Example:
+//
+//	mcp.RegisterStaticParams("GET", "/reports",
+//		map[string]string{"api-version": "2024-01-01"},
+//		map[string]string{"X-Source": "mcp"},
+//	)
+func (e *EchoMCP) RegisterStaticParams(method, path string, query, headers map[string]string) {
+	e.updateSchema(method, path, func(info *types.RegisteredSchemaInfo) {
+		info.StaticQuery = maps.Clone(query)
+		info.StaticHeaders = maps.Clone(headers)
+	})
+}
+
+// SetGenericBody overrides Config.NoGenericBody for a specific route: when enabled is false,
+// a POST, PUT or PATCH route without a registered or swagger body schema gets no generic
+// "body" argument, so models do not invent payloads for bodyless endpoints.
+//
+// Example:
+//
+//	mcp.SetGenericBody("POST", "/jobs/:id/retry", false)
+func (e *EchoMCP) SetGenericBody(method, path string, enabled bool) {
+	e.updateSchema(method, path, func(info *types.RegisteredSchemaInfo) {
+		info.GenericBody = &enabled
+	})
+}
+
+// SetResponseHeaders overrides Config.IncludeResponseHeaders for a specific route: headers of
+// its responses matching one of the patterns are returned with the tool result. Call it without
+// patterns to return no headers for the route.
+//
+// Example:
+//
+//	mcp.SetResponseHeaders("GET", "/users", "X-Total-Count", "Link")
+func (e *EchoMCP) SetResponseHeaders(method, path string, patterns ...string) {
+	e.updateSchema(method, path, func(info *types.RegisteredSchemaInfo) {
+		info.ResponseHeaders = append([]string{}, patterns...)
+	})
+}
+
+// RegisterRawPathParam keeps the slashes of a path parameter unescaped, for routes that
+// intentionally take slash-containing values (e.g. a file path in /files/:path). Other
+// characters are still escaped. The x-mcp-raw-path swagger extension does the same.
+//
+// Example:
+//
+//	mcp.RegisterRawPathParam("GET", "/repos/:repo", "repo")
+func (e *EchoMCP) RegisterRawPathParam(method, path, name string) {
+	e.updateSchema(method, path, func(info *types.RegisteredSchemaInfo) {
+		if !slices.Contains(info.RawPathParams, name) {
+			info.RawPathParams = append(slices.Clone(info.RawPathParams), name)
+		}
+	})
+}
+
+// updateSchema applies update to the schema registered for a route, creating it if needed
+func (e *EchoMCP) updateSchema(method, path string, update func(info *types.RegisteredSchemaInfo)) {
+	e.schemasMu.Lock()
+	defer e.schemasMu.Unlock()
+
+	key := fmt.Sprintf("%s %s", method, path)
+	info := e.registeredSchemas[key]
+	update(&info)
+	e.registeredSchemas[key] = info
+}
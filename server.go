@@ -29,19 +29,26 @@ package server
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"maps"
+	"mime/multipart"
+	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"slices"
 	"strings"
 	"sync"
+	"text/template"
+	"time"
+	"unicode"
 
 	"github.com/bytedance/sonic"
 	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
 
 	"github.com/BrunoKrugel/echo-mcp/pkg/convert"
 	"github.com/BrunoKrugel/echo-mcp/pkg/swagger"
@@ -49,41 +56,139 @@ import (
 	"github.com/BrunoKrugel/echo-mcp/pkg/types"
 )
 
+// protocolVersion is the MCP protocol version implemented by the server
+const protocolVersion = "2024-11-05"
+
+// defaultExcludedHTTPMethods are the HTTP methods never exposed as tools unless
+// Config.ExcludeHTTPMethods is set
+var defaultExcludedHTTPMethods = []string{http.MethodOptions, http.MethodHead, http.MethodTrace}
+
 // EchoMCP represents an MCP server that exposes Echo routes as MCP tools.
 // It handles the conversion of HTTP endpoints to MCP tool definitions and
 // manages the execution of tool calls by forwarding them to the original Echo handlers.
 type EchoMCP struct {
 	transport         transport.Transport
+	configErr         error
+	swaggerErr        error
 	swaggerSpec       *swagger.SwaggerSpec
 	echo              *echo.Echo
 	operations        map[string]types.Operation
 	config            *Config
 	registeredSchemas map[string]types.RegisteredSchemaInfo
-	executeToolFunc   func(operationID string, parameters map[string]any) (any, error)
+	executeToolFunc   func(ctx context.Context, operationID string, parameters map[string]any) (any, error)
+	stopRouteWatch    context.CancelFunc
+	cookieJars        map[string]http.CookieJar
+	logLevels         map[string]string
+	completions       map[string]CompletionProvider
+	sessionSlots      map[string]chan struct{}
+	toolCallSlots     chan struct{}
+	operationOwners   map[string]*echoInstance
+	customTools       map[string]customTool
+	toolTimeouts      map[string]time.Duration
+	descTemplate      *template.Template
 	name              string
 	description       string
 	baseURL           string
 	version           string
 	tools             []types.Tool
+	instances         []*echoInstance
+	customToolOrder   []string
 	includeEndpoints  []string
 	excludeEndpoints  []string
+	warnings          []string
+	aliases           []toolAlias
+	hiddenOriginals   []string
+	cache             responseCache
+	idempotency       idempotencyStore
+	client            *http.Client
 	schemasMu         sync.RWMutex
+	toolsMu           sync.RWMutex
+	endpointsMu       sync.RWMutex
+	instancesMu       sync.RWMutex
+	customToolsMu     sync.RWMutex
+	timeoutsMu        sync.RWMutex
+	aliasesMu         sync.RWMutex
+	cookieJarsMu      sync.Mutex
+	clientMu          sync.RWMutex
+	logLevelsMu       sync.Mutex
+	completionsMu     sync.RWMutex
+	slotsMu           sync.Mutex
+	warningsMu        sync.Mutex
+	lifecycleMu       sync.Mutex
+	refreshMu         sync.Mutex
+	calls             sync.WaitGroup
+	shuttingDown      bool
+	unmounted         bool
 }
 
 // Config holds configuration options for the EchoMCP server.
 type Config struct {
-	Name                       string
-	Version                    string
-	Description                string
-	BaseURL                    string
-	OpenAPISchema              string
-	IncludeOperations          []string
-	ExcludeOperations          []string
-	IncludeTags                []string
-	ExcludeTags                []string
-	EnableSwaggerSchemas       bool
-	DescribeAllResponses       bool
-	DescribeFullResponseSchema bool
+	Name                             string
+	Version                          string
+	Description                      string
+	BaseURL                          string
+	BasePath                         string
+	OpenAPISchema                    string
+	SwaggerSpecURL                   string
+	DescriptionTemplate              string
+	HealthCheckPath                  string
+	ToolPrefix                       string
+	ToolNamePrefix                   string
+	ToolNameSuffix                   string
+	RequestHeaders                   map[string]string
+	CORSOrigins                      []string
+	IncludeOperations                []string
+	ExcludeOperations                []string
+	IncludeTags                      []string
+	ExcludeTags                      []string
+	IncludeEndpoints                 []string
+	ExcludeEndpoints                 []string
+	ExcludeHTTPMethods               []string
+	IncludeResponseHeaders           []string
+	SkipHeadRoutes                   *bool
+	Retry                            RetryConfig
+	OperationIDTransform             func(id string) string
+	RequestTransform                 func(req *http.Request) error
+	HTTPClient                       *http.Client
+	RedirectPolicy                   RedirectPolicy
+	DescriptionFormatter             func(route *echo.Route, op swagger.SwaggerOperation) string
+	EnableSwaggerSchemas             bool
+	SessionTTL                       time.Duration
+	CacheTTL                         time.Duration
+	IdempotencyTTL                   time.Duration
+	RouteRefreshInterval             time.Duration
+	ProgressInterval                 time.Duration
+	ToolCallQueueTimeout             time.Duration
+	MaxResponseBodyBytes             int
+	CacheMaxEntries                  int
+	IdempotencyMaxEntries            int
+	MaxToolNameLength                int
+	MaxConcurrentToolCalls           int
+	ToolsPageSize                    int
+	MaxConcurrentToolCallsPerSession int
+	DescribeAllResponses             bool
+	DescribeFullResponseSchema       bool
+	ForwardCookies                   bool
+	ForwardIdempotencyKey            bool
+	EnableCookieJar                  bool
+	EnableToolsDebugEndpoint         bool
+	SkipCatchAllRoutes               bool
+	RequireSecurityParameters        bool
+	SkipDeprecated                   bool
+	ExcludeDeprecated                bool
+	PreferSwaggerOperationID         bool
+	StrictSwagger                    bool
+	DryRun                           bool
+	DebugProxy                       bool
+	StartupHealthCheck               bool
+	FlattenBodySchema                bool
+	NoGenericBody                    bool
+	AllowDeleteBody                  bool
+	StrictSchemas                    bool
+	EnableSwaggerValidation          bool
+	NullableKeyword                  bool
+	LogToolErrors                    bool
+	StripHTML                        bool
 }
 
 // NewWithConfig creates a new EchoMCP instance with the provided configuration.
@@ -101,8 +206,26 @@ type Config struct {
 //	}
 //	mcp := server.NewWithConfig(e, config)
 func NewWithConfig(e *echo.Echo, config *Config) *EchoMCP {
-	if config == nil {
-		config = &Config{}
+	return NewWithOptions(e, WithConfig(config))
+}
+
+// NewWithOptions creates a new EchoMCP instance configured with functional options.
+// Options are applied in order on top of an empty Config.
+//
+// If EnableSwaggerSchemas is true and Name, Description, or Version are empty,
+// they will be automatically populated from Swagger annotations if available.
+//
+// Example:
+//
+//	mcp := server.NewWithOptions(e,
+//		server.WithBaseURL("http://localhost:8080"),
+//		server.WithSwaggerSchemas(),
+//		server.WithExcludeEndpoints("/health", "/metrics"),
+//	)
+func NewWithOptions(e *echo.Echo, opts ...Option) *EchoMCP {
+	config := &Config{}
+	for _, opt := range opts {
+		opt(config)
 	}
 
 	// Auto-populate name, description, and version from Swagger if available and not provided
@@ -110,10 +233,13 @@ func NewWithConfig(e *echo.Echo, config *Config) *EchoMCP {
 	description := config.Description
 	version := config.Version
 	var swaggerSpec *swagger.SwaggerSpec
+	var swaggerErr error
 
 	// Try to parse OpenAPISchema if provided
 	if config.OpenAPISchema != "" {
-		if spec, err := swagger.ParseOpenAPISchema(config.OpenAPISchema); err == nil {
+		if spec, err := swagger.ParseOpenAPISchema(config.OpenAPISchema); err != nil {
+			swaggerErr = fmt.Errorf("failed to parse OpenAPISchema: %w", err)
+		} else {
 			swaggerSpec = spec
 			if name == "" && spec.Info != nil && spec.Info.Title != "" {
 				name = spec.Info.Title
@@ -125,8 +251,10 @@ func NewWithConfig(e *echo.Echo, config *Config) *EchoMCP {
 				version = spec.Info.Version
 			}
 		}
-	} else if config.EnableSwaggerSchemas {
-		if spec, err := swagger.GetSwaggerSpec(); err == nil && spec.Info != nil {
+	} else if config.EnableSwaggerSchemas || config.SwaggerSpecURL != "" {
+		if spec, err := loadSwaggerSpec(config); err != nil {
+			swaggerErr = fmt.Errorf("failed to load swagger spec: %w", err)
+		} else if spec.Info != nil {
 			swaggerSpec = spec
 			if name == "" && spec.Info.Title != "" {
 				name = spec.Info.Title
@@ -140,6 +268,16 @@ func NewWithConfig(e *echo.Echo, config *Config) *EchoMCP {
 		}
 	}
 
+	// BasePath and NullableKeyword adjust the parsed spec, which is shared, so copy it
+	if swaggerSpec != nil && (config.BasePath != "" || config.NullableKeyword) {
+		configured := *swaggerSpec
+		if config.BasePath != "" {
+			configured.BasePath = config.BasePath
+		}
+		configured.NullableKeyword = config.NullableKeyword
+		swaggerSpec = &configured
+	}
+
 	echoMCP := &EchoMCP{
 		echo:              e,
 		name:              name,
@@ -150,7 +288,26 @@ func NewWithConfig(e *echo.Echo, config *Config) *EchoMCP {
 		registeredSchemas: make(map[string]types.RegisteredSchemaInfo),
 		tools:             []types.Tool{},
 		operations:        make(map[string]types.Operation),
+		cookieJars:        make(map[string]http.CookieJar),
+		customTools:       make(map[string]customTool),
+		toolTimeouts:      make(map[string]time.Duration),
 		swaggerSpec:       swaggerSpec,
+		swaggerErr:        swaggerErr,
+		includeEndpoints:  config.IncludeEndpoints,
+		excludeEndpoints:  config.ExcludeEndpoints,
+	}
+
+	if config.DescriptionTemplate != "" {
+		echoMCP.descTemplate, echoMCP.configErr = template.New("description").Parse(config.DescriptionTemplate)
+	}
+
+	// Without an explicit BaseURL, calls go to the server described by the swagger host
+	if config.BaseURL == "" && swaggerSpec != nil && (config.EnableSwaggerSchemas || config.SwaggerSpecURL != "") {
+		echoMCP.baseURL = echoMCP.swaggerBaseURL(swaggerSpec)
+	}
+
+	if config.HTTPClient != nil && echoMCP.baseURL == "" {
+		echoMCP.configErr = errors.New("HTTPClient requires BaseURL to be set")
 	}
 
 	// Set default execute function (in the future )
@@ -159,6 +316,44 @@ func NewWithConfig(e *echo.Echo, config *Config) *EchoMCP {
 	return echoMCP
 }
 
+// swaggerBaseURL returns the base URL derived from the swagger host and schemes, warning
+// about and ignoring a host that does not form a valid URL
+func (e *EchoMCP) swaggerBaseURL(spec *swagger.SwaggerSpec) string {
+	baseURL, err := spec.BaseURL()
+	if err != nil {
+		e.addWarning(fmt.Sprintf("ignoring swagger host: %v", err))
+		return ""
+	}
+
+	if baseURL != "" {
+		log.Infof("[MCP] Using base URL %s from the swagger host (basePath %q)", baseURL, spec.BasePath)
+	}
+	return baseURL
+}
+
+// loadSwaggerSpec loads the swagger spec from Config.SwaggerSpecURL when set, and from the
+// swaggo registry otherwise
+func loadSwaggerSpec(config *Config) (*swagger.SwaggerSpec, error) {
+	if config.SwaggerSpecURL != "" {
+		return swagger.GetSwaggerSpecFromURL(config.SwaggerSpecURL)
+	}
+	return swagger.GetSwaggerSpec()
+}
+
+// NewForTags creates a new EchoMCP instance exposing only the routes whose swagger
+// operations carry at least one of the given tags. Swagger schemas are enabled.
+// It makes it easy to serve each tag group as a distinct MCP endpoint.
+//
+// Example:
+//
+//	spec, _ := swagger.GetSwaggerSpec()
+//	for _, tag := range spec.ListTags() {
+//		server.NewForTags(e, []string{tag}).Mount("/mcp/" + tag)
+//	}
+func NewForTags(e *echo.Echo, tags []string) *EchoMCP {
+	return NewWithOptions(e, WithSwaggerSchemas(), WithIncludeTags(tags...))
+}
+
 // New creates a new EchoMCP instance with default configuration.
 // EnableSwaggerSchemas is enabled by default. Name, Description, and Version
 // are automatically populated from Swagger annotations if available.
@@ -181,8 +376,14 @@ func New(e *echo.Echo) *EchoMCP {
 	description := config.Description
 	version := config.Version
 
+	var swaggerErr error
 	if config.EnableSwaggerSchemas && (name == "" || description == "" || version == "") {
-		if spec, err := swagger.GetSwaggerSpec(); err == nil && spec.Info != nil {
+		// Swagger is optional here, so only a registered but unreadable spec is reported
+		if spec, err := swagger.GetSwaggerSpec(); err != nil {
+			if !errors.Is(err, swagger.ErrSpecNotFound) {
+				swaggerErr = fmt.Errorf("failed to load swagger spec: %w", err)
+			}
+		} else if spec.Info != nil {
 			if name == "" && spec.Info.Title != "" {
 				name = spec.Info.Title
 			}
@@ -205,6 +406,15 @@ func New(e *echo.Echo) *EchoMCP {
 		registeredSchemas: make(map[string]types.RegisteredSchemaInfo),
 		tools:             []types.Tool{},
 		operations:        make(map[string]types.Operation),
+		cookieJars:        make(map[string]http.CookieJar),
+		customTools:       make(map[string]customTool),
+		toolTimeouts:      make(map[string]time.Duration),
+		swaggerErr:        swaggerErr,
+	}
+
+	// Calls go to the server described by the swagger host, when one is registered
+	if spec, err := swagger.GetSwaggerSpec(); err == nil {
+		echoMCP.baseURL = echoMCP.swaggerBaseURL(spec)
 	}
 
 	// Set default execute function (in the future we should handle SSE)
@@ -215,6 +425,7 @@ func New(e *echo.Echo) *EchoMCP {
 
 // RegisterSchema registers Go types for query parameters and request body for a specific route.
 // This provides type-safe schema generation for routes that aren't covered by Swagger annotations.
+// Other settings of the route, such as static parameters, are kept whatever the call order.
 //
 // Parameters:
 //   - method: HTTP method (e.g., "GET", "POST")
@@ -237,14 +448,62 @@ func New(e *echo.Echo) *EchoMCP {
 //	mcp.RegisterSchema("GET", "/users", UserQuery{}, nil)
 //	mcp.RegisterSchema("POST", "/users", nil, CreateUserRequest{})
 func (e *EchoMCP) RegisterSchema(method, path string, querySchema, bodySchema any) {
-	e.schemasMu.Lock()
-	defer e.schemasMu.Unlock()
+	e.updateSchema(method, path, func(info *types.RegisteredSchemaInfo) {
+		info.QuerySchema = querySchema
+		info.BodySchema = bodySchema
+	})
+}
 
+// RegisterSchemaFromStruct registers query parameters and request body for a specific route
+// from a single combined request struct. Fields tagged with form, query, or header become
+// query parameters, fields tagged only with json become request body properties.
+//
+// Example:
+//
+//	type UserPatchRequest struct {
+//		ID     string `json:"-" param:"id"`
+//		Notify bool   `query:"notify"`
+//		Name   string `json:"name" jsonschema:"required"`
+//	}
+//
+//	mcp.RegisterSchemaFromStruct("PATCH", "/users/:id", UserPatchRequest{})
+func (e *EchoMCP) RegisterSchemaFromStruct(method, path string, schema any) {
+	querySchema, bodySchema := types.SplitSchema(schema)
+	e.RegisterSchema(method, path, querySchema, bodySchema)
+}
+
+// RegisterHeaders registers the header parameters of a specific route from a struct whose
+// fields are tagged with the header name. Registered headers are added to the tool input
+// schema and sent as request headers, whatever the case of the argument names.
+//
+// Example:
+//
+//	type TenantHeaders struct {
+//		TenantID string `header:"X-Tenant-Id" jsonschema:"required"`
+//	}
+//
+//	mcp.RegisterHeaders("GET", "/users", TenantHeaders{})
+func (e *EchoMCP) RegisterHeaders(method, path string, headers any) {
+	headerSchema := types.GetHeaderSchema(headers)
+	e.updateSchema(method, path, func(info *types.RegisteredSchemaInfo) {
+		info.HeaderSchema = headerSchema
+	})
+}
+
+// RemoveSchema removes a previously registered schema for a specific route.
+// If the server has already been mounted, the tools list is rebuilt so the
+// route falls back to Swagger or inferred schemas.
+//
+// Example:
+//
+//	mcp.RemoveSchema("POST", "/users")
+func (e *EchoMCP) RemoveSchema(method, path string) {
+	e.schemasMu.Lock()
 	key := fmt.Sprintf("%s %s", method, path)
-	e.registeredSchemas[key] = types.RegisteredSchemaInfo{
-		QuerySchema: querySchema,
-		BodySchema:  bodySchema,
-	}
+	delete(e.registeredSchemas, key)
+	e.schemasMu.Unlock()
+
+	e.refreshIfMounted()
 }
 
 // RegisterEndpoints sets the specific endpoints to include in MCP tools.
@@ -264,6 +523,8 @@ func (e *EchoMCP) RegisterSchema(method, path string, querySchema, bodySchema an
 //		"/api/v1/orders/*",
 //	})
 func (e *EchoMCP) RegisterEndpoints(endpoints []string) {
+	e.endpointsMu.Lock()
+	defer e.endpointsMu.Unlock()
 	e.includeEndpoints = endpoints
 }
 
@@ -285,9 +546,51 @@ func (e *EchoMCP) RegisterEndpoints(endpoints []string) {
 //		"/swagger/*",
 //	})
 func (e *EchoMCP) ExcludeEndpoints(endpoints []string) {
+	e.endpointsMu.Lock()
+	defer e.endpointsMu.Unlock()
 	e.excludeEndpoints = endpoints
 }
 
+// ExcludeGroups excludes every route registered under the given Echo group prefixes.
+// It is a convenience alias for ExcludeEndpoints with "/*" appended to each prefix.
+//
+// Example:
+//
+//	admin := e.Group("/admin")
+//	mcp.ExcludeGroups("/admin")
+func (e *EchoMCP) ExcludeGroups(prefixes ...string) {
+	endpoints := make([]string, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		endpoints = append(endpoints, strings.TrimSuffix(prefix, "/")+"/*")
+	}
+
+	e.ExcludeEndpoints(endpoints)
+}
+
+// RemoveEndpointFromInclude removes a path from the include list set by RegisterEndpoints.
+// If the server has already been mounted, the tools list is rebuilt.
+func (e *EchoMCP) RemoveEndpointFromInclude(path string) {
+	e.endpointsMu.Lock()
+	e.includeEndpoints = slices.DeleteFunc(slices.Clone(e.includeEndpoints), func(endpoint string) bool {
+		return endpoint == path
+	})
+	e.endpointsMu.Unlock()
+
+	e.refreshIfMounted()
+}
+
+// RemoveEndpointFromExclude removes a path from the exclude list set by ExcludeEndpoints.
+// If the server has already been mounted, the tools list is rebuilt.
+func (e *EchoMCP) RemoveEndpointFromExclude(path string) {
+	e.endpointsMu.Lock()
+	e.excludeEndpoints = slices.DeleteFunc(slices.Clone(e.excludeEndpoints), func(endpoint string) bool {
+		return endpoint == path
+	})
+	e.endpointsMu.Unlock()
+
+	e.refreshIfMounted()
+}
+
 // Mount mounts the MCP server at the specified path and registers it with the Echo instance.
 // This creates the HTTP endpoint that MCP clients will connect to.
 //
@@ -303,8 +606,70 @@ func (e *EchoMCP) ExcludeEndpoints(endpoints []string) {
 // After mounting, the MCP server will be available at the specified path.
 // MCP clients can connect to this endpoint to discover and execute tools.
 func (e *EchoMCP) Mount(path string) error {
-	// Create HTTP transport first
-	e.transport = transport.NewHTTPTransport(path)
+	return e.mount(path, e.echo.Add)
+}
+
+// MountOnGroup mounts the MCP server at path within an Echo group, so the MCP endpoint
+// runs behind the group middleware (authentication, versioning, ...). The endpoint is
+// served at the group prefix followed by path.
+//
+// Example:
+//
+//	api := e.Group("/api/v1", authMiddleware)
+//	if err := mcp.MountOnGroup(api, "/mcp"); err != nil { // serves /api/v1/mcp
+//		log.Fatal("Failed to mount MCP server:", err)
+//	}
+func (e *EchoMCP) MountOnGroup(g *echo.Group, path string) error {
+	if g == nil {
+		return errors.New("group is required")
+	}
+	return e.mount(path, g.Add)
+}
+
+// routeAdder registers a route on an Echo instance or group
+type routeAdder func(method, path string, handler echo.HandlerFunc, middleware ...echo.MiddlewareFunc) *echo.Route
+
+// mount sets up the transport and registers the MCP endpoint at path through add
+func (e *EchoMCP) mount(path string, add routeAdder) error {
+	if e.configErr != nil {
+		return fmt.Errorf("invalid configuration: %w", e.configErr)
+	}
+
+	// Mounting twice would register the handlers again and leak the first transport
+	if e.isUnmounted() {
+		return errors.New("MCP server has been unmounted and cannot be mounted again")
+	}
+	if e.transport != nil {
+		return fmt.Errorf("MCP server is already mounted at %s", e.transport.MountPath())
+	}
+
+	// Surface swagger load failures instead of silently falling back to inferred schemas
+	if e.swaggerErr != nil {
+		if e.config.StrictSwagger {
+			return e.swaggerErr
+		}
+		e.addWarning(e.swaggerErr.Error())
+	}
+
+	// Fail fast when the upstream server is unreachable or unhealthy
+	if e.config.StartupHealthCheck {
+		if ok, err := e.HealthCheck(); !ok {
+			return fmt.Errorf("startup health check failed: %w", err)
+		}
+	}
+
+	// Register the MCP endpoint first: its route carries the full path, including any group prefix
+	middleware := []echo.MiddlewareFunc{e.rejectUnmounted}
+	if len(e.config.CORSOrigins) > 0 {
+		middleware = append(middleware, e.corsMiddleware)
+	}
+	mountPath := add(http.MethodPost, path, e.handleMessage, middleware...).Path
+
+	// Create HTTP transport
+	httpTransport := transport.NewHTTPTransport(mountPath)
+	httpTransport.SetSessionTTL(e.config.SessionTTL)
+	httpTransport.SetDiscovery(e.discovery)
+	e.transport = httpTransport
 
 	if err := e.setupServer(); err != nil {
 		return fmt.Errorf("failed to setup server: %w", err)
@@ -313,13 +678,87 @@ func (e *EchoMCP) Mount(path string) error {
 	// Register handlers
 	e.transport.RegisterHandler("initialize", e.handleInitialize)
 	e.transport.RegisterHandler("tools/list", e.handleToolsList)
-	e.transport.RegisterHandler("tools/call", e.handleToolCall)
+	e.transport.RegisterContextHandler("tools/call", e.handleToolCall)
+	e.transport.RegisterContextHandler("logging/setLevel", e.handleSetLevel)
+	e.transport.RegisterHandler("completion/complete", e.handleComplete)
+	e.transport.RegisterHandler("ping", e.handlePing)
+	e.transport.RegisterHandler("pong", e.handlePing)
+	e.transport.OnSessionClosed(e.dropCookieJar)
+	e.transport.OnSessionClosed(e.dropLogLevel)
+	e.transport.OnSessionClosed(e.dropSessionToolCallSlots)
+
+	// Drain tool calls and close sessions when the Echo server shuts down
+	if e.echo.Server != nil {
+		e.echo.Server.RegisterOnShutdown(func() {
+			if err := e.Shutdown(context.Background()); err != nil {
+				log.Warnf("[MCP] Shutdown failed: %v", err)
+			}
+		})
+	}
+
+	// Handle discovery, session termination and CORS preflight requests next to the MCP messages
+	add(http.MethodGet, path, e.transport.HandleConnection, middleware...)
+	add(http.MethodDelete, path, httpTransport.HandleSessionClose, middleware...)
+	if len(e.config.CORSOrigins) > 0 {
+		add(http.MethodOptions, path, e.handlePreflight, middleware...)
+	}
+
+	if e.config.EnableToolsDebugEndpoint {
+		add(http.MethodGet, path+"/tools", e.handleToolsDebug, e.rejectUnmounted)
+	}
+
+	// Pick up routes registered after Mount
+	if e.config.RouteRefreshInterval > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		e.stopRouteWatch = cancel
+		go e.watchRoutes(ctx)
+	}
 
-	// Handle HTTP messages (Streamable HTTP transport)
-	e.echo.POST(path, e.transport.HandleMessage)
 	return nil
 }
 
+// Refresh rebuilds the tools list from the current Echo routes, registered schemas
+// and endpoint filters. It is useful after reconfiguring the server at runtime.
+// See RefreshTools to learn whether the tools changed.
+func (e *EchoMCP) Refresh() error {
+	_, err := e.RefreshTools()
+	return err
+}
+
+// basePath returns Config.BasePath, falling back to the swagger basePath
+func (e *EchoMCP) basePath() string {
+	if e.config.BasePath != "" {
+		return e.config.BasePath
+	}
+	if e.swaggerSpec != nil {
+		return e.swaggerSpec.BasePath
+	}
+	return ""
+}
+
+// withBasePath prefixes requestPath with basePath unless the route already includes it
+func withBasePath(basePath, requestPath string) string {
+	basePath = strings.TrimSuffix(basePath, "/")
+	if basePath == "" || requestPath == basePath || strings.HasPrefix(requestPath, basePath+"/") || strings.HasPrefix(requestPath, basePath+"?") {
+		return requestPath
+	}
+	return basePath + requestPath
+}
+
+// handleMessage forwards MCP messages (Streamable HTTP transport) to the mounted transport
+func (e *EchoMCP) handleMessage(c echo.Context) error {
+	return e.transport.HandleMessage(c)
+}
+
+// refreshIfMounted rebuilds the tools list only when the server has been mounted
+func (e *EchoMCP) refreshIfMounted() {
+	if e.transport == nil {
+		return
+	}
+
+	_ = e.Refresh()
+}
+
 // setupServer initializes tools and operations from registered routes
 func (e *EchoMCP) setupServer() error {
 	e.schemasMu.RLock()
@@ -327,6 +766,13 @@ func (e *EchoMCP) setupServer() error {
 	maps.Copy(registeredSchemas, e.registeredSchemas)
 	e.schemasMu.RUnlock()
 
+	// Report swagger spec problems that degrade the generated tools
+	if e.config.EnableSwaggerValidation {
+		for _, warning := range swagger.ValidateForMCP(e.swaggerSpec) {
+			e.addWarning("swagger " + warning.String())
+		}
+	}
+
 	// Get routes from Echo
 	routes := e.echo.Routes()
 
@@ -334,17 +780,75 @@ func (e *EchoMCP) setupServer() error {
 	filteredRoutes := e.filterRoutes(routes)
 
 	// Convert routes to tools
-	tools, operations := convert.ConvertRoutesToTools(filteredRoutes, registeredSchemas, e.swaggerSpec)
+	tools, operations := convert.ConvertRoutesToToolsWithOptions(filteredRoutes, registeredSchemas, e.swaggerSpec, e.convertOptions())
+
+	// Merge tools from additional Echo instances
+	instanceTools, instanceOperations, owners := e.convertInstances(registeredSchemas, operations)
+	tools = append(tools, instanceTools...)
+	maps.Copy(operations, instanceOperations)
+
+	// Add aliases of route tools registered with RegisterToolAlias
+	tools = e.applyAliases(tools, operations, owners)
+
+	// Append custom tools registered with RegisterTool, which take over route tools of the same name
+	tools = e.appendCustomTools(tools, operations, owners)
+
+	// Namespace tool names and warn about names MCP clients would reject
+	prefix := e.toolPrefix()
+	for i := range tools {
+		tools[i].Name = prefix + tools[i].Name
+		if !convert.IsValidToolName(tools[i].Name, e.maxToolNameLength()) {
+			e.addWarning(fmt.Sprintf("tool name '%s' is not a valid MCP tool name (letters, digits, '_' and '-', at most %d characters)", tools[i].Name, e.maxToolNameLength()))
+		}
+	}
 
+	// Sort tools so tools/list output is stable across restarts
+	slices.SortStableFunc(tools, func(a, b types.Tool) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	// Swap the new tools in atomically so concurrent tools/call requests never see a partial state
+	e.toolsMu.Lock()
 	e.tools = tools
 	e.operations = operations
+	e.operationOwners = owners
+	e.toolsMu.Unlock()
 
 	return nil
 }
 
+// convertOptions returns the route conversion options derived from the configuration
+func (e *EchoMCP) convertOptions() convert.Options {
+	return convert.Options{
+		OperationIDTransform:      e.config.OperationIDTransform,
+		DescriptionTemplate:       e.descTemplate,
+		DescriptionFormatter:      e.config.DescriptionFormatter,
+		DescribeAllResponses:      e.config.DescribeAllResponses,
+		RequireSecurityParameters: e.config.RequireSecurityParameters,
+		PreferSwaggerOperationID:  e.config.PreferSwaggerOperationID,
+		FlattenBodySchema:         e.config.FlattenBodySchema,
+		NoGenericBody:             e.config.NoGenericBody,
+		AllowDeleteBody:           e.config.AllowDeleteBody,
+		StrictSchemas:             e.config.StrictSchemas,
+		ToolNamePrefix:            e.config.ToolNamePrefix,
+		ToolNameSuffix:            e.config.ToolNameSuffix,
+		// Leave room for the tool prefix added in setupServer
+		MaxToolNameLength: max(e.maxToolNameLength()-len(e.toolPrefix()), 1),
+	}
+}
+
+// maxToolNameLength returns Config.MaxToolNameLength or the default MCP limit
+func (e *EchoMCP) maxToolNameLength() int {
+	if e.config.MaxToolNameLength > 0 {
+		return e.config.MaxToolNameLength
+	}
+	return convert.DefaultMaxToolNameLength
+}
+
 // filterRoutes filters routes based on configuration
 func (e *EchoMCP) filterRoutes(routes []*echo.Route) []*echo.Route {
 	var filtered []*echo.Route
+	getPaths := getRoutePaths(routes)
 
 	for _, route := range routes {
 		// Skip MCP endpoints (only if transport is initialized)
@@ -352,8 +856,7 @@ func (e *EchoMCP) filterRoutes(routes []*echo.Route) []*echo.Route {
 			continue
 		}
 
-		// Apply endpoint filtering
-		if !e.shouldIncludeRoute(route) {
+		if !e.isRouteExposed(route, getPaths, e.swaggerSpec) {
 			continue
 		}
 
@@ -363,10 +866,101 @@ func (e *EchoMCP) filterRoutes(routes []*echo.Route) []*echo.Route {
 	return filtered
 }
 
+// isRouteExposed applies the route kind and endpoint filters shared by every Echo instance,
+// and the tag, deprecation and exclusion filters of spec, the swagger spec of the route's
+// instance (nil when it has none)
+func (e *EchoMCP) isRouteExposed(route *echo.Route, getPaths map[string]bool, spec *swagger.SwaggerSpec) bool {
+	// Skip excluded HTTP methods such as CORS preflight OPTIONS routes
+	if slices.ContainsFunc(e.config.excludedHTTPMethods(), func(method string) bool {
+		return strings.EqualFold(method, route.Method)
+	}) {
+		return false
+	}
+
+	// Skip catch-all routes such as e.Static when configured
+	if e.config.SkipCatchAllRoutes && convert.IsCatchAll(route.Path) {
+		return false
+	}
+
+	// Skip HEAD routes that duplicate a GET route
+	if e.config.skipHeadRoutes() && route.Method == http.MethodHead && getPaths[route.Path] {
+		return false
+	}
+
+	// Apply swagger tag filtering
+	if len(e.config.IncludeTags) > 0 || len(e.config.ExcludeTags) > 0 {
+		tags := e.routeTags(route, spec)
+		hasTag := func(tag string) bool { return slices.Contains(tags, tag) }
+		if len(e.config.IncludeTags) > 0 && !slices.ContainsFunc(e.config.IncludeTags, hasTag) {
+			return false
+		}
+		if slices.ContainsFunc(e.config.ExcludeTags, hasTag) {
+			return false
+		}
+	}
+
+	// Skip operations marked deprecated in the swagger spec when configured
+	if e.config.SkipDeprecated && spec != nil && spec.IsDeprecated(route.Method, route.Path) {
+		return false
+	}
+
+	// Skip operations hidden from MCP with the x-mcp-exclude swagger extension
+	if spec != nil && spec.IsExcluded(route.Method, route.Path) {
+		return false
+	}
+
+	// Apply endpoint filtering
+	return e.shouldIncludeRoute(route)
+}
+
+// routeTags returns the tags of a route in spec, or the tags registered with Handle
+func (e *EchoMCP) routeTags(route *echo.Route, spec *swagger.SwaggerSpec) []string {
+	if spec != nil {
+		if operation, exists := spec.GetOperation(route.Method, route.Path); exists && len(operation.Tags) > 0 {
+			return operation.Tags
+		}
+	}
+
+	e.schemasMu.RLock()
+	defer e.schemasMu.RUnlock()
+	return e.registeredSchemas[fmt.Sprintf("%s %s", route.Method, route.Path)].Tags
+}
+
+// getRoutePaths returns the set of paths registered with a GET route
+func getRoutePaths(routes []*echo.Route) map[string]bool {
+	paths := make(map[string]bool)
+	for _, route := range routes {
+		if route.Method == http.MethodGet {
+			paths[route.Path] = true
+		}
+	}
+	return paths
+}
+
+// excludedHTTPMethods returns the HTTP methods never exposed as tools. Unless ExcludeHTTPMethods
+// is set, OPTIONS, HEAD and TRACE are excluded, keeping HEAD when SkipHeadRoutes is disabled.
+func (c *Config) excludedHTTPMethods() []string {
+	if c.ExcludeHTTPMethods != nil {
+		return c.ExcludeHTTPMethods
+	}
+	if !c.skipHeadRoutes() {
+		return []string{http.MethodOptions, http.MethodTrace}
+	}
+	return defaultExcludedHTTPMethods
+}
+
+// skipHeadRoutes reports whether HEAD routes duplicating a GET route are dropped (default true)
+func (c *Config) skipHeadRoutes() bool {
+	return c.SkipHeadRoutes == nil || *c.SkipHeadRoutes
+}
+
 // shouldIncludeRoute determines if a route should be included based on include/exclude filters
 func (e *EchoMCP) shouldIncludeRoute(route *echo.Route) bool {
 	routePath := route.Path
 
+	e.endpointsMu.RLock()
+	defer e.endpointsMu.RUnlock()
+
 	// If includeEndpoints is set, only include routes that match
 	if len(e.includeEndpoints) > 0 {
 		for _, included := range e.includeEndpoints {
@@ -390,75 +984,134 @@ func (e *EchoMCP) shouldIncludeRoute(route *echo.Route) bool {
 	return true
 }
 
-// matchesEndpoint checks if a route path matches an endpoint pattern
+// matchesEndpoint checks if a route path matches an endpoint pattern, segment by segment.
+// A ":param" segment in the pattern matches any non-empty segment, a ":param" segment in the
+// route matches any pattern parameter, a "*" segment matches any single segment, and a
+// trailing "*" matches the rest of the path (e.g. "/users/*" matches "/users/:id/orders").
 func (e *EchoMCP) matchesEndpoint(routePath, pattern string) bool {
 	// Exact match
 	if routePath == pattern {
 		return true
 	}
 
-	// Prefix match (for patterns ending with *)
-	if before, ok := strings.CutSuffix(pattern, "*"); ok {
-		prefix := before
-		return strings.HasPrefix(routePath, prefix)
-	}
+	routeSegments := strings.Split(routePath, "/")
+	patternSegments := strings.Split(pattern, "/")
 
-	// Wildcard match for path parameters
-	// Convert Echo path params (:param) to match pattern
-	if strings.Contains(routePath, ":") {
-		// Simple pattern matching - replace :param with actual values
-		// This is a basic implementation, could be enhanced
-		routePattern := strings.ReplaceAll(routePath, ":id", "*")
-		routePattern = strings.ReplaceAll(routePattern, ":param", "*")
+	for i, patternSegment := range patternSegments {
+		// Prefix match (for patterns ending with *)
+		if i == len(patternSegments)-1 {
+			if prefix, ok := strings.CutSuffix(patternSegment, "*"); ok {
+				return i < len(routeSegments) && strings.HasPrefix(routeSegments[i], prefix)
+			}
+		}
 
-		if pattern == routePattern {
-			return true
+		if i >= len(routeSegments) || !segmentMatches(routeSegments[i], patternSegment) {
+			return false
 		}
 	}
 
-	return false
+	return len(routeSegments) == len(patternSegments)
 }
 
-// handleInitialize handles MCP initialize requests
-func (e *EchoMCP) handleInitialize(params any) (any, error) {
-	version := e.version
-	if version == "" {
-		version = "1.0.0" // Fallback default
+// segmentMatches reports whether a route path segment matches a pattern segment.
+// Route parameters only match pattern parameters, not concrete values.
+func segmentMatches(routeSegment, patternSegment string) bool {
+	if routeSegment == patternSegment {
+		return true
 	}
+	return routeSegment != "" && (patternSegment == "*" || strings.HasPrefix(patternSegment, ":"))
+}
 
+// handleInitialize handles MCP initialize requests
+func (e *EchoMCP) handleInitialize(params any) (any, error) {
 	return InitializeResponse{
-		ProtocolVersion: "2024-11-05",
-		Capabilities: &Capabilities{
-			Tools: map[string]any{},
-		},
+		ProtocolVersion: protocolVersion,
+		Capabilities:    e.capabilities(),
 		ServerInfo: &ServerInfo{
 			Name:    e.name,
-			Version: version,
+			Version: e.serverVersion(),
 		},
 	}, nil
 }
 
-// handleToolsList handles tools/list requests
+// handlePing handles MCP ping requests (and pong, its alias), which clients send to check that
+// a long-lived session is still alive. It returns an empty result.
+func (e *EchoMCP) handlePing(params any) (any, error) {
+	return map[string]any{}, nil
+}
+
+// discovery builds the server metadata returned to GET requests on the MCP endpoint: the
+// initialize response, so clients can probe capabilities without starting a session
+func (e *EchoMCP) discovery() any {
+	response, _ := e.handleInitialize(nil)
+	return response
+}
+
+// capabilities returns the capabilities advertised to clients
+func (e *EchoMCP) capabilities() *Capabilities {
+	tools := map[string]any{}
+	if e.supportsListChanged() {
+		tools["listChanged"] = true
+	}
+
+	return &Capabilities{
+		Tools:       tools,
+		Logging:     map[string]any{},
+		Completions: map[string]any{},
+	}
+}
+
+// serverVersion returns the configured version, falling back to a default
+func (e *EchoMCP) serverVersion() string {
+	if e.version == "" {
+		return "1.0.0" // Fallback default
+	}
+	return e.version
+}
+
+// handleToolsList handles tools/list requests. The first page picks up route changes through
+// RefreshTools, so clients are notified and schema changes logged; later pages are served from
+// the same cached tool set.
 func (e *EchoMCP) handleToolsList(params any) (any, error) {
-	if err := e.setupServer(); err != nil {
-		return nil, fmt.Errorf("failed to setup server: %w", err)
+	var request types.ToolsListRequest
+	if paramMap, ok := params.(map[string]any); ok {
+		request.Cursor, _ = paramMap["cursor"].(string)
+	}
+
+	if request.Cursor == "" {
+		if _, err := e.RefreshTools(); err != nil {
+			return nil, err
+		}
+	}
+
+	tools := e.GetTools()
+
+	// Hide deprecated tools from the listing; they remain callable by name
+	if e.config.ExcludeDeprecated {
+		tools = slices.DeleteFunc(tools, func(tool types.Tool) bool { return tool.Deprecated })
+	}
+
+	page, nextCursor, err := e.paginateTools(tools, request.Cursor)
+	if err != nil {
+		return nil, toMCPError(err)
 	}
 
 	return ToolsListResponse{
-		Tools: e.tools,
+		Tools:      page,
+		NextCursor: nextCursor,
 	}, nil
 }
 
 // handleToolCall handles tools/call requests
-func (e *EchoMCP) handleToolCall(params any) (any, error) {
+func (e *EchoMCP) handleToolCall(ctx context.Context, params any) (any, error) {
 	paramMap, ok := params.(map[string]any)
 	if !ok {
-		return nil, errors.New("invalid parameters")
+		return nil, types.NewMCPError(types.ErrCodeInvalidParams, "invalid parameters", nil)
 	}
 
 	toolName, ok := paramMap["name"].(string)
 	if !ok {
-		return nil, errors.New("missing tool name")
+		return nil, types.NewMCPError(types.ErrCodeInvalidParams, "missing tool name", nil)
 	}
 
 	arguments, ok := paramMap["arguments"].(map[string]any)
@@ -466,10 +1119,77 @@ func (e *EchoMCP) handleToolCall(params any) (any, error) {
 		arguments = make(map[string]any)
 	}
 
-	result, err := e.executeToolFunc(toolName, arguments)
+	// Tools are listed with Config.ToolPrefix; operations are looked up by their unprefixed name
+	toolName, ok = strings.CutPrefix(toolName, e.toolPrefix())
+	if !ok {
+		return nil, e.unknownToolError(toolName)
+	}
+
+	// Reject arguments missing from closed input schemas before they reach the API
+	if e.config.StrictSchemas {
+		if unexpected := e.unexpectedArguments(toolName, arguments); len(unexpected) > 0 {
+			return nil, toMCPError(InvalidParams(
+				fmt.Sprintf("unexpected arguments: %s", strings.Join(unexpected, ", ")),
+				map[string]any{"unexpected": unexpected},
+			))
+		}
+	}
+
+	// Track the call so Shutdown can drain it
+	if !e.beginCall() {
+		return nil, errors.New("server is shutting down")
+	}
+	defer e.calls.Done()
+
+	// Reject calls beyond the concurrency limits once the queue wait is over
+	release, err := e.acquireToolCallSlot(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
+
+	// Keep the client informed while long-running calls are in progress
+	stopProgress := e.startProgress(ctx, paramMap)
+	defer stopProgress()
+
+	var result any
+	if handler, isCustom := e.customToolHandler(toolName); isCustom {
+		result, err = handler(arguments)
+	} else {
+		result, err = e.executeToolFunc(ctx, toolName, arguments)
+	}
+	if err != nil {
+		if e.config.LogToolErrors {
+			e.logToSession(ctx, "warning", toolErrorLogger, map[string]any{"tool": toolName, "error": err.Error()})
+		}
+		return nil, toMCPError(err)
+	}
+
+	// Results with response headers are also returned as structured content
+	if withHeaders, hasHeaders := result.(*ResponseWithHeaders); hasHeaders {
+		return ToolCallResponse{
+			StructuredContent: withHeaders,
+			Content: []Content{
+				{
+					Type: "text",
+					Text: withHeaders.String(),
+				},
+			},
+		}, nil
+	}
+
+	// Dry-run previews are also returned as structured content
+	if preview, isPreview := result.(*DryRunPreview); isPreview {
+		return ToolCallResponse{
+			StructuredContent: preview,
+			Content: []Content{
+				{
+					Type: "text",
+					Text: preview.String(),
+				},
+			},
+		}, nil
+	}
 
 	return ToolCallResponse{
 		Content: []Content{
@@ -481,25 +1201,84 @@ func (e *EchoMCP) handleToolCall(params any) (any, error) {
 	}, nil
 }
 
+// toolPrefix returns Config.ToolPrefix with the characters MCP clients reject replaced
+func (e *EchoMCP) toolPrefix() string {
+	if e.config.ToolPrefix == "" {
+		return ""
+	}
+	return convert.SanitizeToolName(e.config.ToolPrefix)
+}
+
 // defaultExecuteTool executes a tool by dispatching a synthetic HTTP request
 // through the Echo router in-process, without making a real network call.
 // This eliminates the need for the server to be able to reach itself over the
 // network, which is important in containerized environments where the external
 // hostname may not resolve from inside the container.
-func (e *EchoMCP) defaultExecuteTool(operationID string, parameters map[string]any) (any, error) {
+func (e *EchoMCP) defaultExecuteTool(ctx context.Context, operationID string, parameters map[string]any) (any, error) {
+	e.toolsMu.RLock()
 	operation, exists := e.operations[operationID]
+	owner, hasOwner := e.operationOwners[operationID]
+	e.toolsMu.RUnlock()
+
 	if !exists {
-		return nil, fmt.Errorf("tool '%s' not found in operations map", operationID)
+		return nil, e.unknownToolError(operationID)
+	}
+
+	// Replay retries carrying the same _idempotencyKey instead of executing them again
+	parameters, idempotencyKey := popIdempotencyKey(parameters)
+	if key := e.idempotencyStoreKey(ctx, operationID, idempotencyKey); key != "" {
+		return e.replayIdempotent(ctx, key, func() (any, error) {
+			return e.defaultExecuteTool(ctx, operationID, parameters)
+		})
+	}
+
+	parameters, noCache := popNoCache(parameters)
+	parameters = applyDefaults(parameters, operation.Defaults)
+	parameters = withoutStaticParameters(parameters, &operation)
+	parameters = applyBoundArgs(parameters, operation.BoundArgs)
+
+	// Serve repeated GET and HEAD calls from the response cache; _noCache forces a fresh result
+	cacheKey := e.cacheKey(ctx, operationID, operation.Method, parameters)
+	if cacheKey != "" && !noCache {
+		if result, cached := e.cachedResult(cacheKey); cached {
+			return result, nil
+		}
+	}
+
+	timeout := e.toolTimeout(&operation)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	if err := validatePathParameters(&operation, parameters); err != nil {
+		return nil, err
 	}
 
 	// Build the request path (no base URL needed for in-process execution)
 	requestPath := e.buildRequestPath(&operation, parameters)
 
+	// Route the call to the Echo instance that owns the operation
+	target := e.echo
+	baseURL := e.baseURL
+	if hasOwner {
+		target = owner.echo
+		baseURL = owner.baseURL
+		requestPath = requestTarget(owner.baseURL, requestPath)
+	}
+
+	// Requests sent through a custom HTTP client go over the network to the base URL
+	client := e.httpClient()
+	if client != nil {
+		requestPath = strings.TrimSuffix(baseURL, "/") + withBasePath(e.basePath(), e.buildRequestPath(&operation, parameters))
+	}
+
 	// Create HTTP request with appropriate body format
-	var body io.Reader
+	var body []byte
 	var contentType string
 
-	if isBodyMethod(operation.Method) {
+	if operation.HasBody || isBodyMethod(operation.Method) {
 		// Check if this operation uses form data
 		if len(operation.FormDataParams) > 0 {
 			// Handle form data
@@ -510,8 +1289,16 @@ func (e *EchoMCP) defaultExecuteTool(operationID string, parameters map[string]a
 				}
 			}
 
-			if len(formData) > 0 {
-				body = strings.NewReader(formData.Encode())
+			if len(formData) > 0 && swagger.IsMultipartContentType(operation.ContentType) {
+				// Operations that only consume multipart forms get one part per field
+				multipartBody, multipartType, err := encodeMultipartForm(formData)
+				if err != nil {
+					return nil, fmt.Errorf("failed to encode multipart form: %w", err)
+				}
+				body = multipartBody
+				contentType = multipartType
+			} else if len(formData) > 0 {
+				body = []byte(formData.Encode())
 				contentType = "application/x-www-form-urlencoded"
 			}
 		} else {
@@ -526,64 +1313,340 @@ func (e *EchoMCP) defaultExecuteTool(operationID string, parameters map[string]a
 				}
 			}
 
-			if len(bodyData) > 0 {
+			if len(bodyData) > 0 && swagger.IsXMLContentType(operation.ContentType) {
+				// Operations that only consume XML get the arguments as an XML document
+				xmlBody, err := e.marshalXMLBody(&operation, bodyData)
+				if err != nil {
+					return nil, fmt.Errorf("failed to marshal XML request body: %w", err)
+				}
+				body = xmlBody
+				contentType = operation.ContentType
+			} else if len(bodyData) > 0 {
 				jsonBody, err := sonic.Marshal(bodyData)
 				if err != nil {
 					return nil, fmt.Errorf("failed to marshal request body: %w", err)
 				}
-				body = bytes.NewReader(jsonBody)
+				body = jsonBody
 				contentType = "application/json"
 			}
 		}
 	}
 
-	req := httptest.NewRequestWithContext(context.Background(), operation.Method, requestPath, body)
+	// Record the redirects followed by the custom HTTP client
+	var redirects []string
+	if client != nil {
+		ctx = withRedirects(ctx, &redirects)
+	}
 
-	// Set appropriate Content-Type
-	if contentType != "" {
-		req.Header.Set("Content-Type", contentType)
+	// Record the upstream request logged by Config.DebugProxy to report it on failures
+	var exchange *ProxyExchange
+	if e.config.DebugProxy {
+		exchange = &ProxyExchange{}
+		ctx = withExchange(ctx, exchange)
 	}
 
-	// Add header parameters
-	for key, value := range parameters {
-		if isHeaderParameter(&operation, key) {
-			req.Header.Set(key, fmt.Sprintf("%v", value))
+	// buildRequest creates the upstream request; it runs once per attempt since bodies are consumed
+	buildRequest := func() (*http.Request, error) {
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
 		}
+
+		var req *http.Request
+		if client != nil {
+			var err error
+			if req, err = http.NewRequestWithContext(ctx, operation.Method, requestPath, reader); err != nil {
+				return nil, fmt.Errorf("failed to create request: %w", err)
+			}
+		} else {
+			req = httptest.NewRequestWithContext(ctx, operation.Method, requestPath, reader)
+		}
+
+		// Set appropriate Content-Type
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+
+		// Add header parameters
+		for key, value := range parameters {
+			if isHeaderParameter(&operation, key) {
+				req.Header.Set(key, fmt.Sprintf("%v", value))
+			}
+		}
+
+		// Static headers override header arguments
+		for key, value := range operation.StaticHeaders {
+			req.Header.Set(key, value)
+		}
+
+		// Add static headers without overriding operation-level header parameters
+		for key, value := range e.config.RequestHeaders {
+			if req.Header.Get(key) == "" {
+				req.Header.Set(key, value)
+			}
+		}
+
+		// Let upstreams that support idempotency keys deduplicate retries themselves
+		if e.config.ForwardIdempotencyKey && idempotencyKey != "" {
+			req.Header.Set(idempotencyKeyHeader, idempotencyKey)
+		}
+
+		// Attach forwarded and session cookies
+		e.applyCookies(ctx, req)
+
+		// Let the transform sign or otherwise rewrite the finished request
+		if e.config.RequestTransform != nil {
+			if err := e.config.RequestTransform(req); err != nil {
+				return nil, fmt.Errorf("request transform failed: %w", err)
+			}
+		}
+
+		return req, nil
 	}
 
-	// Execute request in-process through the Echo router
-	rec := httptest.NewRecorder()
-	e.echo.ServeHTTP(rec, req)
+	// In dry-run mode, mutating operations return a preview instead of being sent
+	if e.isDryRun(ctx) && !isSafeMethod(operation.Method) {
+		req, err := buildRequest()
+		if err != nil {
+			return nil, err
+		}
+		return newDryRunPreview(req, baseURL, body, e.sensitiveHeaders()), nil
+	}
 
-	responseBody := rec.Body.Bytes()
+	// Execute request in-process through the Echo router (or through the custom HTTP client),
+	// retrying transient failures
+	resp, attempts, err := e.executeWithRetry(ctx, operation.Method, func() (*http.Response, error) {
+		redirects = redirects[:0]
+		req, err := buildRequest()
+		if err != nil {
+			return nil, &permanentError{err: err}
+		}
+
+		var resp *http.Response
+		if client != nil {
+			resp, err = client.Do(req)
+		} else {
+			resp, err = e.roundTripper(target).RoundTrip(req)
+		}
+		if err != nil {
+			return nil, err
+		}
+		e.storeCookies(ctx, req, resp)
 
-	// Try to parse as JSON, fall back to string
+		return resp, nil
+	})
+	if err != nil {
+		if timeout > 0 && errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("tool '%s' timed out after %s: %w", operationID, timeout, err)
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Transparently decompress gzip-encoded responses (e.g. from echo's Gzip middleware)
+	bodyReader, err := decodeResponseBody(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response body: %w", err)
+	}
+	defer bodyReader.Close()
+
+	responseBody, truncated, err := e.readResponseBody(bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	// Parse the response according to its Content-Type; redirects that were not followed and
+	// responses without a body report their status and headers instead
 	var result any
-	if jsonErr := sonic.Unmarshal(responseBody, &result); jsonErr != nil {
-		result = string(responseBody)
+	switch {
+	case isRedirect(resp):
+		result = redirectResult(resp)
+	case isBodylessResponse(operation.Method, resp.StatusCode, responseBody):
+		result = statusResult(resp)
+	default:
+		result = e.parseResponseBody(resp.Header.Get(echo.HeaderContentType), responseBody)
+	}
+
+	if truncated {
+		result = markTruncated(result, responseBody)
+	}
+
+	// Retries that ran out on a retryable status fail the call, keeping the upstream body
+	if e.config.Retry.retriesExhausted(operation.Method, resp) {
+		data := map[string]any{"status": resp.StatusCode, "attempts": attempts, "body": result}
+		if exchange != nil {
+			data["request"] = *exchange
+		}
+		return nil, Internal(
+			fmt.Sprintf("request failed with status %d after %d attempts", resp.StatusCode, attempts),
+			data,
+		)
+	}
+
+	// Report the upstream request of calls answered with a non-2xx status
+	var request *ProxyExchange
+	if exchange != nil && (resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices) {
+		request = exchange
+	}
+
+	// Surface the response headers the operation asks for and the redirects followed next to the body
+	headers := selectResponseHeaders(e.responseHeaderPatterns(&operation), resp)
+	if len(headers) > 0 || len(redirects) > 0 || request != nil {
+		result = &ResponseWithHeaders{Body: result, Headers: headers, Request: request, Redirects: slices.Clone(redirects)}
+	}
+
+	if cacheKey != "" {
+		e.storeResult(cacheKey, operationID, resp, result)
 	}
 
 	return result, nil
 }
 
+// decodeResponseBody returns a reader for the response body that undoes a gzip Content-Encoding
+func decodeResponseBody(resp *http.Response) (io.ReadCloser, error) {
+	if !strings.EqualFold(resp.Header.Get(echo.HeaderContentEncoding), "gzip") {
+		return io.NopCloser(resp.Body), nil
+	}
+	return gzip.NewReader(resp.Body)
+}
+
+// readResponseBody reads the response body up to Config.MaxResponseBodyBytes (0 means unlimited)
+// and reports whether the body was cut off
+func (e *EchoMCP) readResponseBody(body io.Reader) ([]byte, bool, error) {
+	limit := e.config.MaxResponseBodyBytes
+	if limit <= 0 {
+		data, err := io.ReadAll(body)
+		return data, false, err
+	}
+
+	// Read one extra byte to detect whether the body exceeds the limit
+	data, err := io.ReadAll(io.LimitReader(body, int64(limit)+1))
+	if err != nil {
+		return nil, false, err
+	}
+	if len(data) > limit {
+		return data[:limit], true, nil
+	}
+	return data, false, nil
+}
+
+// markTruncated flags a truncated JSON object with "truncated": true, or appends
+// a marker to the text result (or the raw body for any other response)
+func markTruncated(result any, responseBody []byte) any {
+	switch value := result.(type) {
+	case map[string]any:
+		value["truncated"] = true
+		return value
+	case string:
+		return value + "\n[response truncated]"
+	}
+	return string(responseBody) + "\n[response truncated]"
+}
+
+// applyDefaults returns parameters with the spec defaults filled in for omitted arguments
+func applyDefaults(parameters, defaults map[string]any) map[string]any {
+	if len(defaults) == 0 {
+		return parameters
+	}
+
+	merged := maps.Clone(parameters)
+	if merged == nil {
+		merged = map[string]any{}
+	}
+	for key, value := range defaults {
+		if _, exists := merged[key]; !exists {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// applyBoundArgs returns parameters with the bound arguments of a tool alias, which win over the supplied ones
+func applyBoundArgs(parameters, boundArgs map[string]any) map[string]any {
+	if len(boundArgs) == 0 {
+		return parameters
+	}
+
+	merged := maps.Clone(parameters)
+	if merged == nil {
+		merged = map[string]any{}
+	}
+	maps.Copy(merged, boundArgs)
+	return merged
+}
+
+// withoutStaticParameters drops arguments named like a static query parameter or header,
+// so values supplied by the model never reach the path or the body
+func withoutStaticParameters(parameters map[string]any, operation *types.Operation) map[string]any {
+	if len(operation.StaticQuery)+len(operation.StaticHeaders) == 0 {
+		return parameters
+	}
+
+	filtered := maps.Clone(parameters)
+	maps.DeleteFunc(filtered, func(key string, _ any) bool {
+		if _, exists := operation.StaticQuery[key]; exists {
+			return true
+		}
+		for header := range operation.StaticHeaders {
+			if strings.EqualFold(header, key) {
+				return true
+			}
+		}
+		return false
+	})
+	return filtered
+}
+
 // buildRequestPath builds the request path with path and query parameters
 // for in-process execution (no base URL needed).
 func (e *EchoMCP) buildRequestPath(operation *types.Operation, parameters map[string]any) string {
-	// Replace path parameters
-	finalPath := operation.Path
-	for key, value := range parameters {
-		placeholder := ":" + key
-		if strings.Contains(finalPath, placeholder) {
-			finalPath = strings.ReplaceAll(finalPath, placeholder, fmt.Sprintf("%v", value))
+	// Replace path parameters, escaping their values so they stay within their segment
+	segments := strings.Split(operation.Path, "/")
+	for i, segment := range segments {
+		name, isParam := strings.CutPrefix(segment, ":")
+		value, exists := parameters[name]
+		if !isParam || !exists {
+			continue
+		}
+
+		if slices.Contains(operation.RawPathParams, name) {
+			segments[i] = escapePathSegments(fmt.Sprintf("%v", value))
+		} else {
+			segments[i] = escapePathSegment(fmt.Sprintf("%v", value))
 		}
 	}
 
+	// Replace the catch-all wildcard of the route (its last segment), escaping each segment of
+	// the value but keeping separators
+	if last := len(segments) - 1; convert.IsCatchAll(operation.Path) && strings.Contains(segments[last], "*") {
+		wildcard := ""
+		if value, ok := parameters[convert.WildcardParameter]; ok {
+			wildcard = escapePathSegments(strings.TrimPrefix(fmt.Sprintf("%v", value), "/"))
+		}
+		segments[last] = strings.Replace(segments[last], "*", wildcard, 1)
+	}
+	finalPath := strings.Join(segments, "/")
+
 	// Build query parameters (only include explicit query parameters)
 	queryParams := url.Values{}
 	for key, value := range parameters {
-		if isQueryParameter(operation, key) {
-			queryParams.Add(key, fmt.Sprintf("%v", value))
+		if !isQueryParameter(operation, key) {
+			continue
+		}
+
+		// Arrays are sent as repeated keys (e.g. ?ids=1&ids=2)
+		if values, ok := value.([]any); ok {
+			for _, item := range values {
+				queryParams.Add(key, fmt.Sprintf("%v", item))
+			}
+			continue
 		}
+		queryParams.Add(key, fmt.Sprintf("%v", value))
+	}
+
+	// Static query parameters always win over arguments
+	for key, value := range operation.StaticQuery {
+		queryParams.Set(key, value)
 	}
 
 	if len(queryParams) > 0 {
@@ -600,11 +1663,47 @@ func isBodyMethod(method string) bool {
 }
 
 func isPathParameter(path, paramName string) bool {
+	if paramName == convert.WildcardParameter && convert.IsCatchAll(path) {
+		return true
+	}
 	return strings.Contains(path, ":"+paramName)
 }
 
+// validatePathParameters rejects path parameter values containing control characters,
+// which have no valid place in a request URI
+func validatePathParameters(operation *types.Operation, parameters map[string]any) error {
+	for name, value := range parameters {
+		if !isPathParameter(operation.Path, name) {
+			continue
+		}
+		if strings.ContainsFunc(fmt.Sprintf("%v", value), unicode.IsControl) {
+			return InvalidParams(fmt.Sprintf("path parameter '%s' contains control characters", name), map[string]any{"field": name})
+		}
+	}
+	return nil
+}
+
+// escapePathSegments escapes every segment of a slash-separated path
+func escapePathSegments(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = escapePathSegment(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// escapePathSegment escapes a path parameter value for use as one path segment. Unlike
+// url.PathEscape it also escapes "*", which Echo routes treat as a wildcard.
+func escapePathSegment(value string) string {
+	return strings.ReplaceAll(url.PathEscape(value), "*", "%2A")
+}
+
+// isHeaderParameter reports whether paramName is a header parameter of the operation,
+// comparing canonical header keys since header names are case-insensitive
 func isHeaderParameter(operation *types.Operation, paramName string) bool {
-	return slices.Contains(operation.HeaderParams, paramName)
+	return slices.ContainsFunc(operation.HeaderParams, func(header string) bool {
+		return http.CanonicalHeaderKey(header) == http.CanonicalHeaderKey(paramName)
+	})
 }
 
 func isQueryParameter(operation *types.Operation, paramName string) bool {
@@ -615,6 +1714,122 @@ func isFormDataParameter(operation *types.Operation, paramName string) bool {
 	return slices.Contains(operation.FormDataParams, paramName)
 }
 
+// encodeMultipartForm encodes form fields as a multipart/form-data body, returning the body
+// and its Content-Type with the generated boundary
+func encodeMultipartForm(formData url.Values) ([]byte, string, error) {
+	var buffer bytes.Buffer
+	writer := multipart.NewWriter(&buffer)
+
+	for _, key := range slices.Sorted(maps.Keys(formData)) {
+		for _, value := range formData[key] {
+			if err := writer.WriteField(key, value); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return buffer.Bytes(), writer.FormDataContentType(), nil
+}
+
+// SetHTTPClient sends tool calls through client to the base URL instead of dispatching them
+// in-process. It can be called after construction, e.g. to inject a client instrumented with
+// tracing or retry middleware, and takes precedence over Config.HTTPClient (WithHTTPClient).
+//
+// A nil client uses http.DefaultClient: tool calls still go over the network to the base URL,
+// so nil does not restore in-process dispatch. Without a BaseURL every tool call fails, which
+// is reported by Warnings.
+//
+// Example:
+//
+//	mcp.SetHTTPClient(&http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)})
+func (e *EchoMCP) SetHTTPClient(client *http.Client) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if e.baseURL == "" {
+		e.addWarning("SetHTTPClient requires BaseURL to be set; tool calls will fail without it")
+	}
+
+	e.clientMu.Lock()
+	defer e.clientMu.Unlock()
+	e.client = client
+}
+
+// GetTools returns a copy of the tools currently exposed by the MCP server.
+// The list is populated by Mount and refreshed by RefreshTools, which runs on the first
+// page of every tools/list request.
+func (e *EchoMCP) GetTools() []types.Tool {
+	e.toolsMu.RLock()
+	defer e.toolsMu.RUnlock()
+	return slices.Clone(e.tools)
+}
+
+// unexpectedArguments returns the arguments of a call to a route tool that its input schema
+// does not declare. The _noCache and _idempotencyKey arguments are always accepted.
+func (e *EchoMCP) unexpectedArguments(toolName string, arguments map[string]any) []string {
+	if _, isCustom := e.customToolHandler(toolName); isCustom {
+		return nil
+	}
+
+	name := e.toolPrefix() + toolName
+	for _, tool := range e.GetTools() {
+		if inputSchema, ok := tool.InputSchema.(map[string]any); ok && tool.Name == name {
+			arguments, _ = popNoCache(arguments)
+			arguments, _ = popIdempotencyKey(arguments)
+			return types.UnexpectedArguments(inputSchema, arguments)
+		}
+	}
+	return nil
+}
+
+// GetOperations returns a copy of the operations backing the exposed tools, keyed by tool name.
+func (e *EchoMCP) GetOperations() map[string]types.Operation {
+	e.toolsMu.RLock()
+	defer e.toolsMu.RUnlock()
+	return maps.Clone(e.operations)
+}
+
+// ListRegisteredSchemas returns a copy of the schemas registered with RegisterSchema, Handle and
+// the related helpers, keyed by "METHOD /path" as the routes are registered in Echo.
+func (e *EchoMCP) ListRegisteredSchemas() map[string]types.RegisteredSchemaInfo {
+	e.schemasMu.RLock()
+	defer e.schemasMu.RUnlock()
+	return maps.Clone(e.registeredSchemas)
+}
+
+// handleToolsDebug renders the current tools list as pretty JSON for quick inspection
+func (e *EchoMCP) handleToolsDebug(c echo.Context) error {
+	if _, err := e.RefreshTools(); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSONPretty(http.StatusOK, ToolsListResponse{Tools: e.GetTools()}, "  ")
+}
+
+// Warnings returns the non-fatal problems found while setting up the server,
+// such as a swagger spec that could not be loaded.
+func (e *EchoMCP) Warnings() []string {
+	e.warningsMu.Lock()
+	defer e.warningsMu.Unlock()
+	return slices.Clone(e.warnings)
+}
+
+// addWarning records and logs a non-fatal setup problem
+func (e *EchoMCP) addWarning(warning string) {
+	e.warningsMu.Lock()
+	defer e.warningsMu.Unlock()
+
+	// Warnings raised again on every refresh are only reported once
+	if slices.Contains(e.warnings, warning) {
+		return
+	}
+
+	log.Warn("[MCP] ", warning)
+	e.warnings = append(e.warnings, warning)
+}
+
 // GetServerInfo returns the server information (useful for testing)
 func (e *EchoMCP) GetServerInfo() (string, string, string) {
 	return e.name, e.version, e.description
@@ -1,14 +1,35 @@
 package server
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/swaggo/swag"
 
+	"github.com/BrunoKrugel/echo-mcp/pkg/swagger"
+	"github.com/BrunoKrugel/echo-mcp/pkg/transport"
 	"github.com/BrunoKrugel/echo-mcp/pkg/types"
 )
 
@@ -109,6 +130,61 @@ func TestRegisterSchema(t *testing.T) {
 		assert.Contains(t, mcp.registeredSchemas, "GET /users")
 		assert.Contains(t, mcp.registeredSchemas, "POST /users")
 	})
+
+	t.Run("Should list a copy of the registered schemas", func(t *testing.T) {
+		e := echo.New()
+		mcp := New(e)
+
+		type TestBody struct {
+			Name string `json:"name"`
+		}
+
+		mcp.RegisterSchema("POST", "/users", nil, TestBody{})
+
+		schemas := mcp.ListRegisteredSchemas()
+		require.Contains(t, schemas, "POST /users")
+		assert.Equal(t, TestBody{}, schemas["POST /users"].BodySchema)
+
+		delete(schemas, "POST /users")
+		assert.Contains(t, mcp.ListRegisteredSchemas(), "POST /users")
+	})
+}
+
+func TestRemoveSchema(t *testing.T) {
+	t.Run("Should remove registered schema", func(t *testing.T) {
+		e := echo.New()
+		mcp := New(e)
+
+		mcp.RegisterSchema("GET", "/users", nil, nil)
+		mcp.RegisterSchema("POST", "/users", nil, nil)
+		mcp.RemoveSchema("GET", "/users")
+
+		assert.Len(t, mcp.registeredSchemas, 1)
+		assert.NotContains(t, mcp.registeredSchemas, "GET /users")
+		assert.Contains(t, mcp.registeredSchemas, "POST /users")
+	})
+
+	t.Run("Should rebuild tools when already mounted", func(t *testing.T) {
+		e := echo.New()
+		e.POST("/users", func(c echo.Context) error { return nil })
+
+		type CreateUserRequest struct {
+			Name string `json:"name"`
+		}
+
+		mcp := NewWithConfig(e, &Config{})
+		mcp.RegisterSchema("POST", "/users", nil, CreateUserRequest{})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		properties := mcp.tools[0].InputSchema.(map[string]any)["properties"].(map[string]any)
+		assert.Contains(t, properties, "name")
+
+		mcp.RemoveSchema("POST", "/users")
+
+		properties = mcp.tools[0].InputSchema.(map[string]any)["properties"].(map[string]any)
+		assert.NotContains(t, properties, "name")
+		assert.Contains(t, properties, "body")
+	})
 }
 
 func TestRegisterEndpoints(t *testing.T) {
@@ -155,6 +231,82 @@ func TestExcludeEndpoints(t *testing.T) {
 	})
 }
 
+// routeKeys returns the "METHOD path" keys of routes
+func routeKeys(routes []*echo.Route) []string {
+	var keys []string
+	for _, route := range routes {
+		keys = append(keys, route.Method+" "+route.Path)
+	}
+	return keys
+}
+
+func TestEchoGroupFiltering(t *testing.T) {
+	newEcho := func() *echo.Echo {
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error { return nil })
+
+		admin := e.Group("/admin")
+		admin.GET("/users", func(c echo.Context) error { return nil })
+		admin.POST("/orders", func(c echo.Context) error { return nil })
+
+		return e
+	}
+
+	t.Run("Should exclude group routes with a trailing star pattern", func(t *testing.T) {
+		e := newEcho()
+		mcp := New(e)
+		mcp.ExcludeEndpoints([]string{"/admin/*"})
+
+		assert.ElementsMatch(t, []string{"GET /users"}, routeKeys(mcp.filterRoutes(e.Routes())))
+	})
+
+	t.Run("Should exclude group routes with ExcludeGroups", func(t *testing.T) {
+		e := newEcho()
+		mcp := New(e)
+		mcp.ExcludeGroups("/admin/")
+
+		assert.Equal(t, []string{"/admin/*"}, mcp.excludeEndpoints)
+		assert.ElementsMatch(t, []string{"GET /users"}, routeKeys(mcp.filterRoutes(e.Routes())))
+	})
+}
+
+func TestRemoveEndpoints(t *testing.T) {
+	t.Run("Should remove endpoint from include list", func(t *testing.T) {
+		e := echo.New()
+		mcp := New(e)
+
+		mcp.RegisterEndpoints([]string{"/users", "/orders"})
+		mcp.RemoveEndpointFromInclude("/users")
+
+		assert.Equal(t, []string{"/orders"}, mcp.includeEndpoints)
+	})
+
+	t.Run("Should remove endpoint from exclude list", func(t *testing.T) {
+		e := echo.New()
+		mcp := New(e)
+
+		mcp.ExcludeEndpoints([]string{"/health", "/metrics"})
+		mcp.RemoveEndpointFromExclude("/health")
+
+		assert.Equal(t, []string{"/metrics"}, mcp.excludeEndpoints)
+	})
+
+	t.Run("Should rebuild tools when already mounted", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error { return nil })
+		e.GET("/health", func(c echo.Context) error { return nil })
+
+		mcp := New(e)
+		mcp.ExcludeEndpoints([]string{"/health"})
+		require.NoError(t, mcp.Mount("/mcp"))
+		assert.NotContains(t, mcp.operations, "GET_health")
+
+		mcp.RemoveEndpointFromExclude("/health")
+
+		assert.Contains(t, mcp.operations, "GET_health")
+	})
+}
+
 func TestMount(t *testing.T) {
 	t.Run("Should mount MCP server successfully", func(t *testing.T) {
 		e := echo.New()
@@ -247,6 +399,22 @@ func TestMatchesEndpoint(t *testing.T) {
 		// This is a basic implementation, could be enhanced
 		assert.False(t, mcp.matchesEndpoint("/users/:id", "/users/123"))
 	})
+
+	t.Run("Should match path parameters with any name", func(t *testing.T) {
+		assert.True(t, mcp.matchesEndpoint("/users/:userID", "/users/:id"))
+		assert.True(t, mcp.matchesEndpoint("/users/me", "/users/:id"))
+		assert.True(t, mcp.matchesEndpoint("/users/:userID/orders/:orderID", "/users/:id/orders/:id"))
+		assert.False(t, mcp.matchesEndpoint("/users/:userID/orders", "/users/:id"))
+		assert.False(t, mcp.matchesEndpoint("/users", "/users/:id"))
+	})
+
+	t.Run("Should match wildcard segments", func(t *testing.T) {
+		assert.True(t, mcp.matchesEndpoint("/users/:id/orders", "/users/*"))
+		assert.True(t, mcp.matchesEndpoint("/users/:id/orders", "/users/*/orders"))
+		assert.False(t, mcp.matchesEndpoint("/users/:id/invoices", "/users/*/orders"))
+		assert.True(t, mcp.matchesEndpoint("/api/v1/users", "/api*"))
+		assert.False(t, mcp.matchesEndpoint("/admin", "/admin/*"))
+	})
 }
 
 func TestHandleInitialize(t *testing.T) {
@@ -305,13 +473,83 @@ func TestHandleToolsList(t *testing.T) {
 	})
 }
 
+func TestToolsListPagination(t *testing.T) {
+	newServer := func(t *testing.T, opts ...Option) *EchoMCP {
+		t.Helper()
+
+		e := echo.New()
+		for _, path := range []string{"/a", "/b", "/c"} {
+			e.GET(path, func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+		}
+
+		mcp := NewWithOptions(e, opts...)
+		require.NoError(t, mcp.Mount("/mcp"))
+		return mcp
+	}
+
+	toolNames := func(response ToolsListResponse) []string {
+		var names []string
+		for _, tool := range response.Tools {
+			names = append(names, tool.Name)
+		}
+		return names
+	}
+
+	t.Run("Should list every tool without a page size", func(t *testing.T) {
+		response, err := newServer(t).handleToolsList(map[string]any{})
+		require.NoError(t, err)
+
+		assert.Len(t, response.(ToolsListResponse).Tools, 3)
+		assert.Empty(t, response.(ToolsListResponse).NextCursor)
+	})
+
+	t.Run("Should follow the next cursor to the last page", func(t *testing.T) {
+		mcp := newServer(t, WithToolsPageSize(2))
+
+		first, err := mcp.handleToolsList(map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"GET_a", "GET_b"}, toolNames(first.(ToolsListResponse)))
+		require.NotEmpty(t, first.(ToolsListResponse).NextCursor)
+
+		second, err := mcp.handleToolsList(map[string]any{"cursor": first.(ToolsListResponse).NextCursor})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"GET_c"}, toolNames(second.(ToolsListResponse)))
+		assert.Empty(t, second.(ToolsListResponse).NextCursor)
+	})
+
+	t.Run("Should neither skip nor repeat tools when routes change between pages", func(t *testing.T) {
+		mcp := newServer(t, WithToolsPageSize(2))
+
+		first, err := mcp.handleToolsList(map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"GET_a", "GET_b"}, toolNames(first.(ToolsListResponse)))
+
+		// A route sorted before the cursor appears before the next page is requested
+		mcp.echo.GET("/0", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+		_, err = mcp.RefreshTools()
+		require.NoError(t, err)
+
+		second, err := mcp.handleToolsList(map[string]any{"cursor": first.(ToolsListResponse).NextCursor})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"GET_c"}, toolNames(second.(ToolsListResponse)))
+	})
+
+	t.Run("Should reject invalid cursors", func(t *testing.T) {
+		_, err := newServer(t, WithToolsPageSize(2)).handleToolsList(map[string]any{"cursor": "not a cursor"})
+
+		mcpErr := &types.MCPError{}
+		require.ErrorAs(t, err, &mcpErr)
+		assert.Equal(t, types.ErrCodeInvalidParams, mcpErr.Code)
+	})
+}
+
 func TestHandleToolCall(t *testing.T) {
 	t.Run("Should handle valid tool call", func(t *testing.T) {
 		e := echo.New()
 		mcp := NewWithConfig(e, &Config{BaseURL: "http://localhost:8080"})
 
 		// Mock execute function for testing
-		mcp.executeToolFunc = func(operationID string, parameters map[string]any) (any, error) {
+		mcp.executeToolFunc = func(_ context.Context, operationID string, parameters map[string]any) (any, error) {
 			return map[string]string{"result": "success"}, nil
 		}
 
@@ -320,7 +558,7 @@ func TestHandleToolCall(t *testing.T) {
 			"arguments": map[string]any{"param": "value"},
 		}
 
-		response, err := mcp.handleToolCall(params)
+		response, err := mcp.handleToolCall(context.Background(), params)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, response)
@@ -339,7 +577,7 @@ func TestHandleToolCall(t *testing.T) {
 			"arguments": map[string]any{"param": "value"},
 		}
 
-		response, err := mcp.handleToolCall(params)
+		response, err := mcp.handleToolCall(context.Background(), params)
 
 		assert.Error(t, err)
 		assert.Nil(t, response)
@@ -350,7 +588,7 @@ func TestHandleToolCall(t *testing.T) {
 		e := echo.New()
 		mcp := New(e)
 
-		response, err := mcp.handleToolCall("invalid")
+		response, err := mcp.handleToolCall(context.Background(), "invalid")
 
 		assert.Error(t, err)
 		assert.Nil(t, response)
@@ -418,7 +656,7 @@ func TestDefaultExecuteToolInProcess(t *testing.T) {
 		err := mcp.Mount("/mcp")
 		require.NoError(t, err)
 
-		result, err := mcp.defaultExecuteTool("GET_users_id", map[string]any{"id": "42"})
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_users_id", map[string]any{"id": "42"})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -443,7 +681,7 @@ func TestDefaultExecuteToolInProcess(t *testing.T) {
 		err := mcp.Mount("/mcp")
 		require.NoError(t, err)
 
-		result, err := mcp.defaultExecuteTool("POST_users", map[string]any{
+		result, err := mcp.defaultExecuteTool(context.Background(), "POST_users", map[string]any{
 			"name":  "bob",
 			"email": "bob@example.com",
 		})
@@ -463,7 +701,7 @@ func TestDefaultExecuteToolInProcess(t *testing.T) {
 		err := mcp.Mount("/mcp")
 		require.NoError(t, err)
 
-		_, err = mcp.defaultExecuteTool("UNKNOWN_tool", map[string]any{})
+		_, err = mcp.defaultExecuteTool(context.Background(), "UNKNOWN_tool", map[string]any{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "not found in operations map")
@@ -537,3 +775,4427 @@ func TestFilterRoutes(t *testing.T) {
 		assert.NotContains(t, paths, "/health")
 	})
 }
+
+func TestCookies(t *testing.T) {
+	newCookieServer := func() *echo.Echo {
+		e := echo.New()
+		e.POST("/login", func(c echo.Context) error {
+			c.SetCookie(&http.Cookie{Name: "session", Value: "secret", Path: "/"})
+			return c.JSON(http.StatusOK, map[string]string{"status": "logged in"})
+		})
+		e.GET("/me", func(c echo.Context) error {
+			cookie, err := c.Cookie("session")
+			if err != nil {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"cookie": ""})
+			}
+			return c.JSON(http.StatusOK, map[string]string{"cookie": cookie.Value})
+		})
+		return e
+	}
+
+	t.Run("Should reuse cookies within the same session", func(t *testing.T) {
+		e := newCookieServer()
+		mcp := NewWithConfig(e, &Config{EnableCookieJar: true})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		ctx := transport.WithSessionID(context.Background(), "session-1")
+
+		_, err := mcp.defaultExecuteTool(ctx, "POST_login", map[string]any{})
+		require.NoError(t, err)
+
+		result, err := mcp.defaultExecuteTool(ctx, "GET_me", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, "secret", result.(map[string]any)["cookie"])
+	})
+
+	t.Run("Should not share cookies across sessions", func(t *testing.T) {
+		e := newCookieServer()
+		mcp := NewWithConfig(e, &Config{EnableCookieJar: true})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		_, err := mcp.defaultExecuteTool(transport.WithSessionID(context.Background(), "session-1"), "POST_login", map[string]any{})
+		require.NoError(t, err)
+
+		result, err := mcp.defaultExecuteTool(transport.WithSessionID(context.Background(), "session-2"), "GET_me", map[string]any{})
+		require.NoError(t, err)
+		assert.Empty(t, result.(map[string]any)["cookie"])
+	})
+
+	t.Run("Should drop cookie jar when session closes", func(t *testing.T) {
+		e := newCookieServer()
+		mcp := NewWithConfig(e, &Config{EnableCookieJar: true})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		_, err := mcp.defaultExecuteTool(transport.WithSessionID(context.Background(), "session-1"), "POST_login", map[string]any{})
+		require.NoError(t, err)
+		assert.Contains(t, mcp.cookieJars, "session-1")
+
+		mcp.dropCookieJar("session-1")
+
+		assert.NotContains(t, mcp.cookieJars, "session-1")
+	})
+
+	t.Run("Should forward inbound cookies", func(t *testing.T) {
+		e := newCookieServer()
+		mcp := NewWithConfig(e, &Config{ForwardCookies: true})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		inbound := httptest.NewRequest(http.MethodPost, "/mcp", http.NoBody)
+		inbound.Header.Set("Cookie", "session=forwarded")
+		ctx := transport.WithRequest(context.Background(), inbound)
+
+		result, err := mcp.defaultExecuteTool(ctx, "GET_me", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, "forwarded", result.(map[string]any)["cookie"])
+	})
+
+	t.Run("Should release the cookie jar when the client terminates the session", func(t *testing.T) {
+		e := newCookieServer()
+		mcp := NewWithConfig(e, &Config{EnableCookieJar: true})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		send := func(method, body, sessionID string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(method, "/mcp", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			if sessionID != "" {
+				req.Header.Set("Mcp-Session-Id", sessionID)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			return rec
+		}
+
+		rec := send(http.MethodPost, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`, "")
+		sessionID := rec.Header().Get("Mcp-Session-Id")
+		require.NotEmpty(t, sessionID)
+
+		rec = send(http.MethodPost, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"POST_login","arguments":{}}}`, sessionID)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, mcp.cookieJars, sessionID)
+
+		rec = send(http.MethodDelete, "", sessionID)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.NotContains(t, mcp.cookieJars, sessionID)
+
+		rec = send(http.MethodPost, `{"jsonrpc":"2.0","id":3,"method":"tools/list"}`, sessionID)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestNewWithOptions(t *testing.T) {
+	t.Run("Should apply options to config", func(t *testing.T) {
+		e := echo.New()
+		mcp := NewWithOptions(e,
+			WithName("Test API"),
+			WithVersion("2.0.0"),
+			WithDescription("Test description"),
+			WithBaseURL("http://localhost:8080"),
+			WithSessionTTL(time.Minute),
+		)
+
+		assert.Equal(t, "Test API", mcp.name)
+		assert.Equal(t, "2.0.0", mcp.version)
+		assert.Equal(t, "Test description", mcp.description)
+		assert.Equal(t, "http://localhost:8080", mcp.baseURL)
+		assert.Equal(t, time.Minute, mcp.config.SessionTTL)
+	})
+
+	t.Run("Should seed endpoint filters from options", func(t *testing.T) {
+		e := echo.New()
+		mcp := NewWithOptions(e,
+			WithIncludeEndpoints("/users"),
+			WithExcludeEndpoints("/health", "/metrics"),
+		)
+
+		assert.Equal(t, []string{"/users"}, mcp.includeEndpoints)
+		assert.Equal(t, []string{"/health", "/metrics"}, mcp.excludeEndpoints)
+	})
+
+	t.Run("Should apply options in order", func(t *testing.T) {
+		e := echo.New()
+		mcp := NewWithOptions(e,
+			WithConfig(&Config{Name: "From Config", BaseURL: "http://config"}),
+			WithName("From Option"),
+		)
+
+		assert.Equal(t, "From Option", mcp.name)
+		assert.Equal(t, "http://config", mcp.baseURL)
+	})
+
+	t.Run("Should create instance without options", func(t *testing.T) {
+		e := echo.New()
+		mcp := NewWithOptions(e)
+
+		assert.NotNil(t, mcp.config)
+		assert.NotNil(t, mcp.executeToolFunc)
+	})
+}
+
+func TestRetry(t *testing.T) {
+	newFlakyServer := func(failures int, hits *int) *echo.Echo {
+		e := echo.New()
+		handler := func(c echo.Context) error {
+			*hits++
+			if *hits <= failures {
+				return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "overloaded"})
+			}
+			return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+		}
+		e.GET("/flaky", handler)
+		e.POST("/flaky", handler)
+		return e
+	}
+
+	t.Run("Should retry transient failures until success", func(t *testing.T) {
+		hits := 0
+		e := newFlakyServer(2, &hits)
+		mcp := NewWithConfig(e, &Config{Retry: RetryConfig{MaxAttempts: 3, Backoff: time.Millisecond}})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_flaky", map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, hits)
+		assert.Equal(t, "ok", result.(map[string]any)["status"])
+	})
+
+	t.Run("Should report attempts and the upstream body when retries are exhausted", func(t *testing.T) {
+		hits := 0
+		e := newFlakyServer(5, &hits)
+		mcp := NewWithConfig(e, &Config{Retry: RetryConfig{MaxAttempts: 3, Backoff: time.Millisecond}})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		_, err := mcp.handleToolCall(context.Background(), map[string]any{"name": "GET_flaky"})
+
+		var mcpErr *types.MCPError
+		require.ErrorAs(t, err, &mcpErr)
+		assert.Equal(t, 3, hits)
+		assert.Equal(t, "request failed with status 503 after 3 attempts", mcpErr.Message)
+		assert.Equal(t, map[string]any{
+			"status":   http.StatusServiceUnavailable,
+			"attempts": 3,
+			"body":     map[string]any{"error": "overloaded"},
+		}, mcpErr.Data)
+	})
+
+	t.Run("Should not retry requests that fail to build", func(t *testing.T) {
+		hits := 0
+		e := newFlakyServer(0, &hits)
+		transforms := 0
+		mcp := NewWithConfig(e, &Config{
+			Retry: RetryConfig{MaxAttempts: 3, Backoff: time.Millisecond},
+			RequestTransform: func(req *http.Request) error {
+				transforms++
+				return errors.New("signing key unavailable")
+			},
+		})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		_, err := mcp.defaultExecuteTool(context.Background(), "GET_flaky", map[string]any{})
+
+		require.Error(t, err)
+		assert.Equal(t, 1, transforms)
+		assert.Equal(t, 0, hits)
+		assert.Equal(t, "request transform failed: signing key unavailable", err.Error())
+	})
+
+	t.Run("Should retry transport errors but not canceled requests", func(t *testing.T) {
+		retry := &RetryConfig{}
+
+		assert.True(t, retry.shouldRetry(nil, io.ErrUnexpectedEOF))
+		assert.True(t, retry.shouldRetry(nil, &net.OpError{Op: "dial", Err: errors.New("connection refused")}))
+		assert.False(t, retry.shouldRetry(nil, context.Canceled))
+		assert.False(t, retry.shouldRetry(nil, errors.New("failed to create request")))
+	})
+
+	t.Run("Should not retry POST unless opted in", func(t *testing.T) {
+		hits := 0
+		e := newFlakyServer(2, &hits)
+		mcp := NewWithConfig(e, &Config{Retry: RetryConfig{MaxAttempts: 3, Backoff: time.Millisecond}})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		_, err := mcp.defaultExecuteTool(context.Background(), "POST_flaky", map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, hits)
+	})
+
+	t.Run("Should retry POST when opted in", func(t *testing.T) {
+		hits := 0
+		e := newFlakyServer(2, &hits)
+		mcp := NewWithConfig(e, &Config{Retry: RetryConfig{
+			MaxAttempts: 3,
+			Backoff:     time.Millisecond,
+			Methods:     []string{http.MethodPost},
+		}})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		_, err := mcp.defaultExecuteTool(context.Background(), "POST_flaky", map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, hits)
+	})
+
+	t.Run("Should stop retrying when the deadline would be exceeded", func(t *testing.T) {
+		hits := 0
+		e := newFlakyServer(5, &hits)
+		mcp := NewWithConfig(e, &Config{Retry: RetryConfig{MaxAttempts: 5, Backoff: time.Second}})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_, err := mcp.defaultExecuteTool(ctx, "GET_flaky", map[string]any{})
+
+		require.Error(t, err)
+		assert.Equal(t, 1, hits)
+		assert.Contains(t, err.Error(), "after 1 attempts")
+	})
+}
+
+func TestGetToolsAndOperations(t *testing.T) {
+	t.Run("Should reflect registered schemas and filtering", func(t *testing.T) {
+		e := echo.New()
+		e.POST("/users", func(c echo.Context) error { return nil })
+		e.GET("/health", func(c echo.Context) error { return nil })
+
+		type CreateUserRequest struct {
+			Name string `json:"name"`
+		}
+
+		mcp := NewWithConfig(e, &Config{})
+		mcp.RegisterSchema("POST", "/users", nil, CreateUserRequest{})
+		mcp.ExcludeEndpoints([]string{"/health"})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		tools := mcp.GetTools()
+		require.Len(t, tools, 1)
+		assert.Equal(t, "POST_users", tools[0].Name)
+		properties := tools[0].InputSchema.(map[string]any)["properties"].(map[string]any)
+		assert.Contains(t, properties, "name")
+
+		operations := mcp.GetOperations()
+		assert.Contains(t, operations, "POST_users")
+		assert.NotContains(t, operations, "GET_health")
+	})
+
+	t.Run("Should return copies", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error { return nil })
+
+		mcp := NewWithConfig(e, &Config{})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		tools := mcp.GetTools()
+		tools[0].Name = "changed"
+		operations := mcp.GetOperations()
+		delete(operations, "GET_users")
+
+		assert.Equal(t, "GET_users", mcp.tools[0].Name)
+		assert.Contains(t, mcp.operations, "GET_users")
+	})
+}
+
+func TestToolsDebugEndpoint(t *testing.T) {
+	t.Run("Should render tools as pretty JSON", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error { return nil })
+
+		mcp := NewWithConfig(e, &Config{EnableToolsDebugEndpoint: true})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		req := httptest.NewRequest(http.MethodGet, "/mcp/tools", http.NoBody)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "\n  \"tools\"")
+		assert.Contains(t, rec.Body.String(), "GET_users")
+		assert.NotContains(t, rec.Body.String(), "GET_mcp_tools")
+	})
+
+	t.Run("Should not mount endpoint by default", func(t *testing.T) {
+		e := echo.New()
+		mcp := NewWithConfig(e, &Config{})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		req := httptest.NewRequest(http.MethodGet, "/mcp/tools", http.NoBody)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.NotEqual(t, http.StatusOK, rec.Code)
+	})
+}
+
+func TestAddEchoInstance(t *testing.T) {
+	t.Run("Should expose prefixed tools from additional instances", func(t *testing.T) {
+		public := echo.New()
+		public.GET("/users", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]string{"source": "public"})
+		})
+
+		admin := echo.New()
+		admin.GET("/users", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]string{"source": "admin", "host": c.Request().Host})
+		})
+		admin.GET("/internal/stats", func(c echo.Context) error { return nil })
+
+		mcp := NewWithConfig(public, &Config{})
+		mcp.AddEchoInstance(admin, "http://admin.local:9090", "admin", "/internal/*")
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		operations := mcp.GetOperations()
+		assert.Contains(t, operations, "GET_users")
+		assert.Contains(t, operations, "admin_GET_users")
+		assert.NotContains(t, operations, "admin_GET_internal_stats")
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_users", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, "public", result.(map[string]any)["source"])
+
+		result, err = mcp.defaultExecuteTool(context.Background(), "admin_GET_users", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, "admin", result.(map[string]any)["source"])
+		assert.Equal(t, "admin.local:9090", result.(map[string]any)["host"])
+	})
+
+	t.Run("Should apply shared filters to every instance", func(t *testing.T) {
+		public := echo.New()
+		admin := echo.New()
+		admin.GET("/health", func(c echo.Context) error { return nil })
+		admin.GET("/users", func(c echo.Context) error { return nil })
+
+		mcp := NewWithConfig(public, &Config{})
+		mcp.AddEchoInstance(admin, "", "admin")
+		mcp.ExcludeEndpoints([]string{"/health"})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		operations := mcp.GetOperations()
+		assert.Contains(t, operations, "admin_GET_users")
+		assert.NotContains(t, operations, "admin_GET_health")
+	})
+
+	t.Run("Should not let instances replace tools with the same name", func(t *testing.T) {
+		public := echo.New()
+		public.GET("/users", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]string{"source": "public"})
+		})
+		unprefixed := echo.New()
+		unprefixed.GET("/users", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]string{"source": "unprefixed"})
+		})
+		unprefixed.GET("/orders", func(c echo.Context) error { return nil })
+		first, second := echo.New(), echo.New()
+		first.GET("/stats", func(c echo.Context) error { return nil })
+		second.GET("/stats", func(c echo.Context) error { return nil })
+
+		mcp := NewWithConfig(public, &Config{})
+		mcp.AddEchoInstance(unprefixed, "", "")
+		mcp.AddEchoInstance(first, "", "admin")
+		mcp.AddEchoInstance(second, "", "admin")
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		var names []string
+		for _, tool := range mcp.GetTools() {
+			names = append(names, tool.Name)
+		}
+		assert.Equal(t, []string{"GET_orders", "GET_users", "admin_GET_stats"}, names)
+		assert.Same(t, first, mcp.operationOwners["admin_GET_stats"].echo)
+		assert.Contains(t, mcp.Warnings(), "tool 'GET_users' of the Echo instance with prefix '' conflicts with another tool and is not exposed")
+		assert.Contains(t, mcp.Warnings(), "tool 'admin_GET_stats' of the Echo instance with prefix 'admin' conflicts with another tool and is not exposed")
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_users", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, "public", result.(map[string]any)["source"])
+	})
+
+	t.Run("Should apply the swagger spec to the main instance only", func(t *testing.T) {
+		public := echo.New()
+		public.GET("/users", func(c echo.Context) error { return nil })
+		public.GET("/orders", func(c echo.Context) error { return nil })
+		admin := echo.New()
+		admin.GET("/users", func(c echo.Context) error { return nil })
+		admin.GET("/orders", func(c echo.Context) error { return nil })
+
+		mcp := NewWithOptions(public, WithSkipDeprecated())
+		mcp.swaggerSpec = &swagger.SwaggerSpec{
+			Paths: map[string]swagger.SwaggerPath{
+				"/users":  {"get": swagger.SwaggerOperation{Deprecated: true}},
+				"/orders": {"get": swagger.SwaggerOperation{Extensions: map[string]any{swagger.ExcludeExtension: true}}},
+			},
+		}
+		mcp.AddEchoInstance(admin, "", "admin")
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		assert.Equal(t, []string{"admin_GET_orders", "admin_GET_users"}, slices.Sorted(maps.Keys(mcp.GetOperations())))
+	})
+
+	t.Run("Should share registered schemas between instances", func(t *testing.T) {
+		public := echo.New()
+		admin := echo.New()
+		admin.GET("/users", func(c echo.Context) error { return nil })
+
+		mcp := New(public)
+		mcp.AddEchoInstance(admin, "", "admin")
+		mcp.RegisterSchema(http.MethodGet, "/users", struct {
+			Page int `json:"page" query:"page"`
+		}{}, nil)
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		tools := mcp.GetTools()
+		require.Len(t, tools, 1)
+		assert.Equal(t, "admin_GET_users", tools[0].Name)
+		assert.Contains(t, tools[0].InputSchema.(map[string]any)["properties"], "page")
+	})
+}
+
+func TestRegisterTool(t *testing.T) {
+	sumTool := types.Tool{
+		Name:        "sum",
+		Description: "Add two numbers",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"a": map[string]any{"type": "number"},
+				"b": map[string]any{"type": "number"},
+			},
+		},
+	}
+	sumHandler := func(params map[string]any) (any, error) {
+		a, _ := params["a"].(float64)
+		b, _ := params["b"].(float64)
+		return a + b, nil
+	}
+
+	t.Run("Should list custom tools alongside route tools", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error { return nil })
+
+		mcp := NewWithConfig(e, &Config{})
+		require.NoError(t, mcp.RegisterTool(sumTool, sumHandler))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		response, err := mcp.handleToolsList(nil)
+		require.NoError(t, err)
+
+		names := make([]string, 0)
+		for _, tool := range response.(ToolsListResponse).Tools {
+			names = append(names, tool.Name)
+		}
+		assert.Contains(t, names, "GET_users")
+		assert.Contains(t, names, "sum")
+	})
+
+	t.Run("Should dispatch tool calls to the custom handler", func(t *testing.T) {
+		e := echo.New()
+		mcp := NewWithConfig(e, &Config{})
+		require.NoError(t, mcp.RegisterTool(sumTool, sumHandler))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		response, err := mcp.handleToolCall(context.Background(), map[string]any{
+			"name":      "sum",
+			"arguments": map[string]any{"a": 1.5, "b": 2.0},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "3.5", response.(ToolCallResponse).Content[0].Text)
+	})
+
+	t.Run("Should reject duplicate tool names", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error { return nil })
+
+		mcp := NewWithConfig(e, &Config{})
+		require.NoError(t, mcp.Mount("/mcp"))
+		require.NoError(t, mcp.RegisterTool(sumTool, sumHandler))
+
+		err := mcp.RegisterTool(sumTool, sumHandler)
+		assert.ErrorContains(t, err, "already registered")
+
+		err = mcp.RegisterTool(types.Tool{Name: "GET_users"}, sumHandler)
+		assert.ErrorContains(t, err, "conflicts with a route tool")
+	})
+
+	t.Run("Should expose a single tool when registered before Mount with a route tool name", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error { return c.JSON(http.StatusOK, "route") })
+
+		mcp := NewWithConfig(e, &Config{})
+		require.NoError(t, mcp.RegisterTool(types.Tool{Name: "GET_users"}, func(params map[string]any) (any, error) {
+			return "custom", nil
+		}))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		var names []string
+		for _, tool := range mcp.GetTools() {
+			names = append(names, tool.Name)
+		}
+		assert.Equal(t, []string{"GET_users"}, names)
+		assert.NotContains(t, mcp.GetOperations(), "GET_users")
+		assert.Contains(t, mcp.Warnings(), "custom tool 'GET_users' conflicts with a route tool, which is not exposed")
+
+		result, err := mcp.handleToolCall(context.Background(), map[string]any{"name": "GET_users"})
+		require.NoError(t, err)
+		assert.Equal(t, "custom", result.(ToolCallResponse).Content[0].Text)
+	})
+
+	t.Run("Should require a tool name", func(t *testing.T) {
+		e := echo.New()
+		mcp := NewWithConfig(e, &Config{})
+
+		err := mcp.RegisterTool(types.Tool{}, sumHandler)
+
+		assert.ErrorContains(t, err, "tool name is required")
+	})
+}
+
+func TestUnknownToolSuggestions(t *testing.T) {
+	t.Run("Should suggest the closest tool name", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/users/:id", func(c echo.Context) error { return nil })
+		e.GET("/orders", func(c echo.Context) error { return nil })
+		e.POST("/orders", func(c echo.Context) error { return nil })
+
+		mcp := NewWithConfig(e, &Config{})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		_, err := mcp.defaultExecuteTool(context.Background(), "GET_user_id", map[string]any{})
+
+		var mcpErr *types.MCPError
+		require.ErrorAs(t, err, &mcpErr)
+		assert.Equal(t, types.ErrCodeInvalidParams, mcpErr.Code)
+		assert.Contains(t, mcpErr.Message, "did you mean: GET_users_id?")
+		assert.Contains(t, mcpErr.Message, "(3 tools available)")
+
+		data := mcpErr.Data.(map[string]any)
+		assert.Equal(t, []string{"GET_users_id"}, data["suggestions"])
+		assert.Equal(t, 3, data["toolCount"])
+	})
+
+	t.Run("Should limit suggestions to three", func(t *testing.T) {
+		suggestions := suggestToolNames("GET_user", []string{"GET_users", "GET_usera", "GET_userb", "GET_userc", "POST_orders"})
+
+		assert.Len(t, suggestions, 3)
+		assert.NotContains(t, suggestions, "POST_orders")
+	})
+
+	t.Run("Should compute edit distance", func(t *testing.T) {
+		assert.Equal(t, 0, levenshtein("abc", "abc"))
+		assert.Equal(t, 1, levenshtein("GET_user_id", "GET_users_id"))
+		assert.Equal(t, 3, levenshtein("", "abc"))
+	})
+}
+
+func TestConcurrentToolsListAndCall(t *testing.T) {
+	t.Run("Should handle concurrent tools/list and tools/call", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/users/:id", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]string{"id": c.Param("id")})
+		})
+		e.POST("/users", func(c echo.Context) error {
+			return c.JSON(http.StatusCreated, map[string]string{"status": "created"})
+		})
+
+		mcp := NewWithConfig(e, &Config{})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 50)
+
+		for i := range 50 {
+			wg.Go(func() {
+				var err error
+				switch i % 3 {
+				case 0:
+					_, err = mcp.handleToolsList(nil)
+				case 1:
+					_, err = mcp.handleToolCall(context.Background(), map[string]any{
+						"name":      "GET_users_id",
+						"arguments": map[string]any{"id": "42"},
+					})
+				default:
+					mcp.RegisterSchema("POST", "/users", nil, nil)
+					_ = mcp.GetOperations()
+					_ = mcp.GetTools()
+				}
+				if err != nil {
+					errs <- err
+				}
+			})
+		}
+
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			assert.NoError(t, err)
+		}
+	})
+}
+
+func TestCatchAllExecution(t *testing.T) {
+	t.Run("Should substitute the path argument for the wildcard", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/files/*", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]string{"path": c.Request().URL.Path})
+		})
+
+		mcp := NewWithConfig(e, &Config{})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_files_wildcard", map[string]any{"path": "a/b.txt"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "/files/a/b.txt", result.(map[string]any)["path"])
+	})
+
+	t.Run("Should escape each path segment", func(t *testing.T) {
+		operation := types.Operation{Path: "/files/*", Method: "GET"}
+		mcp := New(echo.New())
+
+		path := mcp.buildRequestPath(&operation, map[string]any{"path": "/my docs/a?.txt"})
+
+		assert.Equal(t, "/files/my%20docs/a%3F.txt", path)
+	})
+
+	t.Run("Should skip catch-all routes when configured", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/files/*", func(c echo.Context) error { return nil })
+		e.GET("/users", func(c echo.Context) error { return nil })
+
+		mcp := NewWithConfig(e, &Config{SkipCatchAllRoutes: true})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		operations := mcp.GetOperations()
+		assert.Contains(t, operations, "GET_users")
+		assert.NotContains(t, operations, "GET_files_wildcard")
+	})
+}
+
+func TestDeterministicTools(t *testing.T) {
+	type routeDef struct {
+		method string
+		path   string
+	}
+
+	routes := []routeDef{
+		{http.MethodGet, "/users"},
+		{http.MethodPost, "/users"},
+		{http.MethodGet, "/users/:id"},
+		{http.MethodDelete, "/users/:id"},
+		{http.MethodGet, "/orders"},
+		{http.MethodHead, "/orders"},
+	}
+
+	newMCP := func(order []int) *EchoMCP {
+		e := echo.New()
+		for _, i := range order {
+			e.Add(routes[i].method, routes[i].path, func(c echo.Context) error { return nil })
+		}
+		mcp := NewWithConfig(e, &Config{})
+		require.NoError(t, mcp.Mount("/mcp"))
+		return mcp
+	}
+
+	t.Run("Should emit identical tools/list regardless of registration order", func(t *testing.T) {
+		first := newMCP([]int{0, 1, 2, 3, 4, 5})
+		second := newMCP([]int{5, 3, 1, 4, 0, 2})
+
+		firstResponse, err := first.handleToolsList(nil)
+		require.NoError(t, err)
+		secondResponse, err := second.handleToolsList(nil)
+		require.NoError(t, err)
+
+		firstJSON, err := json.Marshal(firstResponse)
+		require.NoError(t, err)
+		secondJSON, err := json.Marshal(secondResponse)
+		require.NoError(t, err)
+
+		assert.JSONEq(t, string(firstJSON), string(secondJSON))
+		assert.Equal(t, string(firstJSON), string(secondJSON))
+	})
+
+	t.Run("Should sort tools and drop HEAD duplicates by default", func(t *testing.T) {
+		mcp := newMCP([]int{5, 3, 1, 4, 0, 2})
+
+		names := make([]string, 0)
+		for _, tool := range mcp.GetTools() {
+			names = append(names, tool.Name)
+			assert.Contains(t, mcp.GetOperations(), tool.Name)
+		}
+
+		assert.Equal(t, []string{"DELETE_users_id", "GET_orders", "GET_users", "GET_users_id", "POST_users"}, names)
+	})
+
+	t.Run("Should keep HEAD routes when disabled", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/orders", func(c echo.Context) error { return nil })
+		e.HEAD("/orders", func(c echo.Context) error { return nil })
+
+		mcp := NewWithOptions(e, WithSkipHeadRoutes(false))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		assert.Contains(t, mcp.GetOperations(), "HEAD_orders")
+	})
+}
+
+func TestOperationIDTransformConfig(t *testing.T) {
+	t.Run("Should expose and execute transformed tool names", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+		})
+
+		mcp := NewWithConfig(e, &Config{
+			OperationIDTransform: func(id string) string { return "svc_" + id },
+		})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		assert.Contains(t, mcp.GetOperations(), "svc_GET_users")
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "svc_GET_users", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, "ok", result.(map[string]any)["status"])
+	})
+}
+
+func TestParameterDefaults(t *testing.T) {
+	openAPISchema := `
+openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      parameters:
+        - name: page
+          in: query
+          schema:
+            type: integer
+            default: 1
+        - name: sort
+          in: query
+          schema:
+            type: string
+            example: asc
+            enum: [asc, desc]
+      responses:
+        '200':
+          description: OK
+`
+
+	newServer := func() *EchoMCP {
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]string{
+				"page": c.QueryParam("page"),
+				"sort": c.QueryParam("sort"),
+			})
+		})
+
+		mcp := NewWithConfig(e, &Config{OpenAPISchema: openAPISchema})
+		require.NoError(t, mcp.Mount("/mcp"))
+		return mcp
+	}
+
+	t.Run("Should expose defaults, examples and enums in tools/list", func(t *testing.T) {
+		mcp := newServer()
+
+		tools := mcp.GetTools()
+		require.Len(t, tools, 1)
+
+		properties := tools[0].InputSchema.(map[string]any)["properties"].(map[string]any)
+		page := properties["page"].(map[string]any)
+		assert.Equal(t, 1, page["default"])
+
+		sort := properties["sort"].(map[string]any)
+		assert.Equal(t, []any{"asc", "desc"}, sort["enum"])
+		assert.Equal(t, []any{"asc"}, sort["examples"])
+	})
+
+	t.Run("Should apply defaults for omitted arguments", func(t *testing.T) {
+		mcp := newServer()
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_users", map[string]any{"sort": "desc"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"page": "1", "sort": "desc"}, result)
+	})
+
+	t.Run("Should prefer explicit arguments over defaults", func(t *testing.T) {
+		mcp := newServer()
+
+		parameters := map[string]any{"page": 3}
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_users", parameters)
+		require.NoError(t, err)
+		assert.Equal(t, "3", result.(map[string]any)["page"])
+		assert.Equal(t, map[string]any{"page": 3}, parameters)
+	})
+}
+
+func TestRegisterSchemaFromStruct(t *testing.T) {
+	type UserPatchRequest struct {
+		ID     string `json:"-" param:"id"`
+		Notify string `query:"notify"`
+		Name   string `json:"name" jsonschema:"required"`
+	}
+
+	t.Run("Should expose query and body fields and route them on execution", func(t *testing.T) {
+		e := echo.New()
+		e.PATCH("/users/:id", func(c echo.Context) error {
+			var body map[string]any
+			if err := json.NewDecoder(c.Request().Body).Decode(&body); err != nil {
+				return err
+			}
+			return c.JSON(http.StatusOK, map[string]any{
+				"id":     c.Param("id"),
+				"notify": c.QueryParam("notify"),
+				"body":   body,
+			})
+		})
+
+		mcp := New(e)
+		mcp.RegisterSchemaFromStruct("PATCH", "/users/:id", UserPatchRequest{})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		tools := mcp.GetTools()
+		require.Len(t, tools, 1)
+		schema := tools[0].InputSchema.(map[string]any)
+		properties := schema["properties"].(map[string]any)
+		assert.Contains(t, properties, "id")
+		assert.Contains(t, properties, "notify")
+		assert.Contains(t, properties, "name")
+		assert.ElementsMatch(t, []string{"id", "name"}, schema["required"])
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "PATCH_users_id", map[string]any{
+			"id":     "42",
+			"notify": "true",
+			"name":   "Jane",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"id":     "42",
+			"notify": "true",
+			"body":   map[string]any{"name": "Jane"},
+		}, result)
+	})
+}
+
+func TestDeprecatedOperations(t *testing.T) {
+	openAPISchema := `
+openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      description: List users
+      deprecated: true
+      responses:
+        '200':
+          description: OK
+  /accounts:
+    get:
+      description: List accounts
+      responses:
+        '200':
+          description: OK
+`
+
+	newEcho := func() *echo.Echo {
+		e := echo.New()
+		handler := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+		e.GET("/users", handler)
+		e.GET("/accounts", handler)
+		e.GET("/health", handler)
+		return e
+	}
+
+	t.Run("Should mark deprecated operations by default", func(t *testing.T) {
+		mcp := NewWithConfig(newEcho(), &Config{OpenAPISchema: openAPISchema})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		tools := make(map[string]types.Tool)
+		for _, tool := range mcp.GetTools() {
+			tools[tool.Name] = tool
+		}
+		require.Len(t, tools, 3)
+
+		assert.Equal(t, "[DEPRECATED] List users", tools["GET_users"].Description)
+		assert.Equal(t, true, tools["GET_users"].Annotations["deprecated"])
+		assert.True(t, tools["GET_users"].Deprecated)
+
+		assert.Equal(t, "List accounts", tools["GET_accounts"].Description)
+		assert.Nil(t, tools["GET_accounts"].Annotations)
+		assert.False(t, tools["GET_accounts"].Deprecated)
+		assert.Nil(t, tools["GET_health"].Annotations)
+	})
+
+	t.Run("Should skip deprecated operations when configured", func(t *testing.T) {
+		mcp := NewWithOptions(newEcho(), WithOpenAPISchema(openAPISchema), WithSkipDeprecated())
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		operations := mcp.GetOperations()
+		assert.NotContains(t, operations, "GET_users")
+		assert.Contains(t, operations, "GET_accounts")
+		assert.Contains(t, operations, "GET_health")
+	})
+
+	t.Run("Should hide deprecated tools from tools/list when configured", func(t *testing.T) {
+		mcp := NewWithOptions(newEcho(), WithOpenAPISchema(openAPISchema), WithExcludeDeprecated())
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.handleToolsList(nil)
+		require.NoError(t, err)
+
+		var names []string
+		for _, tool := range result.(ToolsListResponse).Tools {
+			names = append(names, tool.Name)
+		}
+		assert.ElementsMatch(t, []string{"GET_accounts", "GET_health"}, names)
+		assert.Contains(t, mcp.GetOperations(), "GET_users")
+	})
+}
+
+func TestMaxResponseBodyBytes(t *testing.T) {
+	newEcho := func() *echo.Echo {
+		e := echo.New()
+		e.GET("/text", func(c echo.Context) error {
+			return c.String(http.StatusOK, strings.Repeat("a", 100))
+		})
+		e.GET("/json", func(c echo.Context) error {
+			return c.JSONBlob(http.StatusOK, []byte(`{"status":"ok"}   `))
+		})
+		return e
+	}
+
+	t.Run("Should append a marker to truncated text responses", func(t *testing.T) {
+		mcp := NewWithOptions(newEcho(), WithMaxResponseBodyBytes(10))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_text", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, strings.Repeat("a", 10)+"\n[response truncated]", result)
+	})
+
+	t.Run("Should flag truncated JSON objects", func(t *testing.T) {
+		mcp := NewWithOptions(newEcho(), WithMaxResponseBodyBytes(16))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_json", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "ok", "truncated": true}, result)
+	})
+
+	t.Run("Should return full responses within the limit", func(t *testing.T) {
+		mcp := NewWithOptions(newEcho(), WithMaxResponseBodyBytes(100))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_text", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, strings.Repeat("a", 100), result)
+	})
+
+	t.Run("Should not limit responses by default", func(t *testing.T) {
+		mcp := New(newEcho())
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_text", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, strings.Repeat("a", 100), result)
+	})
+}
+
+func TestRequestHeaders(t *testing.T) {
+	newEcho := func() *echo.Echo {
+		e := echo.New()
+		e.GET("/whoami", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]string{
+				"authorization": c.Request().Header.Get("Authorization"),
+				"service":       c.Request().Header.Get("X-Service"),
+			})
+		})
+		return e
+	}
+	headers := map[string]string{
+		"Authorization": "Bearer static-token",
+		"X-Service":     "mcp-proxy",
+	}
+
+	t.Run("Should send static headers on every call", func(t *testing.T) {
+		mcp := NewWithOptions(newEcho(), WithRequestHeaders(headers))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_whoami", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"authorization": "Bearer static-token",
+			"service":       "mcp-proxy",
+		}, result)
+	})
+
+	t.Run("Should let operation header parameters take precedence", func(t *testing.T) {
+		mcp := NewWithOptions(newEcho(), WithRequestHeaders(headers))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		mcp.toolsMu.Lock()
+		operation := mcp.operations["GET_whoami"]
+		operation.HeaderParams = []string{"Authorization"}
+		mcp.operations["GET_whoami"] = operation
+		mcp.toolsMu.Unlock()
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_whoami", map[string]any{
+			"Authorization": "Bearer user-token",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer user-token", result.(map[string]any)["authorization"])
+		assert.Equal(t, "mcp-proxy", result.(map[string]any)["service"])
+	})
+}
+
+func TestRequestTransform(t *testing.T) {
+	newEcho := func() *echo.Echo {
+		e := echo.New()
+		e.GET("/whoami", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]string{
+				"authorization": c.Request().Header.Get("Authorization"),
+				"signature":     c.Request().Header.Get("X-Signature"),
+			})
+		})
+		return e
+	}
+
+	t.Run("Should let the transform rewrite the request", func(t *testing.T) {
+		mcp := NewWithOptions(newEcho(),
+			WithRequestHeaders(map[string]string{"Authorization": "Bearer static-token"}),
+			WithRequestTransform(func(req *http.Request) error {
+				req.Header.Set("X-Signature", "signed:"+req.Method+" "+req.URL.Path)
+				req.Header.Set("Authorization", "Bearer fresh-token")
+				return nil
+			}),
+		)
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_whoami", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"authorization": "Bearer fresh-token",
+			"signature":     "signed:GET /whoami",
+		}, result)
+	})
+
+	t.Run("Should abort the call with an internal error", func(t *testing.T) {
+		e := newEcho()
+		mcp := NewWithOptions(e, WithRequestTransform(func(req *http.Request) error {
+			return errors.New("signing key unavailable")
+		}))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		body := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"GET_whoami","arguments":{}}}`
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		var response types.MCPMessage
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.NotNil(t, response.Error)
+		assert.Equal(t, types.ErrCodeInternalError, response.Error.Code)
+		assert.Contains(t, response.Error.Message, "request transform failed: signing key unavailable")
+	})
+}
+
+func TestDescriptionTemplateConfig(t *testing.T) {
+	t.Run("Should render fallback descriptions from the template", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/users/:id", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+		mcp := NewWithOptions(e, WithDescriptionTemplate("Call {{.Method}} {{.Path}}"))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		tools := mcp.GetTools()
+		require.Len(t, tools, 1)
+		assert.Equal(t, "Call GET /users/:id", tools[0].Description)
+	})
+
+	t.Run("Should fail to mount with an invalid template", func(t *testing.T) {
+		mcp := NewWithOptions(echo.New(), WithDescriptionTemplate("{{.Method"))
+
+		err := mcp.Mount("/mcp")
+		assert.ErrorContains(t, err, "invalid configuration")
+	})
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestHTTPClientConfig(t *testing.T) {
+	t.Run("Should send tool calls through the custom client", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/users/:id", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]string{"id": c.Param("id"), "token": c.Request().Header.Get("X-Token")})
+		})
+		upstream := httptest.NewServer(e)
+		defer upstream.Close()
+
+		var requested []string
+		client := &http.Client{
+			Timeout: 5 * time.Second,
+			Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				requested = append(requested, req.URL.String())
+				req = req.Clone(req.Context())
+				req.Header.Set("X-Token", "spy")
+				return http.DefaultTransport.RoundTrip(req)
+			}),
+		}
+
+		mcp := NewWithOptions(e, WithBaseURL(upstream.URL+"/"), WithHTTPClient(client))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_users_id", map[string]any{"id": "42"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"id": "42", "token": "spy"}, result)
+		assert.Equal(t, []string{upstream.URL + "/users/42"}, requested)
+	})
+
+	t.Run("Should return client errors", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+		client := &http.Client{
+			Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				return nil, errors.New("connection refused")
+			}),
+		}
+
+		mcp := NewWithOptions(e, WithBaseURL("http://upstream.invalid"), WithHTTPClient(client))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		_, err := mcp.defaultExecuteTool(context.Background(), "GET_users", map[string]any{})
+		assert.ErrorContains(t, err, "connection refused")
+	})
+
+	t.Run("Should require a base URL", func(t *testing.T) {
+		mcp := NewWithOptions(echo.New(), WithHTTPClient(http.DefaultClient))
+
+		assert.ErrorContains(t, mcp.Mount("/mcp"), "BaseURL")
+	})
+
+	t.Run("Should send tool calls through a client set after construction", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+		var requested []string
+		client := &http.Client{
+			Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				requested = append(requested, req.URL.String())
+				return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(`{"ok":true}`))}, nil
+			}),
+		}
+
+		mcp := NewWithOptions(e, WithBaseURL("http://upstream.invalid"))
+		require.NoError(t, mcp.Mount("/mcp"))
+		mcp.SetHTTPClient(client)
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_users", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"ok": true}, result)
+		assert.Equal(t, []string{"http://upstream.invalid/users"}, requested)
+	})
+
+	t.Run("Should fall back to the default client when set to nil", func(t *testing.T) {
+		mcp := NewWithOptions(echo.New(), WithBaseURL("http://upstream.invalid"))
+		mcp.SetHTTPClient(nil)
+
+		assert.Same(t, http.DefaultClient, mcp.client)
+	})
+}
+
+func TestRedirectPolicy(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<html>login</html>"))
+	}))
+	defer other.Close()
+
+	e := echo.New()
+	e.GET("/start", func(c echo.Context) error { return c.Redirect(http.StatusFound, "/users") })
+	e.GET("/away", func(c echo.Context) error { return c.Redirect(http.StatusFound, other.URL+"/login") })
+	e.GET("/users", func(c echo.Context) error { return c.JSON(http.StatusOK, []string{"jane"}) })
+	upstream := httptest.NewServer(e)
+	defer upstream.Close()
+
+	newServer := func(t *testing.T, opts ...Option) *EchoMCP {
+		t.Helper()
+		mcp := NewWithOptions(e, append([]Option{WithBaseURL(upstream.URL), WithHTTPClient(upstream.Client())}, opts...)...)
+		require.NoError(t, mcp.Mount("/mcp"))
+		return mcp
+	}
+
+	t.Run("Should follow redirects and list them by default", func(t *testing.T) {
+		result, err := newServer(t).defaultExecuteTool(context.Background(), "GET_start", map[string]any{})
+		require.NoError(t, err)
+
+		withRedirects := result.(*ResponseWithHeaders)
+		assert.Equal(t, []any{"jane"}, withRedirects.Body)
+		assert.Equal(t, []string{upstream.URL + "/users"}, withRedirects.Redirects)
+		assert.Contains(t, withRedirects.String(), "Redirects:\n"+upstream.URL+"/users")
+	})
+
+	t.Run("Should return the redirect without following it", func(t *testing.T) {
+		result, err := newServer(t, WithRedirectPolicy(RedirectNone)).defaultExecuteTool(context.Background(), "GET_start", map[string]any{})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{
+			"status":     http.StatusFound,
+			"headers":    map[string]any{"Location": "/users", "Content-Length": "0"},
+			"redirected": true,
+		}, result)
+	})
+
+	t.Run("Should only follow redirects to the same host", func(t *testing.T) {
+		mcp := newServer(t, WithRedirectPolicy(RedirectSameHost))
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_start", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, []any{"jane"}, result.(*ResponseWithHeaders).Body)
+
+		result, err = mcp.defaultExecuteTool(context.Background(), "GET_away", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, true, result.(map[string]any)["redirected"])
+		assert.Equal(t, other.URL+"/login", result.(map[string]any)["headers"].(map[string]any)["Location"])
+	})
+}
+
+func TestPathParameterEncoding(t *testing.T) {
+	newServer := func(t *testing.T) *EchoMCP {
+		t.Helper()
+
+		e := echo.New()
+		e.GET("/files/:name", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]string{"name": c.Param("name"), "uri": c.Request().RequestURI})
+		})
+		e.GET("/files/:dir/:file", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]string{"dir": c.Param("dir"), "file": c.Param("file")})
+		})
+
+		mcp := New(e)
+		require.NoError(t, mcp.Mount("/mcp"))
+		return mcp
+	}
+
+	t.Run("Should escape unicode and spaces", func(t *testing.T) {
+		result, err := newServer(t).defaultExecuteTool(context.Background(), "GET_files_name", map[string]any{"name": "héllo wörld"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "/files/h%C3%A9llo%20w%C3%B6rld", result.(map[string]any)["uri"])
+	})
+
+	t.Run("Should keep slashes within the parameter", func(t *testing.T) {
+		result, err := newServer(t).defaultExecuteTool(context.Background(), "GET_files_name", map[string]any{"name": "a/b"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "/files/a%2Fb", result.(map[string]any)["uri"])
+	})
+
+	t.Run("Should escape wildcards in parameter values", func(t *testing.T) {
+		result, err := newServer(t).defaultExecuteTool(context.Background(), "GET_files_name", map[string]any{"name": "a*b"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "/files/a%2Ab", result.(map[string]any)["uri"])
+	})
+
+	t.Run("Should only replace the wildcard segment of catch-all routes", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/buckets/:bucket/*", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]string{"uri": c.Request().RequestURI})
+		})
+		mcp := New(e)
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_buckets_bucket_wildcard", map[string]any{
+			"bucket": "x*y", "path": "docs/a.txt",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "/buckets/x%2Ay/docs/a.txt", result.(map[string]any)["uri"])
+	})
+
+	t.Run("Should send slashes of raw path parameters as they are", func(t *testing.T) {
+		mcp := newServer(t)
+		mcp.RegisterRawPathParam(http.MethodGet, "/files/:name", "name")
+		require.NoError(t, mcp.setupServer())
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_files_name", map[string]any{"name": "a/b"})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{"dir": "a", "file": "b"}, result)
+	})
+
+	t.Run("Should keep raw path parameters registered before the schema", func(t *testing.T) {
+		mcp := newServer(t)
+		mcp.RegisterRawPathParam(http.MethodGet, "/files/:name", "name")
+		mcp.RegisterSchema(http.MethodGet, "/files/:name", struct {
+			Version int `query:"version"`
+		}{}, nil)
+		require.NoError(t, mcp.setupServer())
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_files_name", map[string]any{"name": "a/b"})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{"dir": "a", "file": "b"}, result)
+	})
+
+	t.Run("Should reject control characters", func(t *testing.T) {
+		_, err := newServer(t).defaultExecuteTool(context.Background(), "GET_files_name", map[string]any{"name": "a\nb"})
+
+		var toolErr *ToolError
+		require.ErrorAs(t, err, &toolErr)
+		assert.Equal(t, types.ErrCodeInvalidParams, toolErr.Code)
+	})
+}
+
+func TestArrayQueryParameterExecution(t *testing.T) {
+	t.Run("Should send array values as repeated query keys", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]any{
+				"ids":   c.QueryParams()["ids"],
+				"query": c.QueryString(),
+			})
+		})
+
+		mcp := New(e)
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		mcp.toolsMu.Lock()
+		operation := mcp.operations["GET_users"]
+		operation.QueryParams = []string{"ids"}
+		mcp.operations["GET_users"] = operation
+		mcp.toolsMu.Unlock()
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_users", map[string]any{
+			"ids": []any{float64(1), float64(2)},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []any{"1", "2"}, result.(map[string]any)["ids"])
+		assert.Equal(t, "ids=1&ids=2", result.(map[string]any)["query"])
+	})
+}
+
+// fakeSwaggerDoc is a swaggo document whose contents can be changed by tests
+type fakeSwaggerDoc struct {
+	mu  sync.Mutex
+	doc string
+}
+
+func (f *fakeSwaggerDoc) ReadDoc() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.doc
+}
+
+func (f *fakeSwaggerDoc) set(doc string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.doc = doc
+}
+
+var (
+	fakeSwagger         = &fakeSwaggerDoc{}
+	registerFakeSwagger sync.Once
+)
+
+// useCorruptSwagger registers a malformed swaggo document for the duration of the test
+func useCorruptSwagger(t *testing.T) {
+	t.Helper()
+	registerFakeSwagger.Do(func() { swag.Register(swag.Name, fakeSwagger) })
+	fakeSwagger.set(`{"swagger": "2.0", "paths": {`)
+	swagger.ResetSwaggerCache()
+	t.Cleanup(func() {
+		fakeSwagger.set("")
+		swagger.ResetSwaggerCache()
+	})
+}
+
+func TestSwaggerLoadErrors(t *testing.T) {
+	newEcho := func() *echo.Echo {
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+		return e
+	}
+
+	t.Run("Should record a warning and fall back to inferred schemas", func(t *testing.T) {
+		useCorruptSwagger(t)
+
+		mcp := NewWithOptions(newEcho(), WithSwaggerSchemas())
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		warnings := mcp.Warnings()
+		require.Len(t, warnings, 1)
+		assert.Contains(t, warnings[0], "failed to load swagger spec")
+		assert.Len(t, mcp.GetTools(), 1)
+	})
+
+	t.Run("Should report a corrupt spec loaded by New", func(t *testing.T) {
+		useCorruptSwagger(t)
+
+		mcp := New(newEcho())
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		assert.Len(t, mcp.Warnings(), 1)
+	})
+
+	t.Run("Should fail to mount in strict mode", func(t *testing.T) {
+		useCorruptSwagger(t)
+
+		mcp := NewWithOptions(newEcho(), WithSwaggerSchemas(), WithStrictSwagger())
+
+		err := mcp.Mount("/mcp")
+		assert.ErrorContains(t, err, "failed to load swagger spec")
+	})
+
+	t.Run("Should report an invalid OpenAPISchema", func(t *testing.T) {
+		mcp := NewWithOptions(newEcho(), WithOpenAPISchema("paths: ["))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		warnings := mcp.Warnings()
+		require.Len(t, warnings, 1)
+		assert.Contains(t, warnings[0], "failed to parse OpenAPISchema")
+	})
+
+	t.Run("Should not warn when no swagger documentation is registered", func(t *testing.T) {
+		mcp := New(newEcho())
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		assert.Empty(t, mcp.Warnings())
+	})
+}
+
+func TestToolTimeouts(t *testing.T) {
+	newEcho := func() *echo.Echo {
+		e := echo.New()
+		e.GET("/reports", func(c echo.Context) error {
+			select {
+			case <-c.Request().Context().Done():
+				return c.Request().Context().Err()
+			case <-time.After(200 * time.Millisecond):
+				return c.JSON(http.StatusOK, map[string]string{"status": "ready"})
+			}
+		})
+		return e
+	}
+
+	openAPISchema := `
+openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /reports:
+    get:
+      x-mcp-timeout: 20ms
+      responses:
+        '200':
+          description: OK
+`
+
+	t.Run("Should fail calls exceeding a registered timeout", func(t *testing.T) {
+		mcp := New(newEcho())
+		mcp.RegisterToolTimeout("GET", "/reports", 20*time.Millisecond)
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		_, err := mcp.defaultExecuteTool(context.Background(), "GET_reports", map[string]any{})
+		assert.ErrorContains(t, err, "timed out after 20ms")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Should apply the swagger x-mcp-timeout extension", func(t *testing.T) {
+		mcp := NewWithOptions(newEcho(), WithOpenAPISchema(openAPISchema))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		_, err := mcp.defaultExecuteTool(context.Background(), "GET_reports", map[string]any{})
+		assert.ErrorContains(t, err, "timed out after 20ms")
+	})
+
+	t.Run("Should let registered timeouts override swagger", func(t *testing.T) {
+		mcp := NewWithOptions(newEcho(), WithOpenAPISchema(openAPISchema))
+		mcp.RegisterToolTimeout("GET", "/reports", time.Second)
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_reports", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, "ready", result.(map[string]any)["status"])
+	})
+}
+
+func TestProgressNotifications(t *testing.T) {
+	newServer := func() *echo.Echo {
+		e := echo.New()
+		e.GET("/reports", func(c echo.Context) error {
+			time.Sleep(100 * time.Millisecond)
+			return c.JSON(http.StatusOK, map[string]string{"status": "ready"})
+		})
+
+		mcp := NewWithOptions(e, WithProgressInterval(20*time.Millisecond))
+		require.NoError(t, mcp.Mount("/mcp"))
+		return e
+	}
+
+	call := func(e *echo.Echo, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("Should echo the progress token while a call is running", func(t *testing.T) {
+		rec := call(newServer(), `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"GET_reports","arguments":{},"_meta":{"progressToken":"report-1"}}}`)
+
+		assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+		body := rec.Body.String()
+		assert.Contains(t, body, `"method":"notifications/progress"`)
+		assert.Contains(t, body, `"progressToken":"report-1"`)
+		assert.Contains(t, body, `ready`)
+	})
+
+	t.Run("Should not send progress without a token", func(t *testing.T) {
+		rec := call(newServer(), `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"GET_reports","arguments":{}}}`)
+
+		assert.Contains(t, rec.Header().Get("Content-Type"), "application/json")
+		assert.NotContains(t, rec.Body.String(), "notifications/progress")
+	})
+}
+
+func TestResponseHeaders(t *testing.T) {
+	newEcho := func() *echo.Echo {
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error {
+			c.Response().Header().Set("X-Total-Count", "42")
+			c.Response().Header().Set("X-Request-Id", "abc")
+			return c.JSON(http.StatusOK, []string{"jane"})
+		})
+		e.POST("/users", func(c echo.Context) error {
+			c.Response().Header().Set(echo.HeaderLocation, "/users/7")
+			return c.JSON(http.StatusCreated, map[string]string{"status": "created"})
+		})
+		return e
+	}
+
+	t.Run("Should return listed headers as structured content", func(t *testing.T) {
+		mcp := NewWithOptions(newEcho(), WithIncludeResponseHeaders("x-total-*"))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.handleToolCall(context.Background(), map[string]any{"name": "GET_users", "arguments": map[string]any{}})
+		require.NoError(t, err)
+
+		response := result.(ToolCallResponse)
+		withHeaders := response.StructuredContent.(*ResponseWithHeaders)
+		assert.Equal(t, []any{"jane"}, withHeaders.Body)
+		assert.Equal(t, map[string]string{"X-Total-Count": "42"}, withHeaders.Headers)
+		assert.Contains(t, response.Content[0].Text, "X-Total-Count: 42")
+		assert.NotContains(t, response.Content[0].Text, "X-Request-Id")
+	})
+
+	t.Run("Should include Location on 201 responses", func(t *testing.T) {
+		mcp := NewWithOptions(newEcho(), WithIncludeResponseHeaders("X-Total-Count"))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "POST_users", map[string]any{})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]string{"Location": "/users/7"}, result.(*ResponseWithHeaders).Headers)
+	})
+
+	t.Run("Should hide headers without a list", func(t *testing.T) {
+		mcp := New(newEcho())
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "POST_users", map[string]any{})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{"status": "created"}, result)
+	})
+
+	t.Run("Should let routes override the list", func(t *testing.T) {
+		mcp := NewWithOptions(newEcho(), WithIncludeResponseHeaders("X-Total-Count"))
+		mcp.SetResponseHeaders(http.MethodGet, "/users", "X-Request-Id")
+		mcp.SetResponseHeaders(http.MethodPost, "/users")
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_users", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"X-Request-Id": "abc"}, result.(*ResponseWithHeaders).Headers)
+
+		result, err = mcp.defaultExecuteTool(context.Background(), "POST_users", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "created"}, result)
+	})
+
+	t.Run("Should keep the list of a route registered before its schema", func(t *testing.T) {
+		mcp := NewWithOptions(newEcho(), WithIncludeResponseHeaders("X-Total-Count"))
+		mcp.SetResponseHeaders(http.MethodGet, "/users", "X-Request-Id")
+		mcp.RegisterSchema(http.MethodGet, "/users", struct {
+			Page int `query:"page"`
+		}{}, nil)
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_users", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"X-Request-Id": "abc"}, result.(*ResponseWithHeaders).Headers)
+	})
+}
+
+func TestDryRun(t *testing.T) {
+	newEcho := func(calls *int) *echo.Echo {
+		e := echo.New()
+		e.POST("/users", func(c echo.Context) error {
+			*calls++
+			return c.JSON(http.StatusCreated, map[string]string{"status": "created"})
+		})
+		e.GET("/users", func(c echo.Context) error {
+			*calls++
+			return c.JSON(http.StatusOK, map[string]string{"status": "listed"})
+		})
+		return e
+	}
+
+	t.Run("Should preview non-GET calls without executing them", func(t *testing.T) {
+		var calls int
+		mcp := NewWithOptions(newEcho(&calls), WithDryRun(), WithBaseURL("http://localhost:8080"))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.handleToolCall(context.Background(), map[string]any{
+			"name":      "POST_users",
+			"arguments": map[string]any{"name": "Jane"},
+		})
+		require.NoError(t, err)
+		assert.Zero(t, calls)
+
+		response := result.(ToolCallResponse)
+		preview := response.StructuredContent.(*DryRunPreview)
+		assert.True(t, preview.DryRun)
+		assert.Equal(t, http.MethodPost, preview.Method)
+		assert.Equal(t, "http://localhost:8080/users", preview.URL)
+		assert.Equal(t, map[string]any{"name": "Jane"}, preview.Body)
+		assert.Equal(t, "application/json", preview.Headers["Content-Type"])
+		assert.Contains(t, response.Content[0].Text, `"dryRun":true`)
+	})
+
+	t.Run("Should still execute GET calls", func(t *testing.T) {
+		var calls int
+		mcp := NewWithOptions(newEcho(&calls), WithDryRun())
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_users", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, "listed", result.(map[string]any)["status"])
+	})
+
+	t.Run("Should let the request header override the config", func(t *testing.T) {
+		var calls int
+		mcp := New(newEcho(&calls))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		inbound := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		inbound.Header.Set(DryRunHeader, "true")
+		ctx := transport.WithRequest(context.Background(), inbound)
+
+		result, err := mcp.defaultExecuteTool(ctx, "POST_users", map[string]any{})
+		require.NoError(t, err)
+		assert.Zero(t, calls)
+		assert.IsType(t, &DryRunPreview{}, result)
+
+		inbound.Header.Set(DryRunHeader, "false")
+		_, err = mcp.defaultExecuteTool(ctx, "POST_users", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Should redact credentials in the preview headers", func(t *testing.T) {
+		var calls int
+		mcp := NewWithOptions(newEcho(&calls), WithDryRun(),
+			WithRequestHeaders(map[string]string{"Authorization": "Bearer secret-token", "X-Tenant": "acme"}))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.handleToolCall(context.Background(), map[string]any{
+			"name":      "POST_users",
+			"arguments": map[string]any{"name": "Jane"},
+		})
+		require.NoError(t, err)
+
+		response := result.(ToolCallResponse)
+		preview := response.StructuredContent.(*DryRunPreview)
+		assert.Equal(t, "[REDACTED]", preview.Headers["Authorization"])
+		assert.Equal(t, "acme", preview.Headers["X-Tenant"])
+		assert.NotContains(t, response.Content[0].Text, "secret-token")
+	})
+
+	t.Run("Should redact API keys and CSRF tokens in the preview headers", func(t *testing.T) {
+		var calls int
+		mcp := NewWithOptions(newEcho(&calls), WithDryRun(), WithRequestHeaders(map[string]string{
+			"X-Api-Key":    "key-secret",
+			"X-Csrf-Token": "csrf-secret",
+		}))
+		mcp.swaggerSpec = &swagger.SwaggerSpec{
+			SecurityDefinitions: map[string]*swagger.SwaggerSecurityScheme{
+				"ApiKey": {Type: "apiKey", Name: "X-API-Key", In: "header"},
+			},
+		}
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.handleToolCall(context.Background(), map[string]any{"name": "POST_users"})
+		require.NoError(t, err)
+
+		response := result.(ToolCallResponse)
+		preview := response.StructuredContent.(*DryRunPreview)
+		assert.Equal(t, "[REDACTED]", preview.Headers["X-Api-Key"])
+		assert.Equal(t, "[REDACTED]", preview.Headers["X-Csrf-Token"])
+		assert.NotContains(t, response.Content[0].Text, "key-secret")
+		assert.NotContains(t, response.Content[0].Text, "csrf-secret")
+	})
+}
+
+func TestGzipResponses(t *testing.T) {
+	t.Run("Should decompress gzip-encoded responses", func(t *testing.T) {
+		e := echo.New()
+		e.Use(middleware.Gzip())
+		e.GET("/users", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]string{"name": "Jane"})
+		})
+
+		mcp := NewWithOptions(e, WithRequestHeaders(map[string]string{
+			echo.HeaderAcceptEncoding: "gzip",
+		}))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_users", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"name": "Jane"}, result)
+	})
+
+	t.Run("Should report a corrupt gzip body", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error {
+			c.Response().Header().Set(echo.HeaderContentEncoding, "gzip")
+			return c.String(http.StatusOK, "not gzip")
+		})
+
+		mcp := New(e)
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		_, err := mcp.defaultExecuteTool(context.Background(), "GET_users", map[string]any{})
+		assert.ErrorContains(t, err, "failed to decode response body")
+	})
+}
+
+func TestClone(t *testing.T) {
+	newEcho := func() *echo.Echo {
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error { return c.String(http.StatusOK, "users") })
+		e.POST("/orders", func(c echo.Context) error { return c.String(http.StatusCreated, "created") })
+		return e
+	}
+
+	t.Run("Should share the echo instance and copy the configuration", func(t *testing.T) {
+		e := newEcho()
+		original := NewWithOptions(e, WithName("API"), WithExcludeEndpoints("/health"))
+		original.RegisterSchema(http.MethodPost, "/orders", nil, map[string]any{"type": "object"})
+
+		clone := original.Clone()
+
+		assert.Same(t, e, clone.echo)
+		assert.Equal(t, "API", clone.name)
+		assert.NotSame(t, original.config, clone.config)
+		assert.Equal(t, original.excludeEndpoints, clone.excludeEndpoints)
+		assert.Contains(t, clone.registeredSchemas, "POST /orders")
+	})
+
+	t.Run("Should deep-copy the configuration slices and maps", func(t *testing.T) {
+		original := NewWithOptions(newEcho(),
+			WithCORSOrigins("https://app.example.com"),
+			WithRequestHeaders(map[string]string{"X-Tenant": "acme"}),
+			WithIncludeResponseHeaders("X-Total-Count"),
+		)
+
+		clone := original.Clone()
+		clone.config.CORSOrigins[0] = "https://evil.example.com"
+		clone.config.RequestHeaders["X-Tenant"] = "other"
+		clone.config.IncludeResponseHeaders[0] = "X-Other"
+
+		assert.Equal(t, []string{"https://app.example.com"}, original.config.CORSOrigins)
+		assert.Equal(t, "acme", original.config.RequestHeaders["X-Tenant"])
+		assert.Equal(t, []string{"X-Total-Count"}, original.config.IncludeResponseHeaders)
+	})
+
+	t.Run("Should configure and mount the clone independently", func(t *testing.T) {
+		e := newEcho()
+		original := New(e)
+		readOnly := original.Clone()
+		readOnly.ExcludeEndpoints([]string{"/orders", "/mcp"})
+
+		require.NoError(t, original.Mount("/mcp"))
+		require.NoError(t, readOnly.Mount("/read-only"))
+
+		assert.Contains(t, original.operations, "POST_orders")
+		assert.NotContains(t, readOnly.operations, "POST_orders")
+		assert.Contains(t, readOnly.operations, "GET_users")
+		assert.Empty(t, original.excludeEndpoints)
+
+		result, err := readOnly.defaultExecuteTool(context.Background(), "GET_users", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, "users", result)
+	})
+}
+
+func TestToolErrors(t *testing.T) {
+	newServer := func(handler ToolHandler) *echo.Echo {
+		e := echo.New()
+		e.GET("/slow", func(c echo.Context) error {
+			time.Sleep(200 * time.Millisecond)
+			return c.NoContent(http.StatusOK)
+		})
+
+		mcp := New(e)
+		mcp.RegisterToolTimeout(http.MethodGet, "/slow", 20*time.Millisecond)
+		require.NoError(t, mcp.RegisterTool(types.Tool{Name: "lookup"}, handler))
+		require.NoError(t, mcp.Mount("/mcp"))
+		return e
+	}
+
+	call := func(e *echo.Echo, tool string) *types.MCPError {
+		body := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"` + tool + `","arguments":{}}}`
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		var response types.MCPMessage
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.NotNil(t, response.Error)
+		return response.Error
+	}
+
+	t.Run("Should surface InvalidParams code and data", func(t *testing.T) {
+		e := newServer(func(map[string]any) (any, error) {
+			return nil, InvalidParams("id is required", map[string]any{"field": "id"})
+		})
+
+		mcpErr := call(e, "lookup")
+		assert.Equal(t, types.ErrCodeInvalidParams, mcpErr.Code)
+		assert.Equal(t, "id is required", mcpErr.Message)
+		assert.Equal(t, map[string]any{"field": "id"}, mcpErr.Data)
+	})
+
+	t.Run("Should surface NotFound code", func(t *testing.T) {
+		e := newServer(func(map[string]any) (any, error) {
+			return nil, fmt.Errorf("lookup failed: %w", NotFound("user not found"))
+		})
+
+		mcpErr := call(e, "lookup")
+		assert.Equal(t, types.ErrCodeNotFound, mcpErr.Code)
+		assert.Equal(t, "user not found", mcpErr.Message)
+		assert.Nil(t, mcpErr.Data)
+	})
+
+	t.Run("Should surface Internal code and data", func(t *testing.T) {
+		e := newServer(func(map[string]any) (any, error) {
+			return nil, Internal("database unavailable", map[string]any{"retryable": true})
+		})
+
+		mcpErr := call(e, "lookup")
+		assert.Equal(t, types.ErrCodeInternalError, mcpErr.Code)
+		assert.Equal(t, map[string]any{"retryable": true}, mcpErr.Data)
+	})
+
+	t.Run("Should keep plain errors as internal errors", func(t *testing.T) {
+		e := newServer(func(map[string]any) (any, error) {
+			return nil, errors.New("boom")
+		})
+
+		mcpErr := call(e, "lookup")
+		assert.Equal(t, types.ErrCodeInternalError, mcpErr.Code)
+		assert.Equal(t, "boom", mcpErr.Message)
+		assert.Nil(t, mcpErr.Data)
+	})
+
+	t.Run("Should flag timeouts in the error data", func(t *testing.T) {
+		e := newServer(func(map[string]any) (any, error) { return nil, nil })
+
+		mcpErr := call(e, "GET_slow")
+		assert.Equal(t, types.ErrCodeInternalError, mcpErr.Code)
+		assert.Equal(t, map[string]any{"code": TimeoutErrorCode}, mcpErr.Data)
+	})
+}
+
+func TestTypedSchemaRegistration(t *testing.T) {
+	type UserQuery struct {
+		Page int `form:"page"`
+	}
+
+	type CreateUserRequest struct {
+		Name string `json:"name" jsonschema:"required"`
+	}
+
+	t.Run("Should register query and body schemas from type parameters", func(t *testing.T) {
+		mcp := New(echo.New())
+
+		RegisterQuery[UserQuery](mcp, http.MethodPost, "/users")
+		RegisterBody[CreateUserRequest](mcp, http.MethodPost, "/users")
+
+		info := mcp.registeredSchemas["POST /users"]
+		assert.Equal(t, UserQuery{}, info.QuerySchema)
+		assert.Equal(t, CreateUserRequest{}, info.BodySchema)
+	})
+
+	t.Run("Should expose generic registrations in the tool schema", func(t *testing.T) {
+		e := echo.New()
+		e.POST("/users", func(c echo.Context) error { return nil })
+
+		mcp := New(e)
+		RegisterBody[CreateUserRequest](mcp, http.MethodPost, "/users")
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		tools := mcp.GetTools()
+		require.Len(t, tools, 1)
+		properties := tools[0].InputSchema.(map[string]any)["properties"].(map[string]any)
+		assert.Contains(t, properties, "name")
+	})
+
+	t.Run("Should register schemas for an existing route", func(t *testing.T) {
+		e := echo.New()
+		route := e.POST("/users", func(c echo.Context) error { return nil })
+
+		mcp := New(e)
+		require.NoError(t, mcp.RegisterSchemaForRoute(route, nil, CreateUserRequest{}))
+
+		assert.Equal(t, CreateUserRequest{}, mcp.registeredSchemas["POST /users"].BodySchema)
+	})
+
+	t.Run("Should reject routes that are not registered", func(t *testing.T) {
+		e := echo.New()
+		route := echo.New().POST("/orders", func(c echo.Context) error { return nil })
+
+		mcp := New(e)
+
+		assert.EqualError(t, mcp.RegisterSchemaForRoute(route, nil, CreateUserRequest{}), "route POST /orders is not registered")
+		assert.Error(t, mcp.RegisterSchemaForRoute(nil, nil, nil))
+		assert.Empty(t, mcp.registeredSchemas)
+	})
+}
+
+func TestDiscoveryEndpoint(t *testing.T) {
+	t.Run("Should return the initialize response to GET requests", func(t *testing.T) {
+		e := echo.New()
+		mcp := NewWithOptions(e, WithName("Users API"), WithVersion("2.1.0"))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		req := httptest.NewRequest(http.MethodGet, "/mcp", http.NoBody)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get("Content-Type"), "application/json")
+		assert.Empty(t, rec.Header().Get("Mcp-Session-Id"))
+		assert.JSONEq(t, `{"serverInfo":{"name":"Users API","version":"2.1.0"},"protocolVersion":"2024-11-05","capabilities":{"tools":{},"logging":{},"completions":{}}}`, rec.Body.String())
+	})
+
+	t.Run("Should reject WebSocket upgrade requests", func(t *testing.T) {
+		e := echo.New()
+		mcp := New(e)
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		req := httptest.NewRequest(http.MethodGet, "/mcp", http.NoBody)
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
+
+func TestExcludeHTTPMethods(t *testing.T) {
+	newEcho := func() *echo.Echo {
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error { return nil })
+		e.OPTIONS("/users", func(c echo.Context) error { return nil })
+		e.TRACE("/users", func(c echo.Context) error { return nil })
+		e.HEAD("/status", func(c echo.Context) error { return nil })
+		return e
+	}
+
+	t.Run("Should exclude OPTIONS, HEAD and TRACE routes by default", func(t *testing.T) {
+		mcp := New(newEcho())
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		assert.Equal(t, []string{"GET_users"}, slices.Sorted(maps.Keys(mcp.GetOperations())))
+	})
+
+	t.Run("Should use the configured methods instead of the defaults", func(t *testing.T) {
+		mcp := NewWithOptions(newEcho(), WithExcludeHTTPMethods("trace"))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		assert.Equal(t, []string{"GET_users", "HEAD_status", "OPTIONS_users"}, slices.Sorted(maps.Keys(mcp.GetOperations())))
+	})
+
+	t.Run("Should expose every method when no methods are excluded", func(t *testing.T) {
+		mcp := NewWithOptions(newEcho(), WithExcludeHTTPMethods())
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		assert.Len(t, mcp.GetOperations(), 4)
+	})
+}
+
+func TestHandle(t *testing.T) {
+	type UserQuery struct {
+		Page int `form:"page" json:"page"`
+	}
+
+	type CreateUserRequest struct {
+		Name string `json:"name" jsonschema:"required"`
+	}
+
+	t.Run("Should register the route and its tool schema", func(t *testing.T) {
+		e := echo.New()
+		mcp := New(e)
+
+		route := mcp.Handle(http.MethodPost, "/users", func(c echo.Context) error {
+			var body CreateUserRequest
+			if err := c.Bind(&body); err != nil {
+				return err
+			}
+			return c.JSON(http.StatusCreated, map[string]string{"name": body.Name, "page": c.QueryParam("page")})
+		},
+			WithQuery(UserQuery{}),
+			WithBody(CreateUserRequest{}),
+			WithRouteDescription("Create a user"),
+		)
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		assert.Equal(t, http.MethodPost, route.Method)
+		assert.Contains(t, routeKeys(e.Routes()), "POST /users")
+
+		tools := mcp.GetTools()
+		require.Len(t, tools, 1)
+		assert.Equal(t, "Create a user", tools[0].Description)
+
+		schema := tools[0].InputSchema.(map[string]any)
+		properties := schema["properties"].(map[string]any)
+		assert.Contains(t, properties, "page")
+		assert.Contains(t, properties, "name")
+		assert.Contains(t, schema["required"], "name")
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "POST_users", map[string]any{"name": "Jane", "page": 2})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"name": "Jane", "page": "2"}, result)
+	})
+
+	t.Run("Should expose tags to the description template", func(t *testing.T) {
+		e := echo.New()
+		mcp := NewWithOptions(e, WithDescriptionTemplate("{{.Method}} {{.Path}} [{{range .Tags}}{{.}}{{end}}]"))
+		mcp.Handle(http.MethodGet, "/users", func(c echo.Context) error { return nil }, WithTags("users"))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		tools := mcp.GetTools()
+		require.Len(t, tools, 1)
+		assert.Equal(t, "GET /users [users]", tools[0].Description)
+	})
+
+	t.Run("Should rebuild tools when already mounted", func(t *testing.T) {
+		e := echo.New()
+		mcp := New(e)
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		mcp.Handle(http.MethodGet, "/orders", func(c echo.Context) error { return nil })
+
+		assert.Contains(t, mcp.GetOperations(), "GET_orders")
+	})
+}
+
+func TestRegisterHeaders(t *testing.T) {
+	type TenantHeaders struct {
+		TenantID string `header:"X-Tenant-Id" jsonschema:"required,description=Tenant identifier"`
+		Ignored  string `json:"ignored"`
+	}
+
+	newEcho := func() *echo.Echo {
+		e := echo.New()
+		e.POST("/users", func(c echo.Context) error {
+			var body map[string]any
+			if err := json.NewDecoder(c.Request().Body).Decode(&body); err != nil {
+				body = nil
+			}
+			return c.JSON(http.StatusOK, map[string]any{
+				"tenant": c.Request().Header.Get("X-Tenant-Id"),
+				"body":   body,
+			})
+		})
+		return e
+	}
+
+	t.Run("Should add registered headers to the tool schema and operation", func(t *testing.T) {
+		mcp := New(newEcho())
+		mcp.RegisterHeaders(http.MethodPost, "/users", TenantHeaders{})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		tools := mcp.GetTools()
+		require.Len(t, tools, 1)
+		schema := tools[0].InputSchema.(map[string]any)
+		properties := schema["properties"].(map[string]any)
+		assert.Equal(t, "Tenant identifier", properties["X-Tenant-Id"].(map[string]any)["description"])
+		assert.NotContains(t, properties, "ignored")
+		assert.Contains(t, schema["required"], "X-Tenant-Id")
+
+		assert.Equal(t, []string{"X-Tenant-Id"}, mcp.GetOperations()["POST_users"].HeaderParams)
+	})
+
+	t.Run("Should send lowercase arguments as canonical headers", func(t *testing.T) {
+		mcp := New(newEcho())
+		mcp.RegisterHeaders(http.MethodPost, "/users", TenantHeaders{})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "POST_users", map[string]any{
+			"x-tenant-id": "acme",
+			"name":        "Jane",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"tenant": "acme",
+			"body":   map[string]any{"name": "Jane"},
+		}, result)
+	})
+
+	t.Run("Should keep registered query and body schemas", func(t *testing.T) {
+		mcp := New(newEcho())
+		mcp.RegisterSchema(http.MethodPost, "/users", nil, map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"name": map[string]any{"type": "string"}},
+		})
+		mcp.RegisterHeaders(http.MethodPost, "/users", TenantHeaders{})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		properties := mcp.GetTools()[0].InputSchema.(map[string]any)["properties"].(map[string]any)
+		assert.Contains(t, properties, "name")
+		assert.Contains(t, properties, "X-Tenant-Id")
+	})
+}
+
+func TestCaseInsensitiveHeaderParameters(t *testing.T) {
+	t.Run("Should send arguments differing in case from the swagger header as headers", func(t *testing.T) {
+		e := echo.New()
+		e.POST("/users", func(c echo.Context) error {
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				return err
+			}
+			return c.JSON(http.StatusOK, map[string]any{
+				"requestID": c.Request().Header.Get("X-Request-ID"),
+				"body":      string(body),
+			})
+		})
+
+		mcp := New(e)
+		mcp.swaggerSpec = &swagger.SwaggerSpec{
+			Paths: map[string]swagger.SwaggerPath{
+				"/users": {
+					"post": swagger.SwaggerOperation{
+						Parameters: []swagger.SwaggerParameter{
+							{Name: "X-Request-ID", In: "header", Type: "string"},
+						},
+					},
+				},
+			},
+		}
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "POST_users", map[string]any{
+			"x-request-id": "req-42",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"requestID": "req-42", "body": ""}, result)
+	})
+}
+
+func TestDebugProxy(t *testing.T) {
+	captureLogs := func(t *testing.T) *bytes.Buffer {
+		var buf bytes.Buffer
+		log.SetOutput(&buf)
+		t.Cleanup(func() { log.SetOutput(os.Stderr) })
+		return &buf
+	}
+
+	t.Run("Should log the proxied request with credentials redacted", func(t *testing.T) {
+		logs := captureLogs(t)
+
+		e := echo.New()
+		e.POST("/users", func(c echo.Context) error {
+			return c.JSON(http.StatusCreated, map[string]string{"status": "created"})
+		})
+
+		mcp := NewWithOptions(e, WithDebugProxy(), WithRequestHeaders(map[string]string{
+			"Authorization": "Bearer top-secret",
+		}))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "POST_users", map[string]any{"name": "Jane"})
+		require.NoError(t, err)
+		assert.Equal(t, "created", result.(map[string]any)["status"])
+
+		output := logs.String()
+		assert.Contains(t, output, "POST")
+		assert.Contains(t, output, "/users")
+		assert.Contains(t, output, "Jane")
+		assert.Contains(t, output, "201")
+		assert.Contains(t, output, "[REDACTED]")
+		assert.NotContains(t, output, "top-secret")
+	})
+
+	t.Run("Should attach the request to the error data of failed calls", func(t *testing.T) {
+		logs := captureLogs(t)
+
+		client := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		})}
+
+		mcp := NewWithOptions(echo.New(),
+			WithDebugProxy(),
+			WithHTTPClient(client),
+			WithBaseURL("http://api.local"),
+			WithRequestHeaders(map[string]string{"Authorization": "Bearer top-secret"}),
+		)
+		mcp.echo.GET("/users", func(c echo.Context) error { return nil })
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		_, err := mcp.handleToolCall(context.Background(), map[string]any{"name": "GET_users"})
+
+		var mcpErr *types.MCPError
+		require.ErrorAs(t, err, &mcpErr)
+		assert.Contains(t, mcpErr.Message, "connection refused")
+
+		exchange := mcpErr.Data.(map[string]any)["request"].(ProxyExchange)
+		assert.Equal(t, http.MethodGet, exchange.Method)
+		assert.Equal(t, "http://api.local/users", exchange.URL)
+		assert.Equal(t, "[REDACTED]", exchange.Headers["Authorization"])
+		assert.NotContains(t, logs.String(), "top-secret")
+	})
+
+	t.Run("Should attach the request to the result of non-2xx responses", func(t *testing.T) {
+		captureLogs(t)
+
+		e := echo.New()
+		e.GET("/users/:id", func(c echo.Context) error {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "user not found"})
+		})
+		e.GET("/users", func(c echo.Context) error { return c.JSON(http.StatusOK, []string{}) })
+
+		mcp := NewWithOptions(e, WithDebugProxy(), WithRequestHeaders(map[string]string{
+			"Authorization": "Bearer top-secret",
+		}))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_users_id", map[string]any{"id": "42"})
+		require.NoError(t, err)
+
+		response, ok := result.(*ResponseWithHeaders)
+		require.True(t, ok)
+		assert.Equal(t, map[string]any{"error": "user not found"}, response.Body)
+		require.NotNil(t, response.Request)
+		assert.Equal(t, http.MethodGet, response.Request.Method)
+		assert.Equal(t, "/users/42", response.Request.URL)
+		assert.Equal(t, http.StatusNotFound, response.Request.Status)
+		assert.Equal(t, "[REDACTED]", response.Request.Headers["Authorization"])
+
+		result, err = mcp.defaultExecuteTool(context.Background(), "GET_users", map[string]any{})
+		require.NoError(t, err)
+		assert.IsType(t, []any{}, result)
+	})
+
+	t.Run("Should attach the request to the error data when retries are exhausted", func(t *testing.T) {
+		captureLogs(t)
+
+		e := echo.New()
+		e.GET("/flaky", func(c echo.Context) error {
+			return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "overloaded"})
+		})
+
+		mcp := NewWithConfig(e, &Config{
+			DebugProxy: true,
+			Retry:      RetryConfig{MaxAttempts: 2, Backoff: time.Millisecond},
+		})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		_, err := mcp.handleToolCall(context.Background(), map[string]any{"name": "GET_flaky"})
+
+		var mcpErr *types.MCPError
+		require.ErrorAs(t, err, &mcpErr)
+
+		exchange := mcpErr.Data.(map[string]any)["request"].(ProxyExchange)
+		assert.Equal(t, "/flaky", exchange.URL)
+		assert.Equal(t, http.StatusServiceUnavailable, exchange.Status)
+	})
+
+	t.Run("Should redact CSRF tokens and the headers of apiKey security schemes", func(t *testing.T) {
+		logs := captureLogs(t)
+
+		client := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		})}
+
+		mcp := NewWithOptions(echo.New(),
+			WithDebugProxy(),
+			WithHTTPClient(client),
+			WithBaseURL("http://api.local"),
+			WithRequestHeaders(map[string]string{
+				"X-Api-Key":    "key-secret",
+				"X-Csrf-Token": "csrf-secret",
+				"X-Tenant":     "acme",
+			}),
+		)
+		mcp.swaggerSpec = &swagger.SwaggerSpec{
+			SecurityDefinitions: map[string]*swagger.SwaggerSecurityScheme{
+				"ApiKey": {Type: "apiKey", Name: "X-API-Key", In: "header"},
+			},
+		}
+		mcp.echo.GET("/users", func(c echo.Context) error { return nil })
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		_, err := mcp.handleToolCall(context.Background(), map[string]any{"name": "GET_users"})
+
+		var mcpErr *types.MCPError
+		require.ErrorAs(t, err, &mcpErr)
+
+		exchange := mcpErr.Data.(map[string]any)["request"].(ProxyExchange)
+		assert.Equal(t, "[REDACTED]", exchange.Headers["X-Api-Key"])
+		assert.Equal(t, "[REDACTED]", exchange.Headers["X-Csrf-Token"])
+		assert.Equal(t, "acme", exchange.Headers["X-Tenant"])
+
+		output := logs.String()
+		assert.NotContains(t, output, "key-secret")
+		assert.NotContains(t, output, "csrf-secret")
+	})
+
+	t.Run("Should not log by default", func(t *testing.T) {
+		logs := captureLogs(t)
+
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+		mcp := New(e)
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		_, err := mcp.defaultExecuteTool(context.Background(), "GET_users", map[string]any{})
+		require.NoError(t, err)
+		assert.NotContains(t, logs.String(), "Proxied request")
+	})
+}
+
+func TestTagFiltering(t *testing.T) {
+	spec := &swagger.SwaggerSpec{
+		Paths: map[string]swagger.SwaggerPath{
+			"/users": {
+				"get": swagger.SwaggerOperation{Tags: []string{"users"}},
+			},
+			"/orders": {
+				"get": swagger.SwaggerOperation{Tags: []string{"orders", "admin"}},
+			},
+		},
+	}
+
+	newEcho := func() *echo.Echo {
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error { return nil })
+		e.GET("/orders", func(c echo.Context) error { return nil })
+		e.GET("/health", func(c echo.Context) error { return nil })
+		return e
+	}
+
+	t.Run("Should expose only the routes of the given tags", func(t *testing.T) {
+		mcp := NewForTags(newEcho(), []string{"orders"})
+		mcp.swaggerSpec = spec
+
+		assert.True(t, mcp.config.EnableSwaggerSchemas)
+		assert.Equal(t, []string{"orders"}, mcp.config.IncludeTags)
+
+		require.NoError(t, mcp.Mount("/mcp"))
+		assert.Equal(t, []string{"GET_orders"}, slices.Sorted(maps.Keys(mcp.GetOperations())))
+	})
+
+	t.Run("Should drop routes with excluded tags", func(t *testing.T) {
+		mcp := NewWithOptions(newEcho(), WithExcludeTags("admin"))
+		mcp.swaggerSpec = spec
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		assert.Equal(t, []string{"GET_health", "GET_users"}, slices.Sorted(maps.Keys(mcp.GetOperations())))
+	})
+
+	t.Run("Should use tags registered with Handle", func(t *testing.T) {
+		e := echo.New()
+		mcp := NewWithOptions(e, WithIncludeTags("users"))
+		mcp.Handle(http.MethodGet, "/users", func(c echo.Context) error { return nil }, WithTags("users"))
+		mcp.Handle(http.MethodGet, "/orders", func(c echo.Context) error { return nil })
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		assert.Equal(t, []string{"GET_users"}, slices.Sorted(maps.Keys(mcp.GetOperations())))
+	})
+}
+
+func TestShutdown(t *testing.T) {
+	newServer := func(started chan<- struct{}, release <-chan struct{}) *EchoMCP {
+		e := echo.New()
+		e.GET("/reports", func(c echo.Context) error {
+			close(started)
+			<-release
+			return c.JSON(http.StatusOK, map[string]string{"status": "ready"})
+		})
+
+		mcp := New(e)
+		require.NoError(t, mcp.Mount("/mcp"))
+		return mcp
+	}
+
+	t.Run("Should wait for in-flight calls before closing", func(t *testing.T) {
+		started, release := make(chan struct{}), make(chan struct{})
+		mcp := newServer(started, release)
+
+		callDone := make(chan error, 1)
+		go func() {
+			_, err := mcp.handleToolCall(context.Background(), map[string]any{"name": "GET_reports"})
+			callDone <- err
+		}()
+		<-started
+
+		shutdownDone := make(chan error, 1)
+		go func() { shutdownDone <- mcp.Shutdown(context.Background()) }()
+
+		select {
+		case <-shutdownDone:
+			t.Fatal("Shutdown returned before the in-flight call finished")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(release)
+		require.NoError(t, <-callDone)
+		require.NoError(t, <-shutdownDone)
+
+		_, err := mcp.handleToolCall(context.Background(), map[string]any{"name": "GET_reports"})
+		assert.EqualError(t, err, "server is shutting down")
+	})
+
+	t.Run("Should return the context error when calls do not drain in time", func(t *testing.T) {
+		started, release := make(chan struct{}), make(chan struct{})
+		defer close(release)
+		mcp := newServer(started, release)
+
+		go func() {
+			_, _ = mcp.handleToolCall(context.Background(), map[string]any{"name": "GET_reports"})
+		}()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, mcp.Shutdown(ctx), context.DeadlineExceeded)
+	})
+
+	t.Run("Should clear sessions on shutdown", func(t *testing.T) {
+		e := echo.New()
+		mcp := New(e)
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		var closed []string
+		mcp.transport.OnSessionClosed(func(sessionID string) { closed = append(closed, sessionID) })
+
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		sessionID := rec.Header().Get("Mcp-Session-Id")
+		require.NotEmpty(t, sessionID)
+
+		require.NoError(t, mcp.Shutdown(context.Background()))
+
+		assert.Equal(t, []string{sessionID}, closed)
+	})
+}
+
+func TestHealthCheck(t *testing.T) {
+	t.Run("Should report a healthy server in-process", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/health", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+		mcp := New(e)
+
+		ok, err := mcp.HealthCheck()
+
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("Should report an unhealthy status", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/health", func(c echo.Context) error { return c.NoContent(http.StatusServiceUnavailable) })
+		mcp := New(e)
+
+		ok, err := mcp.HealthCheck()
+
+		assert.False(t, ok)
+		assert.ErrorContains(t, err, "status 503")
+	})
+
+	t.Run("Should probe the configured path over HTTP", func(t *testing.T) {
+		upstream := echo.New()
+		upstream.GET("/status", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+		srv := httptest.NewServer(upstream)
+		defer srv.Close()
+
+		mcp := NewWithOptions(echo.New(),
+			WithBaseURL(srv.URL),
+			WithHTTPClient(srv.Client()),
+			WithHealthCheckPath("/status"),
+		)
+
+		ok, err := mcp.HealthCheck()
+
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("Should fail Mount when the startup health check fails", func(t *testing.T) {
+		mcp := NewWithOptions(echo.New(), WithStartupHealthCheck())
+
+		err := mcp.Mount("/mcp")
+
+		assert.ErrorContains(t, err, "startup health check failed")
+	})
+}
+
+func TestResponseCache(t *testing.T) {
+	newServer := func(t *testing.T, ttl time.Duration, handler echo.HandlerFunc) (*EchoMCP, *int) {
+		t.Helper()
+
+		calls := 0
+		e := echo.New()
+		e.GET("/users/:id", func(c echo.Context) error {
+			calls++
+			return handler(c)
+		})
+		e.POST("/users", func(c echo.Context) error {
+			calls++
+			return c.JSON(http.StatusCreated, map[string]string{"id": "1"})
+		})
+
+		mcp := NewWithOptions(e, WithCacheTTL(ttl))
+		require.NoError(t, mcp.Mount("/mcp"))
+		return mcp, &calls
+	}
+	ok := func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"id": c.Param("id")})
+	}
+	call := func(t *testing.T, mcp *EchoMCP, name string, arguments map[string]any) {
+		t.Helper()
+		_, err := mcp.handleToolCall(context.Background(), map[string]any{"name": name, "arguments": arguments})
+		require.NoError(t, err)
+	}
+
+	t.Run("Should serve identical GET calls from the cache", func(t *testing.T) {
+		mcp, calls := newServer(t, time.Minute, ok)
+
+		call(t, mcp, "GET_users_id", map[string]any{"id": "1"})
+		call(t, mcp, "GET_users_id", map[string]any{"id": "1"})
+		call(t, mcp, "GET_users_id", map[string]any{"id": "2"})
+
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("Should expire entries after the TTL", func(t *testing.T) {
+		mcp, calls := newServer(t, 20*time.Millisecond, ok)
+
+		call(t, mcp, "GET_users_id", map[string]any{"id": "1"})
+		time.Sleep(40 * time.Millisecond)
+		call(t, mcp, "GET_users_id", map[string]any{"id": "1"})
+
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("Should bypass the cache with _noCache", func(t *testing.T) {
+		mcp, calls := newServer(t, time.Minute, ok)
+
+		call(t, mcp, "GET_users_id", map[string]any{"id": "1"})
+		call(t, mcp, "GET_users_id", map[string]any{"id": "1", "_noCache": true})
+
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("Should not cache non-2xx responses", func(t *testing.T) {
+		mcp, calls := newServer(t, time.Minute, func(c echo.Context) error {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "not found"})
+		})
+
+		call(t, mcp, "GET_users_id", map[string]any{"id": "1"})
+		call(t, mcp, "GET_users_id", map[string]any{"id": "1"})
+
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("Should honor Cache-Control no-store", func(t *testing.T) {
+		mcp, calls := newServer(t, time.Minute, func(c echo.Context) error {
+			c.Response().Header().Set("Cache-Control", "no-store")
+			return ok(c)
+		})
+
+		call(t, mcp, "GET_users_id", map[string]any{"id": "1"})
+		call(t, mcp, "GET_users_id", map[string]any{"id": "1"})
+
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("Should not cache non-GET operations", func(t *testing.T) {
+		mcp, calls := newServer(t, time.Minute, ok)
+
+		call(t, mcp, "POST_users", nil)
+		call(t, mcp, "POST_users", nil)
+
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("Should drop cached results on Invalidate", func(t *testing.T) {
+		mcp, calls := newServer(t, time.Minute, ok)
+
+		call(t, mcp, "GET_users_id", map[string]any{"id": "1"})
+		mcp.Invalidate("GET_users_id")
+		call(t, mcp, "GET_users_id", map[string]any{"id": "1"})
+
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("Should evict the oldest entry when full", func(t *testing.T) {
+		mcp, calls := newServer(t, time.Minute, ok)
+		mcp.config.CacheMaxEntries = 1
+
+		call(t, mcp, "GET_users_id", map[string]any{"id": "1"})
+		call(t, mcp, "GET_users_id", map[string]any{"id": "2"})
+		call(t, mcp, "GET_users_id", map[string]any{"id": "1"})
+
+		assert.Equal(t, 3, *calls)
+	})
+}
+
+func TestIdempotencyKeys(t *testing.T) {
+	newServer := func(t *testing.T, opts ...Option) (*EchoMCP, *atomic.Int32, *[]string) {
+		t.Helper()
+
+		var calls atomic.Int32
+		var received []string
+		e := echo.New()
+		e.POST("/orders", func(c echo.Context) error {
+			n := calls.Add(1)
+			body, _ := io.ReadAll(c.Request().Body)
+			received = append(received, c.Request().Header.Get("Idempotency-Key")+" "+string(body))
+			return c.JSON(http.StatusCreated, map[string]any{"order": n})
+		})
+
+		mcp := NewWithOptions(e, opts...)
+		require.NoError(t, mcp.Mount("/mcp"))
+		return mcp, &calls, &received
+	}
+	call := func(t *testing.T, mcp *EchoMCP, arguments map[string]any) any {
+		t.Helper()
+		result, err := mcp.handleToolCall(context.Background(), map[string]any{"name": "POST_orders", "arguments": arguments})
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("Should execute a duplicate call with the same key once", func(t *testing.T) {
+		mcp, calls, received := newServer(t, WithIdempotencyTTL(time.Minute))
+
+		first := call(t, mcp, map[string]any{"item": "book", "_idempotencyKey": "abc"})
+		second := call(t, mcp, map[string]any{"item": "book", "_idempotencyKey": "abc"})
+
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Equal(t, first, second)
+		assert.Equal(t, []string{` {"item":"book"}`}, *received)
+	})
+
+	t.Run("Should execute calls with different keys", func(t *testing.T) {
+		mcp, calls, _ := newServer(t, WithIdempotencyTTL(time.Minute))
+
+		call(t, mcp, map[string]any{"item": "book", "_idempotencyKey": "abc"})
+		call(t, mcp, map[string]any{"item": "book", "_idempotencyKey": "def"})
+		call(t, mcp, map[string]any{"item": "book"})
+
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("Should execute the call again after the TTL", func(t *testing.T) {
+		mcp, calls, _ := newServer(t, WithIdempotencyTTL(20*time.Millisecond))
+
+		call(t, mcp, map[string]any{"_idempotencyKey": "abc"})
+		time.Sleep(40 * time.Millisecond)
+		call(t, mcp, map[string]any{"_idempotencyKey": "abc"})
+
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("Should evict the least recently used keys", func(t *testing.T) {
+		mcp, calls, _ := newServer(t, WithIdempotencyTTL(time.Minute), WithIdempotencyMaxEntries(2))
+
+		call(t, mcp, map[string]any{"_idempotencyKey": "a"})
+		call(t, mcp, map[string]any{"_idempotencyKey": "b"})
+		call(t, mcp, map[string]any{"_idempotencyKey": "a"})
+		call(t, mcp, map[string]any{"_idempotencyKey": "c"})
+		assert.Equal(t, int32(3), calls.Load())
+
+		call(t, mcp, map[string]any{"_idempotencyKey": "a"})
+		assert.Equal(t, int32(3), calls.Load())
+
+		call(t, mcp, map[string]any{"_idempotencyKey": "b"})
+		assert.Equal(t, int32(4), calls.Load())
+	})
+
+	t.Run("Should wait for an in-flight call with the same key", func(t *testing.T) {
+		var calls atomic.Int32
+		started := make(chan struct{})
+		release := make(chan struct{})
+		e := echo.New()
+		e.POST("/orders", func(c echo.Context) error {
+			if calls.Add(1) == 1 {
+				close(started)
+				<-release
+			}
+			return c.JSON(http.StatusCreated, map[string]any{"order": 1})
+		})
+		mcp := NewWithOptions(e, WithIdempotencyTTL(time.Minute))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		results := make(chan any, 2)
+		send := func() {
+			result, err := mcp.handleToolCall(context.Background(), map[string]any{
+				"name": "POST_orders", "arguments": map[string]any{"_idempotencyKey": "abc"},
+			})
+			assert.NoError(t, err)
+			results <- result
+		}
+		go send()
+		<-started
+		go send()
+		close(release)
+
+		first, second := <-results, <-results
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("Should forward the key as a header", func(t *testing.T) {
+		mcp, calls, received := newServer(t, WithIdempotencyTTL(time.Minute), WithForwardIdempotencyKey())
+
+		call(t, mcp, map[string]any{"item": "book", "_idempotencyKey": "abc"})
+		call(t, mcp, map[string]any{"item": "book", "_idempotencyKey": "abc"})
+
+		assert.Equal(t, int32(2), calls.Load())
+		assert.Equal(t, []string{`abc {"item":"book"}`, `abc {"item":"book"}`}, *received)
+	})
+
+	t.Run("Should strip the key when replays are disabled", func(t *testing.T) {
+		mcp, calls, received := newServer(t)
+
+		call(t, mcp, map[string]any{"item": "book", "_idempotencyKey": "abc"})
+		call(t, mcp, map[string]any{"item": "book", "_idempotencyKey": "abc"})
+
+		assert.Equal(t, int32(2), calls.Load())
+		assert.Equal(t, []string{` {"item":"book"}`, ` {"item":"book"}`}, *received)
+	})
+}
+
+func TestToolPrefix(t *testing.T) {
+	newServer := func(t *testing.T, prefix string) *EchoMCP {
+		t.Helper()
+
+		e := echo.New()
+		e.GET("/users/:id", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]string{"id": c.Param("id")})
+		})
+		e.GET("/files/résumé.pdf", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+		mcp := NewWithOptions(e, WithToolPrefix(prefix))
+		require.NoError(t, mcp.RegisterTool(types.Tool{Name: "ping"}, func(map[string]any) (any, error) {
+			return "pong", nil
+		}))
+		require.NoError(t, mcp.Mount("/mcp"))
+		return mcp
+	}
+
+	t.Run("Should list prefixed and sanitized tool names", func(t *testing.T) {
+		mcp := newServer(t, "billing.")
+
+		var names []string
+		for _, tool := range mcp.GetTools() {
+			names = append(names, tool.Name)
+		}
+
+		assert.ElementsMatch(t, []string{"billing_GET_files_r_sum_pdf", "billing_GET_users_id", "billing_ping"}, names)
+		assert.Empty(t, mcp.Warnings())
+	})
+
+	t.Run("Should call route and custom tools by their prefixed names", func(t *testing.T) {
+		mcp := newServer(t, "billing_")
+
+		result, err := mcp.handleToolCall(context.Background(), map[string]any{
+			"name":      "billing_GET_users_id",
+			"arguments": map[string]any{"id": "42"},
+		})
+		require.NoError(t, err)
+		assert.Contains(t, result.(ToolCallResponse).Content[0].Text, "42")
+
+		result, err = mcp.handleToolCall(context.Background(), map[string]any{"name": "billing_ping"})
+		require.NoError(t, err)
+		assert.Equal(t, "pong", result.(ToolCallResponse).Content[0].Text)
+	})
+
+	t.Run("Should reject unprefixed tool names", func(t *testing.T) {
+		mcp := newServer(t, "billing_")
+
+		_, err := mcp.handleToolCall(context.Background(), map[string]any{"name": "GET_users_id"})
+
+		var mcpErr *types.MCPError
+		require.ErrorAs(t, err, &mcpErr)
+		assert.Contains(t, mcpErr.Data.(map[string]any)["suggestions"], "billing_GET_users_id")
+	})
+
+	t.Run("Should shorten route tools and warn about custom tools exceeding the length limit", func(t *testing.T) {
+		prefix := strings.Repeat("a", 62)
+		mcp := newServer(t, prefix)
+
+		for _, tool := range mcp.GetTools() {
+			if tool.Name != prefix+"ping" {
+				assert.LessOrEqual(t, len(tool.Name), 64, tool.Name)
+			}
+		}
+		require.Len(t, mcp.Warnings(), 1)
+		assert.Contains(t, mcp.Warnings()[0], prefix+"ping")
+	})
+}
+
+func TestFlattenBodySchemaConfig(t *testing.T) {
+	t.Run("Should send flattened body arguments as the request body", func(t *testing.T) {
+		e := echo.New()
+		var received map[string]any
+		e.PUT("/users/:id", func(c echo.Context) error {
+			if err := json.NewDecoder(c.Request().Body).Decode(&received); err != nil {
+				return err
+			}
+			return c.JSON(http.StatusOK, map[string]string{"id": c.Param("id")})
+		})
+
+		mcp := NewWithOptions(e, WithFlattenBodySchema())
+		mcp.swaggerSpec = &swagger.SwaggerSpec{
+			Paths: map[string]swagger.SwaggerPath{
+				"/users/{id}": {
+					"put": swagger.SwaggerOperation{
+						Parameters: []swagger.SwaggerParameter{
+							{Name: "id", In: "path", Type: "string", Required: true},
+							{Name: "body", In: "body", Required: true, Schema: &swagger.SwaggerSchema{
+								Type:       "object",
+								Properties: map[string]*swagger.SwaggerSchema{"name": {Type: "string"}},
+							}},
+						},
+					},
+				},
+			},
+		}
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		schema := mcp.GetTools()[0].InputSchema.(map[string]any)
+		assert.Contains(t, schema["properties"], "name")
+		assert.NotContains(t, schema["properties"], "body")
+
+		_, err := mcp.handleToolCall(context.Background(), map[string]any{
+			"name":      "PUT_users_id",
+			"arguments": map[string]any{"id": "7", "name": "Jane"},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{"name": "Jane"}, received)
+	})
+}
+
+func TestXMLBodies(t *testing.T) {
+	type note struct {
+		XMLName xml.Name `xml:"note" json:"-"`
+		To      string   `xml:"to" json:"to"`
+		Message string   `xml:"message" json:"message"`
+	}
+
+	t.Run("Should round-trip a registered type through an XML handler", func(t *testing.T) {
+		e := echo.New()
+		var contentType string
+		e.POST("/notes", func(c echo.Context) error {
+			contentType = c.Request().Header.Get(echo.HeaderContentType)
+			var n note
+			if err := xml.NewDecoder(c.Request().Body).Decode(&n); err != nil {
+				return err
+			}
+			return c.XML(http.StatusOK, n)
+		})
+
+		mcp := New(e)
+		mcp.RegisterSchema(http.MethodPost, "/notes", nil, note{})
+		mcp.swaggerSpec = &swagger.SwaggerSpec{
+			Paths: map[string]swagger.SwaggerPath{
+				"/notes": {"post": swagger.SwaggerOperation{Consumes: []string{"application/xml"}}},
+			},
+		}
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.executeToolFunc(context.Background(), "POST_notes", map[string]any{"to": "Jane", "message": "hi"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "application/xml", contentType)
+		assert.Equal(t, map[string]any{"note": map[string]any{"to": "Jane", "message": "hi"}}, result)
+	})
+
+	t.Run("Should wrap arguments in a request element without a registered type", func(t *testing.T) {
+		e := echo.New()
+		e.POST("/echo", func(c echo.Context) error {
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				return err
+			}
+			return c.Blob(http.StatusOK, echo.MIMEApplicationXMLCharsetUTF8, body)
+		})
+
+		mcp := New(e)
+		mcp.swaggerSpec = &swagger.SwaggerSpec{
+			Paths: map[string]swagger.SwaggerPath{
+				"/echo": {"post": swagger.SwaggerOperation{Consumes: []string{"text/xml"}}},
+			},
+		}
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.executeToolFunc(context.Background(), "POST_echo", map[string]any{
+			"to":   "Jane",
+			"tags": []any{"a", "b"},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{"request": map[string]any{"to": "Jane", "tags": []any{"a", "b"}}}, result)
+	})
+
+	t.Run("Should keep JSON bodies when the operation also consumes JSON", func(t *testing.T) {
+		e := echo.New()
+		var contentType string
+		e.POST("/notes", func(c echo.Context) error {
+			contentType = c.Request().Header.Get(echo.HeaderContentType)
+			return c.NoContent(http.StatusOK)
+		})
+
+		mcp := New(e)
+		mcp.swaggerSpec = &swagger.SwaggerSpec{
+			Paths: map[string]swagger.SwaggerPath{
+				"/notes": {"post": swagger.SwaggerOperation{Consumes: []string{"application/json", "application/xml"}}},
+			},
+		}
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		_, err := mcp.executeToolFunc(context.Background(), "POST_notes", map[string]any{"to": "Jane"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "application/json", contentType)
+	})
+}
+
+func TestToolNameAffixesConfig(t *testing.T) {
+	t.Run("Should call tools by their prefixed and suffixed names", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, []string{"Jane"})
+		})
+
+		mcp := NewWithConfig(e, &Config{ToolNamePrefix: "user_service_", ToolNameSuffix: "_v1"})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		require.Len(t, mcp.GetTools(), 1)
+		assert.Equal(t, "user_service_GET_users_v1", mcp.GetTools()[0].Name)
+
+		result, err := mcp.handleToolCall(context.Background(), map[string]any{"name": "user_service_GET_users_v1"})
+		require.NoError(t, err)
+		assert.Contains(t, result.(ToolCallResponse).Content[0].Text, "Jane")
+	})
+}
+
+func TestRegisterStaticParams(t *testing.T) {
+	type captured struct {
+		apiVersion string
+		year       string
+		source     []string
+	}
+
+	newServer := func(t *testing.T) (*EchoMCP, *captured) {
+		t.Helper()
+
+		received := &captured{}
+		e := echo.New()
+		e.GET("/reports", func(c echo.Context) error {
+			received.apiVersion = c.QueryParam("api-version")
+			received.year = c.QueryParam("year")
+			received.source = c.Request().Header.Values("X-Source")
+			return c.NoContent(http.StatusOK)
+		})
+
+		mcp := New(e)
+		mcp.RegisterSchema(http.MethodGet, "/reports", struct {
+			Year       int    `json:"year"`
+			APIVersion string `json:"api-version" jsonschema:"required"`
+		}{}, nil)
+		mcp.RegisterStaticParams(http.MethodGet, "/reports",
+			map[string]string{"api-version": "2024-01-01"},
+			map[string]string{"X-Source": "mcp"},
+		)
+		require.NoError(t, mcp.Mount("/mcp"))
+		return mcp, received
+	}
+
+	t.Run("Should hide static parameters from the input schema", func(t *testing.T) {
+		mcp, _ := newServer(t)
+
+		schema := mcp.GetTools()[0].InputSchema.(map[string]any)
+
+		assert.Contains(t, schema["properties"], "year")
+		assert.NotContains(t, schema["properties"], "api-version")
+		assert.NotContains(t, schema, "required")
+	})
+
+	t.Run("Should always send static values, overriding arguments", func(t *testing.T) {
+		mcp, received := newServer(t)
+
+		_, err := mcp.handleToolCall(context.Background(), map[string]any{
+			"name": "GET_reports",
+			"arguments": map[string]any{
+				"year":        2024,
+				"api-version": "1999-01-01",
+				"x-source":    "model",
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "2024-01-01", received.apiVersion)
+		assert.Equal(t, "2024", received.year)
+		assert.Equal(t, []string{"mcp"}, received.source)
+	})
+
+	t.Run("Should keep static parameters registered before the schema or the route", func(t *testing.T) {
+		var versions []string
+		handler := func(c echo.Context) error {
+			versions = append(versions, c.QueryParam("api-version"))
+			return c.NoContent(http.StatusOK)
+		}
+
+		e := echo.New()
+		e.GET("/reports", handler)
+
+		mcp := New(e)
+		for _, path := range []string{"/reports", "/exports"} {
+			mcp.RegisterStaticParams(http.MethodGet, path, map[string]string{"api-version": "2024-01-01"}, nil)
+		}
+		mcp.RegisterSchemaFromStruct(http.MethodGet, "/reports", struct {
+			Year int `query:"year"`
+		}{})
+		mcp.Handle(http.MethodGet, "/exports", handler, WithRouteDescription("Export reports"))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		for _, name := range []string{"GET_reports", "GET_exports"} {
+			_, err := mcp.handleToolCall(context.Background(), map[string]any{
+				"name":      name,
+				"arguments": map[string]any{"api-version": "1999-01-01"},
+			})
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, []string{"2024-01-01", "2024-01-01"}, versions)
+		for _, tool := range mcp.GetTools() {
+			properties := tool.InputSchema.(map[string]any)["properties"]
+			assert.NotContains(t, properties, "api-version")
+			if tool.Name == "GET_reports" {
+				assert.Contains(t, properties, "year")
+			}
+		}
+	})
+}
+
+func TestRegisterToolAlias(t *testing.T) {
+	newServer := func(t *testing.T, hideOriginal bool) (*EchoMCP, *[]string) {
+		t.Helper()
+
+		var received []string
+		e := echo.New()
+		e.GET("/search", func(c echo.Context) error {
+			received = append(received, c.QueryParam("type"))
+			return c.NoContent(http.StatusOK)
+		})
+
+		mcp := New(e)
+		mcp.RegisterSchema(http.MethodGet, "/search", struct {
+			Type  string `json:"type" jsonschema:"required"`
+			Query string `json:"q"`
+		}{}, nil)
+		require.NoError(t, mcp.RegisterToolAlias("search_users", "Search users", http.MethodGet, "/search", map[string]any{"type": "users"}, nil))
+		require.NoError(t, mcp.RegisterToolAlias("search_orders", "Search orders", http.MethodGet, "/search", map[string]any{"type": "orders"}, nil))
+		if hideOriginal {
+			mcp.HideOriginal(http.MethodGet, "/search")
+		}
+		require.NoError(t, mcp.Mount("/mcp"))
+		return mcp, &received
+	}
+
+	toolByName := func(mcp *EchoMCP, name string) (types.Tool, bool) {
+		for _, tool := range mcp.GetTools() {
+			if tool.Name == name {
+				return tool, true
+			}
+		}
+		return types.Tool{}, false
+	}
+
+	t.Run("Should send the bound arguments of each alias", func(t *testing.T) {
+		mcp, received := newServer(t, false)
+
+		for _, name := range []string{"search_users", "search_orders"} {
+			_, err := mcp.handleToolCall(context.Background(), map[string]any{
+				"name":      name,
+				"arguments": map[string]any{"q": "john", "type": "invoices"},
+			})
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, []string{"users", "orders"}, *received)
+	})
+
+	t.Run("Should remove the bound arguments from the alias schema", func(t *testing.T) {
+		mcp, _ := newServer(t, false)
+
+		alias, exists := toolByName(mcp, "search_users")
+		require.True(t, exists)
+		schema := alias.InputSchema.(map[string]any)
+
+		assert.Equal(t, "Search users", alias.Description)
+		assert.Contains(t, schema["properties"], "q")
+		assert.NotContains(t, schema["properties"], "type")
+		assert.NotContains(t, schema, "required")
+
+		_, exists = toolByName(mcp, "GET_search")
+		assert.True(t, exists)
+	})
+
+	t.Run("Should hide the original tool but keep the aliases callable", func(t *testing.T) {
+		mcp, received := newServer(t, true)
+
+		_, exists := toolByName(mcp, "GET_search")
+		assert.False(t, exists)
+		_, exists = toolByName(mcp, "search_orders")
+		assert.True(t, exists)
+
+		_, err := mcp.handleToolCall(context.Background(), map[string]any{
+			"name":      "search_orders",
+			"arguments": map[string]any{"q": "42"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"orders"}, *received)
+	})
+
+	t.Run("Should reject empty and duplicate alias names", func(t *testing.T) {
+		mcp := New(echo.New())
+
+		require.Error(t, mcp.RegisterToolAlias("", "", http.MethodGet, "/search", nil, nil))
+		require.NoError(t, mcp.RegisterToolAlias("search_users", "", http.MethodGet, "/search", nil, nil))
+		require.Error(t, mcp.RegisterToolAlias("search_users", "", http.MethodGet, "/search", nil, nil))
+	})
+}
+
+func TestCORSOrigins(t *testing.T) {
+	newEcho := func(t *testing.T, origins ...string) *echo.Echo {
+		t.Helper()
+
+		e := echo.New()
+		e.GET("/ping", func(c echo.Context) error { return c.String(http.StatusOK, "pong") })
+		require.NoError(t, NewWithOptions(e, WithCORSOrigins(origins...)).Mount("/mcp"))
+		return e
+	}
+
+	call := func(e *echo.Echo, method, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Origin", origin)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("Should answer preflight requests with 204", func(t *testing.T) {
+		rec := call(newEcho(t, "https://app.example.com"), http.MethodOptions, "https://app.example.com")
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Contains(t, rec.Header().Get("Access-Control-Allow-Methods"), http.MethodPost)
+		assert.Contains(t, rec.Header().Get("Access-Control-Allow-Headers"), "Mcp-Session-Id")
+		assert.Contains(t, rec.Header().Get("Access-Control-Allow-Headers"), DryRunHeader)
+	})
+
+	t.Run("Should add CORS headers to MCP responses", func(t *testing.T) {
+		rec := call(newEcho(t, "*"), http.MethodPost, "https://other.example.com")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "https://other.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Contains(t, rec.Header().Get("Access-Control-Expose-Headers"), "Mcp-Session-Id")
+	})
+
+	t.Run("Should not allow unlisted origins", func(t *testing.T) {
+		rec := call(newEcho(t, "https://app.example.com"), http.MethodPost, "https://evil.example.com")
+
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("Should not register a preflight route without origins", func(t *testing.T) {
+		e := newEcho(t)
+		rec := call(e, http.MethodOptions, "https://app.example.com")
+
+		// Echo answers OPTIONS for any matched path itself, so check the route is absent
+		assert.NotContains(t, routeKeys(e.Routes()), "OPTIONS /mcp")
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
+
+// streamingTransport is an HTTP transport that records tools changed notifications
+type streamingTransport struct {
+	*transport.HTTPTransport
+	notifications atomic.Int32
+}
+
+func (s *streamingTransport) NotifyToolsChanged() {
+	s.notifications.Add(1)
+}
+
+func (s *streamingTransport) SupportsStreaming() bool {
+	return true
+}
+
+func TestRefreshTools(t *testing.T) {
+	newServer := func(t *testing.T) (*echo.Echo, *EchoMCP, *streamingTransport) {
+		t.Helper()
+
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+		mcp := New(e)
+		require.NoError(t, mcp.Mount("/mcp"))
+		streaming := &streamingTransport{HTTPTransport: mcp.transport.(*transport.HTTPTransport)}
+		mcp.transport = streaming
+		return e, mcp, streaming
+	}
+
+	toolNames := func(mcp *EchoMCP) []string {
+		var names []string
+		for _, tool := range mcp.GetTools() {
+			names = append(names, tool.Name)
+		}
+		return names
+	}
+
+	t.Run("Should list routes added after Mount and notify clients", func(t *testing.T) {
+		e, mcp, streaming := newServer(t)
+		e.GET("/reports", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+		changed, err := mcp.RefreshTools()
+		require.NoError(t, err)
+
+		assert.True(t, changed)
+		assert.Equal(t, []string{"GET_reports", "GET_users"}, toolNames(mcp))
+		assert.Equal(t, int32(1), streaming.notifications.Load())
+	})
+
+	t.Run("Should notify clients when tools/list picks up new routes", func(t *testing.T) {
+		e, mcp, streaming := newServer(t)
+		e.GET("/reports", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+		response, err := mcp.handleToolsList(nil)
+		require.NoError(t, err)
+		assert.Len(t, response.(ToolsListResponse).Tools, 2)
+		assert.Equal(t, int32(1), streaming.notifications.Load())
+
+		changed, err := mcp.RefreshTools()
+		require.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, int32(1), streaming.notifications.Load())
+	})
+
+	t.Run("Should log schema changes picked up by tools/list", func(t *testing.T) {
+		var logs bytes.Buffer
+		log.SetOutput(&logs)
+		t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+		_, mcp, _ := newServer(t)
+		mcp.RegisterSchema(http.MethodGet, "/users", struct {
+			Page int `json:"page" query:"page"`
+		}{}, nil)
+
+		_, err := mcp.handleToolsList(nil)
+		require.NoError(t, err)
+
+		assert.Contains(t, logs.String(), "Schema of tool 'GET_users' changed: page: added")
+	})
+
+	t.Run("Should not notify clients when the tools did not change", func(t *testing.T) {
+		_, mcp, streaming := newServer(t)
+
+		changed, err := mcp.RefreshTools()
+		require.NoError(t, err)
+		require.NoError(t, mcp.Refresh())
+
+		assert.False(t, changed)
+		assert.Zero(t, streaming.notifications.Load())
+	})
+
+	t.Run("Should poll for route changes", func(t *testing.T) {
+		e := echo.New()
+		mcp := NewWithOptions(e, WithRouteRefreshInterval(10*time.Millisecond))
+		require.NoError(t, mcp.Mount("/mcp"))
+		t.Cleanup(func() { _ = mcp.Shutdown(context.Background()) })
+
+		e.GET("/reports", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+		assert.Eventually(t, func() bool { return slices.Contains(toolNames(mcp), "GET_reports") }, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Should advertise listChanged only with a streaming transport", func(t *testing.T) {
+		mcp := New(echo.New())
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		response, err := mcp.handleInitialize(nil)
+		require.NoError(t, err)
+		assert.NotContains(t, response.(InitializeResponse).Capabilities.Tools, "listChanged")
+
+		_, streamingMCP, _ := newServer(t)
+		response, err = streamingMCP.handleInitialize(nil)
+		require.NoError(t, err)
+		assert.Equal(t, true, response.(InitializeResponse).Capabilities.Tools["listChanged"])
+	})
+}
+
+func TestNoGenericBody(t *testing.T) {
+	retry := func(c echo.Context) error { return c.NoContent(http.StatusAccepted) }
+
+	properties := func(t *testing.T, mcp *EchoMCP) map[string]any {
+		t.Helper()
+		require.NoError(t, mcp.Mount("/mcp"))
+		return mcp.GetTools()[0].InputSchema.(map[string]any)["properties"].(map[string]any)
+	}
+
+	t.Run("Should keep the generic body when unset", func(t *testing.T) {
+		e := echo.New()
+		e.POST("/jobs/:id/retry", retry)
+
+		assert.Contains(t, properties(t, New(e)), "body")
+	})
+
+	t.Run("Should drop the generic body when set", func(t *testing.T) {
+		e := echo.New()
+		e.POST("/jobs/:id/retry", retry)
+
+		assert.NotContains(t, properties(t, NewWithOptions(e, WithNoGenericBody())), "body")
+	})
+
+	t.Run("Should drop the generic body of a single route", func(t *testing.T) {
+		e := echo.New()
+		e.POST("/jobs/:id/retry", retry)
+		mcp := New(e)
+		mcp.SetGenericBody(http.MethodPost, "/jobs/:id/retry", false)
+
+		assert.NotContains(t, properties(t, mcp), "body")
+	})
+
+	t.Run("Should drop the generic body of routes declared without body", func(t *testing.T) {
+		mcp := New(echo.New())
+		mcp.Handle(http.MethodPost, "/jobs/:id/retry", retry, WithoutBody())
+
+		assert.NotContains(t, properties(t, mcp), "body")
+	})
+}
+
+func TestBodylessMethods(t *testing.T) {
+	e := echo.New()
+	e.DELETE("/users", func(c echo.Context) error {
+		var filter map[string]any
+		if err := c.Bind(&filter); err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, map[string]any{"deleted": filter["status"]})
+	})
+	e.DELETE("/users/:id", func(c echo.Context) error { return c.NoContent(http.StatusNoContent) })
+	e.HEAD("/reports/:id", func(c echo.Context) error {
+		c.Response().Header().Set("ETag", `"v2"`)
+		c.Response().Header().Set(echo.HeaderContentLength, "1024")
+		return c.NoContent(http.StatusOK)
+	})
+
+	mcp := NewWithOptions(e, WithAllowDeleteBody(), WithExcludeHTTPMethods())
+	require.NoError(t, mcp.Mount("/mcp"))
+
+	t.Run("Should send DELETE bodies to the handler", func(t *testing.T) {
+		result, err := mcp.defaultExecuteTool(context.Background(), "DELETE_users", map[string]any{"status": "inactive"})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{"deleted": "inactive"}, result)
+	})
+
+	t.Run("Should return the status of HEAD responses", func(t *testing.T) {
+		result, err := mcp.defaultExecuteTool(context.Background(), "HEAD_reports_id", map[string]any{"id": "7"})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{
+			"status":  http.StatusOK,
+			"headers": map[string]any{"Content-Length": "1024", "ETag": `"v2"`},
+		}, result)
+	})
+
+	t.Run("Should return the status of 204 responses", func(t *testing.T) {
+		result, err := mcp.defaultExecuteTool(context.Background(), "DELETE_users_id", map[string]any{"id": "7"})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{"status": http.StatusNoContent}, result)
+	})
+}
+
+func TestMountOnGroup(t *testing.T) {
+	newServer := func(t *testing.T) (*echo.Echo, *EchoMCP) {
+		t.Helper()
+
+		e := echo.New()
+		api := e.Group("/api/v1", func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(c echo.Context) error {
+				if c.Request().Header.Get("Authorization") != "Bearer secret" {
+					return echo.ErrUnauthorized
+				}
+				return next(c)
+			}
+		})
+		api.GET("/users", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+		mcp := New(e)
+		require.NoError(t, mcp.MountOnGroup(api, "/mcp"))
+		return e, mcp
+	}
+
+	call := func(e *echo.Echo, authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("Should serve the MCP endpoint under the group prefix", func(t *testing.T) {
+		e, mcp := newServer(t)
+
+		assert.Equal(t, "/api/v1/mcp", mcp.transport.MountPath())
+		assert.Equal(t, http.StatusOK, call(e, "Bearer secret").Code)
+	})
+
+	t.Run("Should apply the group middleware", func(t *testing.T) {
+		e, _ := newServer(t)
+
+		assert.Equal(t, http.StatusUnauthorized, call(e, "").Code)
+	})
+
+	t.Run("Should not expose the MCP endpoint as a tool", func(t *testing.T) {
+		_, mcp := newServer(t)
+
+		assert.Equal(t, []string{"GET_api_v1_users"}, slices.Sorted(maps.Keys(mcp.GetOperations())))
+	})
+
+	t.Run("Should require a group", func(t *testing.T) {
+		assert.Error(t, New(echo.New()).MountOnGroup(nil, "/mcp"))
+	})
+}
+
+func TestBasePath(t *testing.T) {
+	newSpec := func() *swagger.SwaggerSpec {
+		return &swagger.SwaggerSpec{
+			BasePath: "/api/v1",
+			Paths: map[string]swagger.SwaggerPath{
+				"/users/{id}": {
+					"get": swagger.SwaggerOperation{
+						Summary: "Get a user",
+						Parameters: []swagger.SwaggerParameter{
+							{Name: "id", In: "path", Type: "integer", Required: true},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("Should match routes under the swagger basePath", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/api/v1/users/:id", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+		mcp := New(e)
+		mcp.swaggerSpec = newSpec()
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		tool := mcp.GetTools()[0]
+		assert.Contains(t, tool.Description, "Get a user")
+		assert.Equal(t, "integer", tool.InputSchema.(map[string]any)["properties"].(map[string]any)["id"].(map[string]any)["type"])
+	})
+
+	t.Run("Should prefix upstream requests with the base path", func(t *testing.T) {
+		upstream := echo.New()
+		upstream.GET("/api/v1/users/:id", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]string{"id": c.Param("id")})
+		})
+		srv := httptest.NewServer(upstream)
+		defer srv.Close()
+
+		var requested []string
+		client := &http.Client{
+			Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				requested = append(requested, req.URL.Path)
+				return http.DefaultTransport.RoundTrip(req)
+			}),
+		}
+
+		e := echo.New()
+		e.GET("/users/:id", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+		mcp := NewWithOptions(e, WithBaseURL(srv.URL), WithHTTPClient(client), WithBasePath("/api/v1"))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_users_id", map[string]any{"id": "7"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"id": "7"}, result)
+		assert.Equal(t, []string{"/api/v1/users/7"}, requested)
+	})
+
+	t.Run("Should not prefix paths already under the base path", func(t *testing.T) {
+		assert.Equal(t, "/api/v1/users/7", withBasePath("/api/v1/", "/api/v1/users/7"))
+		assert.Equal(t, "/api/v1?page=2", withBasePath("/api/v1", "/api/v1?page=2"))
+		assert.Equal(t, "/api/v1/api/v10", withBasePath("/api/v1", "/api/v10"))
+		assert.Equal(t, "/users", withBasePath("", "/users"))
+	})
+}
+
+func TestSwaggerSpecURL(t *testing.T) {
+	t.Run("Should load swagger schemas from the spec URL", func(t *testing.T) {
+		specServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{
+				"swagger": "2.0",
+				"info": {"title": "Users API", "version": "3.0.0"},
+				"paths": {"/users/{id}": {"get": {"summary": "Get a user by ID"}}}
+			}`))
+		}))
+		defer specServer.Close()
+		t.Cleanup(swagger.ResetSwaggerCache)
+
+		e := echo.New()
+		e.GET("/users/:id", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+		mcp := NewWithOptions(e, WithSwaggerSpecURL(specServer.URL+"/swagger.json"))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		assert.Equal(t, "Users API", mcp.name)
+		assert.Contains(t, mcp.GetTools()[0].Description, "Get a user by ID")
+	})
+
+	t.Run("Should warn when the spec URL cannot be loaded", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+		mcp := NewWithOptions(e, WithSwaggerSpecURL("http://127.0.0.1:0/swagger.json"))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		require.Len(t, mcp.Warnings(), 1)
+		assert.Contains(t, mcp.Warnings()[0], "failed to load swagger spec")
+	})
+}
+
+func TestStrictSchemas(t *testing.T) {
+	type createUserRequest struct {
+		Labels map[string]string `json:"labels"`
+		Name   string            `json:"name"`
+	}
+
+	newServer := func(t *testing.T, opts ...Option) (*EchoMCP, *int) {
+		t.Helper()
+
+		calls := 0
+		e := echo.New()
+		e.POST("/users", func(c echo.Context) error {
+			calls++
+			return c.NoContent(http.StatusCreated)
+		})
+
+		mcp := NewWithOptions(e, opts...)
+		mcp.RegisterSchema(http.MethodPost, "/users", nil, createUserRequest{})
+		require.NoError(t, mcp.Mount("/mcp"))
+		return mcp, &calls
+	}
+
+	t.Run("Should leave schemas open by default", func(t *testing.T) {
+		mcp, _ := newServer(t)
+
+		assert.NotContains(t, mcp.GetTools()[0].InputSchema, "additionalProperties")
+	})
+
+	t.Run("Should close schemas except map-typed fields", func(t *testing.T) {
+		mcp, _ := newServer(t, WithStrictSchemas())
+
+		schema := mcp.GetTools()[0].InputSchema.(map[string]any)
+		labels := schema["properties"].(map[string]any)["labels"].(map[string]any)
+
+		assert.Equal(t, false, schema["additionalProperties"])
+		assert.Equal(t, map[string]any{"type": "string"}, labels["additionalProperties"])
+	})
+
+	t.Run("Should reject unexpected arguments before calling the API", func(t *testing.T) {
+		mcp, calls := newServer(t, WithStrictSchemas())
+
+		_, err := mcp.handleToolCall(context.Background(), map[string]any{
+			"name":      "POST_users",
+			"arguments": map[string]any{"name": "Jane", "admin": true},
+		})
+
+		mcpErr := &types.MCPError{}
+		require.ErrorAs(t, err, &mcpErr)
+		assert.Equal(t, types.ErrCodeInvalidParams, mcpErr.Code)
+		assert.Contains(t, mcpErr.Message, "admin")
+		assert.Zero(t, *calls)
+	})
+
+	t.Run("Should accept declared arguments", func(t *testing.T) {
+		mcp, calls := newServer(t, WithStrictSchemas())
+
+		_, err := mcp.handleToolCall(context.Background(), map[string]any{
+			"name":      "POST_users",
+			"arguments": map[string]any{"name": "Jane", "labels": map[string]any{"team": "core"}, "_noCache": true},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, *calls)
+	})
+}
+
+func TestSwaggerValidation(t *testing.T) {
+	newServer := func(opts ...Option) *EchoMCP {
+		e := echo.New()
+		e.GET("/users/:id", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+		mcp := NewWithOptions(e, opts...)
+		mcp.swaggerSpec = &swagger.SwaggerSpec{
+			Paths: map[string]swagger.SwaggerPath{
+				"/users/{id}": {"get": swagger.SwaggerOperation{}},
+			},
+		}
+		return mcp
+	}
+
+	t.Run("Should record spec problems as warnings when enabled", func(t *testing.T) {
+		mcp := newServer(WithSwaggerValidation())
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		assert.Equal(t, []string{
+			"swagger warning: GET /users/{id}: operation has no summary or description",
+			"swagger error: GET /users/{id}: path parameter 'id' is not declared in parameters",
+		}, mcp.Warnings())
+	})
+
+	t.Run("Should not validate by default", func(t *testing.T) {
+		mcp := newServer()
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		assert.Empty(t, mcp.Warnings())
+	})
+}
+
+func TestNullableKeyword(t *testing.T) {
+	openAPIYAML := `
+openapi: 3.0.3
+paths:
+  /users:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                nickname:
+                  type: string
+                  nullable: true
+`
+	nickname := func(t *testing.T, opts ...Option) map[string]any {
+		t.Helper()
+
+		e := echo.New()
+		e.POST("/users", func(c echo.Context) error { return c.NoContent(http.StatusCreated) })
+
+		mcp := NewWithOptions(e, append(opts, WithOpenAPISchema(openAPIYAML))...)
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		schema := mcp.GetTools()[0].InputSchema.(map[string]any)
+		body := schema["properties"].(map[string]any)["body"].(map[string]any)
+		return body["properties"].(map[string]any)["nickname"].(map[string]any)
+	}
+
+	t.Run("Should use a type union by default", func(t *testing.T) {
+		assert.Equal(t, []any{"string", "null"}, nickname(t)["type"])
+	})
+
+	t.Run("Should use the nullable keyword when enabled", func(t *testing.T) {
+		schema := nickname(t, WithNullableKeyword())
+
+		assert.Equal(t, "string", schema["type"])
+		assert.Equal(t, true, schema["nullable"])
+	})
+}
+
+func TestExcludeExtension(t *testing.T) {
+	e := echo.New()
+	e.GET("/users", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+	e.POST("/admin/reset", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	mcp := New(e)
+	mcp.swaggerSpec = &swagger.SwaggerSpec{
+		Paths: map[string]swagger.SwaggerPath{
+			"/users":       {"get": swagger.SwaggerOperation{Summary: "List users"}},
+			"/admin/reset": {"post": swagger.SwaggerOperation{Extensions: map[string]any{swagger.ExcludeExtension: true}}},
+		},
+	}
+	require.NoError(t, mcp.Mount("/mcp"))
+
+	t.Run("Should not expose operations marked with x-mcp-exclude", func(t *testing.T) {
+		tools := mcp.GetTools()
+
+		require.Len(t, tools, 1)
+		assert.Equal(t, "GET_users", tools[0].Name)
+	})
+}
+
+// pushTransport is a streaming HTTP transport that records the messages pushed to each session
+type pushTransport struct {
+	*transport.HTTPTransport
+	pushed   map[string][]*types.MCPMessage
+	sessions []string
+	mu       sync.Mutex
+}
+
+func (p *pushTransport) SupportsStreaming() bool {
+	return true
+}
+
+func (p *pushTransport) Sessions() []string {
+	return p.sessions
+}
+
+func (p *pushTransport) Push(sessionID string, msg *types.MCPMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pushed[sessionID] = append(p.pushed[sessionID], msg)
+	return nil
+}
+
+func (p *pushTransport) levels(sessionID string) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var levels []string
+	for _, msg := range p.pushed[sessionID] {
+		levels = append(levels, msg.Params.(map[string]any)["level"].(string))
+	}
+	return levels
+}
+
+func TestLogging(t *testing.T) {
+	newServer := func(t *testing.T, opts ...Option) (*EchoMCP, *pushTransport) {
+		t.Helper()
+
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+		mcp := NewWithOptions(e, opts...)
+		require.NoError(t, mcp.Mount("/mcp"))
+		pusher := &pushTransport{
+			HTTPTransport: mcp.transport.(*transport.HTTPTransport),
+			sessions:      []string{"quiet", "default"},
+			pushed:        make(map[string][]*types.MCPMessage),
+		}
+		mcp.transport = pusher
+		return mcp, pusher
+	}
+
+	setLevel := func(mcp *EchoMCP, sessionID, level string) error {
+		_, err := mcp.handleSetLevel(transport.WithSessionID(context.Background(), sessionID), map[string]any{"level": level})
+		return err
+	}
+
+	t.Run("Should advertise the logging capability", func(t *testing.T) {
+		mcp, _ := newServer(t)
+
+		response, err := mcp.handleInitialize(nil)
+		require.NoError(t, err)
+
+		assert.NotNil(t, response.(InitializeResponse).Capabilities.Logging)
+	})
+
+	t.Run("Should filter log entries by session level", func(t *testing.T) {
+		mcp, pusher := newServer(t)
+		require.NoError(t, setLevel(mcp, "quiet", "error"))
+
+		mcp.LogToClient("debug", "sync", "skipped")
+		mcp.LogToClient("warning", "sync", "slow upstream")
+		mcp.LogToClient("critical", "sync", map[string]any{"failed": 3})
+
+		assert.Equal(t, []string{"critical"}, pusher.levels("quiet"))
+		assert.Equal(t, []string{"warning", "critical"}, pusher.levels("default"))
+
+		params := pusher.pushed["quiet"][0].Params.(map[string]any)
+		assert.Equal(t, "notifications/message", pusher.pushed["quiet"][0].Method)
+		assert.Equal(t, "sync", params["logger"])
+		assert.Equal(t, map[string]any{"failed": 3}, params["data"])
+	})
+
+	t.Run("Should reject unknown levels", func(t *testing.T) {
+		mcp, _ := newServer(t)
+
+		err := setLevel(mcp, "quiet", "verbose")
+
+		mcpErr := &types.MCPError{}
+		require.ErrorAs(t, err, &mcpErr)
+		assert.Equal(t, types.ErrCodeInvalidParams, mcpErr.Code)
+	})
+
+	t.Run("Should forget the level of closed sessions", func(t *testing.T) {
+		mcp, pusher := newServer(t)
+		require.NoError(t, setLevel(mcp, "quiet", "error"))
+
+		mcp.dropLogLevel("quiet")
+		mcp.LogToClient("info", "", "hello")
+
+		assert.Equal(t, []string{"info"}, pusher.levels("quiet"))
+		assert.NotContains(t, pusher.pushed["quiet"][0].Params, "logger")
+	})
+
+	t.Run("Should warn when the transport cannot push", func(t *testing.T) {
+		e := echo.New()
+		mcp := New(e)
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		mcp.LogToClient("error", "sync", "lost")
+
+		require.Len(t, mcp.Warnings(), 1)
+		assert.Contains(t, mcp.Warnings()[0], "cannot push log notifications")
+	})
+
+	t.Run("Should log tool call failures when enabled", func(t *testing.T) {
+		mcp, pusher := newServer(t, WithToolErrorLogging())
+		require.NoError(t, mcp.RegisterTool(types.Tool{Name: "sync"}, func(map[string]any) (any, error) {
+			return nil, errors.New("upstream unavailable")
+		}))
+
+		ctx := transport.WithSessionID(context.Background(), "default")
+		_, err := mcp.handleToolCall(ctx, map[string]any{"name": "sync"})
+		require.Error(t, err)
+
+		require.Len(t, pusher.pushed["default"], 1)
+		assert.Equal(t, map[string]any{
+			"level":  "warning",
+			"logger": "tools",
+			"data":   map[string]any{"tool": "sync", "error": "upstream unavailable"},
+		}, pusher.pushed["default"][0].Params)
+	})
+
+	t.Run("Should not log tool call failures by default", func(t *testing.T) {
+		mcp, pusher := newServer(t)
+		require.NoError(t, mcp.RegisterTool(types.Tool{Name: "sync"}, func(map[string]any) (any, error) {
+			return nil, errors.New("upstream unavailable")
+		}))
+
+		_, err := mcp.handleToolCall(transport.WithSessionID(context.Background(), "default"), map[string]any{"name": "sync"})
+		require.Error(t, err)
+
+		assert.Empty(t, pusher.pushed)
+	})
+}
+
+func TestCompletion(t *testing.T) {
+	type updateOrderRequest struct {
+		Status string `json:"status" jsonschema:"enum=pending|paid|packed|shipped"`
+	}
+
+	newServer := func(t *testing.T, opts ...Option) *EchoMCP {
+		t.Helper()
+
+		e := echo.New()
+		e.PATCH("/orders/:id", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+		mcp := NewWithOptions(e, opts...)
+		mcp.RegisterSchema(http.MethodPatch, "/orders/:id", nil, updateOrderRequest{})
+		require.NoError(t, mcp.Mount("/mcp"))
+		return mcp
+	}
+
+	complete := func(t *testing.T, mcp *EchoMCP, tool, arg, value string) CompletionValues {
+		t.Helper()
+
+		response, err := mcp.handleComplete(map[string]any{
+			"ref":      map[string]any{"type": "ref/tool", "name": tool},
+			"argument": map[string]any{"name": arg, "value": value},
+		})
+		require.NoError(t, err)
+		return response.(CompletionResponse).Completion
+	}
+
+	t.Run("Should advertise the completions capability", func(t *testing.T) {
+		response, err := newServer(t).handleInitialize(nil)
+		require.NoError(t, err)
+
+		assert.NotNil(t, response.(InitializeResponse).Capabilities.Completions)
+	})
+
+	t.Run("Should complete enum values by prefix", func(t *testing.T) {
+		completion := complete(t, newServer(t), "PATCH_orders_id", "status", "pa")
+
+		assert.Equal(t, CompletionValues{Values: []string{"paid", "packed"}, Total: 2}, completion)
+	})
+
+	t.Run("Should complete swagger parameter enums", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/orders", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+		mcp := New(e)
+		mcp.swaggerSpec = &swagger.SwaggerSpec{
+			Paths: map[string]swagger.SwaggerPath{
+				"/orders": {"get": swagger.SwaggerOperation{
+					Parameters: []swagger.SwaggerParameter{{Name: "sort", In: "query", Type: "string", Enum: []any{"newest", "oldest"}}},
+				}},
+			},
+		}
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		assert.Equal(t, []string{"newest", "oldest"}, complete(t, mcp, "GET_orders", "sort", "").Values)
+	})
+
+	t.Run("Should use registered providers filtered by prefix", func(t *testing.T) {
+		mcp := newServer(t, WithToolPrefix("shop_"))
+		ids := []string{"ord-100", "ord-101", "ord-200"}
+		mcp.RegisterCompletion("PATCH_orders_id", "id", func(prefix string) []string {
+			var matches []string
+			for _, id := range ids {
+				if strings.HasPrefix(id, prefix) {
+					matches = append(matches, id)
+				}
+			}
+			return matches
+		})
+
+		assert.Equal(t, []string{"ord-100", "ord-101"}, complete(t, mcp, "shop_PATCH_orders_id", "id", "ord-1").Values)
+	})
+
+	t.Run("Should cap results and report more values", func(t *testing.T) {
+		mcp := newServer(t)
+		mcp.RegisterCompletion("PATCH_orders_id", "id", func(string) []string {
+			values := make([]string, 150)
+			for i := range values {
+				values[i] = fmt.Sprintf("ord-%d", i)
+			}
+			return values
+		})
+
+		completion := complete(t, mcp, "PATCH_orders_id", "id", "")
+
+		assert.Len(t, completion.Values, 100)
+		assert.Equal(t, 150, completion.Total)
+		assert.True(t, completion.HasMore)
+	})
+
+	t.Run("Should return no values for undeclared arguments", func(t *testing.T) {
+		assert.Equal(t, []string{}, complete(t, newServer(t), "PATCH_orders_id", "unknown", "").Values)
+	})
+
+	t.Run("Should reject unknown tools", func(t *testing.T) {
+		_, err := newServer(t).handleComplete(map[string]any{
+			"ref":      map[string]any{"type": "ref/tool", "name": "DELETE_orders"},
+			"argument": map[string]any{"name": "status", "value": ""},
+		})
+
+		mcpErr := &types.MCPError{}
+		require.ErrorAs(t, err, &mcpErr)
+		assert.Equal(t, types.ErrCodeInvalidParams, mcpErr.Code)
+	})
+}
+
+func TestRegisteredFormData(t *testing.T) {
+	type signupForm struct {
+		Email    string `form:"email" json:"email"`
+		Referrer string `query:"ref" json:"ref"`
+	}
+
+	e := echo.New()
+	e.POST("/signup", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{
+			"contentType": c.Request().Header.Get(echo.HeaderContentType),
+			"email":       c.FormValue("email"),
+			"ref":         c.QueryParam("ref"),
+		})
+	})
+
+	mcp := New(e)
+	mcp.RegisterSchema(http.MethodPost, "/signup", signupForm{}, nil)
+	require.NoError(t, mcp.Mount("/mcp"))
+
+	t.Run("Should send registered form fields as form data", func(t *testing.T) {
+		result, err := mcp.defaultExecuteTool(context.Background(), "POST_signup", map[string]any{
+			"email": "jane@example.com",
+			"ref":   "newsletter",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{
+			"contentType": "application/x-www-form-urlencoded",
+			"email":       "jane@example.com",
+			"ref":         "newsletter",
+		}, result)
+	})
+
+	t.Run("Should send body schemas made only of form fields as form data", func(t *testing.T) {
+		type loginForm struct {
+			Username string `form:"username" json:"username"`
+		}
+
+		e := echo.New()
+		e.POST("/login", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]string{
+				"contentType": c.Request().Header.Get(echo.HeaderContentType),
+				"username":    c.FormValue("username"),
+			})
+		})
+
+		mcp := New(e)
+		mcp.RegisterSchema(http.MethodPost, "/login", nil, loginForm{})
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "POST_login", map[string]any{"username": "jane"})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{
+			"contentType": "application/x-www-form-urlencoded",
+			"username":    "jane",
+		}, result)
+	})
+
+	t.Run("Should send multipart forms when the operation only consumes multipart", func(t *testing.T) {
+		e := echo.New()
+		e.POST("/upload", func(c echo.Context) error {
+			mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+			return c.JSON(http.StatusOK, map[string]string{
+				"contentType": mediaType,
+				"title":       c.FormValue("title"),
+			})
+		})
+
+		mcp := New(e)
+		mcp.operations = map[string]types.Operation{
+			"POST_upload": {Method: http.MethodPost, Path: "/upload", FormDataParams: []string{"title"}, ContentType: "multipart/form-data"},
+		}
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "POST_upload", map[string]any{"title": "Report"})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{
+			"contentType": "multipart/form-data",
+			"title":       "Report",
+		}, result)
+	})
+}
+
+func TestResponseContentTypes(t *testing.T) {
+	newServer := func(t *testing.T, opts ...Option) *EchoMCP {
+		t.Helper()
+
+		e := echo.New()
+		e.GET("/plain", func(c echo.Context) error {
+			return c.String(http.StatusOK, "null")
+		})
+		e.GET("/json", func(c echo.Context) error {
+			return c.JSONBlob(http.StatusOK, []byte(`{"id":1}`))
+		})
+		e.GET("/problem", func(c echo.Context) error {
+			return c.Blob(http.StatusBadRequest, "application/problem+json", []byte(`{"title":"Bad Request"}`))
+		})
+		e.GET("/page", func(c echo.Context) error {
+			return c.HTML(http.StatusBadGateway, `<html><head><title>502 Bad Gateway</title><style>h1 {color: red}</style></head>`+
+				`<body><h1>Bad <b>Gateway</b></h1><p>The upstream server &amp; proxy failed.</p><script>track()</script></body></html>`)
+		})
+
+		mcp := NewWithOptions(e, opts...)
+		require.NoError(t, mcp.Mount("/mcp"))
+		return mcp
+	}
+
+	t.Run("Should return text/plain bodies verbatim", func(t *testing.T) {
+		result, err := newServer(t).defaultExecuteTool(context.Background(), "GET_plain", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "null", result)
+	})
+
+	t.Run("Should parse JSON and +json bodies", func(t *testing.T) {
+		mcp := newServer(t)
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_json", nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"id": float64(1)}, result)
+
+		result, err = mcp.defaultExecuteTool(context.Background(), "GET_problem", nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"title": "Bad Request"}, result)
+	})
+
+	t.Run("Should return HTML pages verbatim by default", func(t *testing.T) {
+		result, err := newServer(t).defaultExecuteTool(context.Background(), "GET_page", nil)
+		require.NoError(t, err)
+		assert.Contains(t, result, "<h1>Bad <b>Gateway</b></h1>")
+	})
+
+	t.Run("Should strip HTML pages to their text with StripHTML", func(t *testing.T) {
+		result, err := newServer(t, WithStripHTML()).defaultExecuteTool(context.Background(), "GET_page", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "502 Bad Gateway\nBad Gateway\nThe upstream server & proxy failed.", result)
+	})
+}
+
+func TestConcurrentToolCallLimit(t *testing.T) {
+	newServer := func(t *testing.T, opts ...Option) (*EchoMCP, chan struct{}, chan struct{}) {
+		t.Helper()
+
+		started := make(chan struct{}, 10)
+		unblock := make(chan struct{})
+		e := echo.New()
+		e.GET("/slow", func(c echo.Context) error {
+			started <- struct{}{}
+			<-unblock
+			return c.String(http.StatusOK, "done")
+		})
+
+		mcp := NewWithOptions(e, opts...)
+		require.NoError(t, mcp.Mount("/mcp"))
+		return mcp, started, unblock
+	}
+
+	call := func(ctx context.Context, mcp *EchoMCP) <-chan error {
+		result := make(chan error, 1)
+		go func() {
+			_, err := mcp.handleToolCall(ctx, map[string]any{"name": "GET_slow"})
+			result <- err
+		}()
+		return result
+	}
+
+	assertBusy := func(t *testing.T, err error) {
+		t.Helper()
+
+		var mcpErr *types.MCPError
+		require.ErrorAs(t, err, &mcpErr)
+		assert.Equal(t, types.ErrCodeServerBusy, mcpErr.Code)
+		assert.Contains(t, mcpErr.Message, "server busy")
+		assert.Equal(t, map[string]any{"code": BusyErrorCode}, mcpErr.Data)
+	}
+
+	t.Run("Should reject calls beyond the limit while the rest complete", func(t *testing.T) {
+		mcp, started, unblock := newServer(t, WithMaxConcurrentToolCalls(2))
+
+		running := []<-chan error{call(context.Background(), mcp), call(context.Background(), mcp)}
+		<-started
+		<-started
+
+		for range 3 {
+			assertBusy(t, <-call(context.Background(), mcp))
+		}
+
+		close(unblock)
+		for _, result := range running {
+			assert.NoError(t, <-result)
+		}
+		assert.NoError(t, <-call(context.Background(), mcp))
+	})
+
+	t.Run("Should limit each session separately", func(t *testing.T) {
+		mcp, started, unblock := newServer(t, WithMaxConcurrentToolCallsPerSession(1))
+		sessionA := transport.WithSessionID(context.Background(), "a")
+		sessionB := transport.WithSessionID(context.Background(), "b")
+
+		running := call(sessionA, mcp)
+		<-started
+
+		assertBusy(t, <-call(sessionA, mcp))
+
+		other := call(sessionB, mcp)
+		<-started
+
+		close(unblock)
+		assert.NoError(t, <-running)
+		assert.NoError(t, <-other)
+	})
+
+	t.Run("Should wait for a slot up to the queue timeout", func(t *testing.T) {
+		mcp, started, unblock := newServer(t, WithMaxConcurrentToolCalls(1), WithToolCallQueueTimeout(5*time.Second))
+
+		running := call(context.Background(), mcp)
+		<-started
+
+		queued := call(context.Background(), mcp)
+		close(unblock)
+
+		assert.NoError(t, <-running)
+		assert.NoError(t, <-queued)
+	})
+}
+
+func TestSwaggerHostBaseURL(t *testing.T) {
+	// newSpecServer serves a swagger spec with the given host and basePath ("{host}" is replaced
+	// with the address of the server itself) next to the API it describes
+	newSpecServer := func(t *testing.T, host, basePath string) *httptest.Server {
+		t.Helper()
+
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/swagger.json" {
+				w.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				_, _ = w.Write([]byte(`{"path":"` + r.URL.Path + `"}`))
+				return
+			}
+			host := strings.ReplaceAll(host, "{host}", strings.TrimPrefix(server.URL, "http://"))
+			_, _ = w.Write([]byte(`{
+				"swagger": "2.0",
+				"info": {"title": "Users API"},
+				"host": "` + host + `",
+				"basePath": "` + basePath + `",
+				"paths": {"/users": {"get": {"summary": "List users"}}}
+			}`))
+		}))
+		t.Cleanup(server.Close)
+		t.Cleanup(swagger.ResetSwaggerCache)
+		return server
+	}
+
+	newEcho := func() *echo.Echo {
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+		return e
+	}
+
+	t.Run("Should send calls to the swagger host and basePath", func(t *testing.T) {
+		server := newSpecServer(t, "{host}", "/api/v1")
+
+		mcp := NewWithOptions(newEcho(), WithSwaggerSpecURL(server.URL+"/swagger.json"), WithHTTPClient(server.Client()))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		assert.Equal(t, server.URL, mcp.baseURL)
+
+		result, err := mcp.defaultExecuteTool(context.Background(), "GET_users", nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"path": "/api/v1/users"}, result)
+	})
+
+	t.Run("Should default to http for a host without schemes", func(t *testing.T) {
+		server := newSpecServer(t, "api.example.com", "")
+
+		mcp := NewWithOptions(newEcho(), WithSwaggerSpecURL(server.URL+"/swagger.json"))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		assert.Equal(t, "http://api.example.com", mcp.baseURL)
+	})
+
+	t.Run("Should keep an explicit BaseURL", func(t *testing.T) {
+		server := newSpecServer(t, "api.example.com", "/api/v1")
+
+		mcp := NewWithOptions(newEcho(), WithSwaggerSpecURL(server.URL+"/swagger.json"), WithBaseURL("http://localhost:9000"))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		assert.Equal(t, "http://localhost:9000", mcp.baseURL)
+	})
+
+	t.Run("Should leave the base URL empty without a host", func(t *testing.T) {
+		server := newSpecServer(t, "", "/api/v1")
+
+		mcp := NewWithOptions(newEcho(), WithSwaggerSpecURL(server.URL+"/swagger.json"))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		assert.Empty(t, mcp.baseURL)
+		assert.Empty(t, mcp.Warnings())
+	})
+
+	t.Run("Should warn about an invalid host and still mount", func(t *testing.T) {
+		server := newSpecServer(t, "api example com", "")
+
+		mcp := NewWithOptions(newEcho(), WithSwaggerSpecURL(server.URL+"/swagger.json"))
+		require.NoError(t, mcp.Mount("/mcp"))
+
+		assert.Empty(t, mcp.baseURL)
+		require.Len(t, mcp.Warnings(), 1)
+		assert.Contains(t, mcp.Warnings()[0], "ignoring swagger host")
+	})
+}
+
+func TestUnmount(t *testing.T) {
+	newServer := func(t *testing.T) (*echo.Echo, *EchoMCP) {
+		t.Helper()
+
+		e := echo.New()
+		e.GET("/ping", func(c echo.Context) error { return c.String(http.StatusOK, "pong") })
+
+		mcp := New(e)
+		require.NoError(t, mcp.Mount("/mcp"))
+		return e, mcp
+	}
+
+	post := func(e *echo.Echo) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("Should reject a second Mount", func(t *testing.T) {
+		_, mcp := newServer(t)
+
+		err := mcp.Mount("/other")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already mounted at /mcp")
+	})
+
+	t.Run("Should answer 410 Gone and stop executing tools once unmounted", func(t *testing.T) {
+		e, mcp := newServer(t)
+		require.Equal(t, http.StatusOK, post(e).Code)
+
+		require.NoError(t, mcp.Unmount())
+
+		assert.Equal(t, http.StatusGone, post(e).Code)
+
+		_, err := mcp.handleToolCall(context.Background(), map[string]any{"name": "GET_ping"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Should not mount or unmount an unmounted server again", func(t *testing.T) {
+		_, mcp := newServer(t)
+		require.NoError(t, mcp.Unmount())
+
+		assert.Error(t, mcp.Unmount())
+		assert.Error(t, mcp.Mount("/mcp"))
+	})
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+)
+
+// maxToolSuggestions is the maximum number of similar tool names suggested for an unknown tool
+const maxToolSuggestions = 3
+
+// unknownToolError builds an invalid params error listing the tools closest to name
+func (e *EchoMCP) unknownToolError(name string) *types.MCPError {
+	tools := e.GetTools()
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+
+	suggestions := suggestToolNames(name, names)
+
+	message := fmt.Sprintf("tool '%s' not found in operations map (%d tools available)", name, len(names))
+	if len(suggestions) > 0 {
+		message += fmt.Sprintf("; did you mean: %s?", strings.Join(suggestions, ", "))
+	}
+
+	return types.NewMCPError(types.ErrCodeInvalidParams, message, map[string]any{
+		"suggestions": suggestions,
+		"toolCount":   len(names),
+	})
+}
+
+// suggestToolNames returns up to maxToolSuggestions names similar to name,
+// ordered by edit distance. Names sharing a prefix with name, or ending with it (such as
+// tool names under a ToolPrefix), are always candidates.
+func suggestToolNames(name string, names []string) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+
+	lowerName := strings.ToLower(name)
+	threshold := max(2, len(name)/3)
+
+	var candidates []candidate
+	for _, toolName := range names {
+		lowerToolName := strings.ToLower(toolName)
+		distance := levenshtein(lowerName, lowerToolName)
+		hasPrefix := lowerName != "" && (strings.HasPrefix(lowerToolName, lowerName) || strings.HasPrefix(lowerName, lowerToolName))
+		hasSuffix := lowerName != "" && strings.HasSuffix(lowerToolName, lowerName)
+		if distance <= threshold || hasPrefix || hasSuffix {
+			candidates = append(candidates, candidate{name: toolName, distance: distance})
+		}
+	}
+
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+		return strings.Compare(a.name, b.name)
+	})
+
+	suggestions := make([]string, 0, maxToolSuggestions)
+	for _, c := range candidates {
+		if len(suggestions) == maxToolSuggestions {
+			break
+		}
+		suggestions = append(suggestions, c.name)
+	}
+
+	return suggestions
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
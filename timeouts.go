@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/transport"
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+)
+
+// defaultProgressInterval is how often progress notifications are sent for a running tool call
+const defaultProgressInterval = 5 * time.Second
+
+// RegisterToolTimeout sets the timeout of the tool for a specific route, overriding
+// any x-mcp-timeout declared in the swagger spec. A zero duration removes the override.
+//
+// Example:
+//
+//	mcp.RegisterToolTimeout("POST", "/reports", 5*time.Minute)
+//	mcp.RegisterToolTimeout("GET", "/search", 2*time.Second)
+func (e *EchoMCP) RegisterToolTimeout(method, path string, timeout time.Duration) {
+	e.timeoutsMu.Lock()
+	defer e.timeoutsMu.Unlock()
+
+	key := fmt.Sprintf("%s %s", method, path)
+	if timeout <= 0 {
+		delete(e.toolTimeouts, key)
+		return
+	}
+	e.toolTimeouts[key] = timeout
+}
+
+// toolTimeout returns the timeout for an operation: a registered override first,
+// then the swagger x-mcp-timeout extension. Zero means no timeout.
+func (e *EchoMCP) toolTimeout(operation *types.Operation) time.Duration {
+	e.timeoutsMu.RLock()
+	timeout, exists := e.toolTimeouts[fmt.Sprintf("%s %s", operation.Method, operation.Path)]
+	e.timeoutsMu.RUnlock()
+
+	if exists {
+		return timeout
+	}
+	return operation.Timeout
+}
+
+// serveInProcess dispatches req through the Echo router and stops waiting once ctx is done
+func serveInProcess(ctx context.Context, target http.Handler, req *http.Request) (*http.Response, error) {
+	done := make(chan *http.Response, 1)
+	go func() {
+		rec := httptest.NewRecorder()
+		target.ServeHTTP(rec, req)
+		done <- rec.Result()
+	}()
+
+	select {
+	case resp := <-done:
+		return resp, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// startProgress sends notifications/progress for the progressToken of a tools/call request
+// until the returned stop function is called. Nothing is sent if the client did not ask
+// for progress or the transport cannot deliver notifications before the response.
+func (e *EchoMCP) startProgress(ctx context.Context, params map[string]any) (stop func()) {
+	meta, _ := params["_meta"].(map[string]any)
+	progressToken, hasToken := meta["progressToken"]
+	notifier := transport.NotifierFromContext(ctx)
+	if !hasToken || progressToken == nil || notifier == nil {
+		return func() {}
+	}
+
+	interval := e.config.ProgressInterval
+	if interval <= 0 {
+		interval = defaultProgressInterval
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		started := time.Now()
+		for progress := 1; ; progress++ {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = notifier(&types.MCPMessage{
+					Jsonrpc: "2.0",
+					Method:  "notifications/progress",
+					Params: map[string]any{
+						"progressToken": progressToken,
+						"progress":      progress,
+						"message":       fmt.Sprintf("still running after %s", time.Since(started).Round(time.Second)),
+					},
+				})
+			}
+		}
+	})
+
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
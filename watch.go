@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/transport"
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+)
+
+// RefreshTools rebuilds the tools list from the current Echo routes, registered schemas
+// and endpoint filters, and notifies clients through the transport when the list changed.
+// It reports whether any tool was added, removed or modified.
+//
+// Example:
+//
+//	e.GET("/reports", listReports) // registered after Mount
+//	if _, err := mcp.RefreshTools(); err != nil {
+//		log.Fatal(err)
+//	}
+func (e *EchoMCP) RefreshTools() (bool, error) {
+	// Serialize refreshes so every change is diffed, logged and notified exactly once
+	e.refreshMu.Lock()
+	defer e.refreshMu.Unlock()
+
+	previous := e.GetTools()
+
+	if err := e.setupServer(); err != nil {
+		return false, fmt.Errorf("failed to refresh server: %w", err)
+	}
+
+	current := e.GetTools()
+	changed := !reflect.DeepEqual(previous, current)
+	if changed {
+		logSchemaChanges(previous, current)
+	}
+	if changed && e.transport != nil {
+		e.transport.NotifyToolsChanged()
+	}
+
+	return changed, nil
+}
+
+// logSchemaChanges logs how the input schema of every tool listed both before and after
+// a refresh changed, so developers can see what a code update did to a tool. Every rebuild
+// of the tools list after Mount, including the one run by tools/list, goes through
+// RefreshTools and is logged here.
+func logSchemaChanges(previous, current []types.Tool) {
+	previousSchemas := make(map[string]map[string]any, len(previous))
+	for _, tool := range previous {
+		if schema, ok := tool.InputSchema.(map[string]any); ok {
+			previousSchemas[tool.Name] = schema
+		}
+	}
+
+	for _, tool := range current {
+		previousSchema, existed := previousSchemas[tool.Name]
+		schema, ok := tool.InputSchema.(map[string]any)
+		if !existed || !ok {
+			continue
+		}
+
+		for _, diff := range types.DiffSchemas(previousSchema, schema) {
+			log.Infof("[MCP] Schema of tool '%s' changed: %s", tool.Name, diff)
+		}
+	}
+}
+
+// watchRoutes refreshes the tools list every Config.RouteRefreshInterval until ctx is done,
+// picking up routes added to or removed from Echo after Mount
+func (e *EchoMCP) watchRoutes(ctx context.Context) {
+	ticker := time.NewTicker(e.config.RouteRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := e.RefreshTools(); err != nil {
+				log.Warnf("[MCP] Route refresh failed: %v", err)
+			}
+		}
+	}
+}
+
+// supportsListChanged reports whether the mounted transport can push
+// notifications/tools/list_changed to clients
+func (e *EchoMCP) supportsListChanged() bool {
+	streaming, ok := e.transport.(transport.StreamingTransport)
+	return ok && streaming.SupportsStreaming()
+}
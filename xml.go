@@ -0,0 +1,166 @@
+package server
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/BrunoKrugel/echo-mcp/pkg/types"
+)
+
+// xmlRootElement names the root element of XML bodies built without a registered Go type
+const xmlRootElement = "request"
+
+// marshalXMLBody encodes the body arguments of an operation as XML. When a struct type is
+// registered as the body schema of the route (RegisterSchema, Handle, ...) the arguments are
+// decoded into it so its XMLName and xml tags define the element names; otherwise the
+// arguments are written under a <request> root element.
+func (e *EchoMCP) marshalXMLBody(operation *types.Operation, bodyData map[string]any) ([]byte, error) {
+	e.schemasMu.RLock()
+	registered := e.registeredSchemas[fmt.Sprintf("%s %s", operation.Method, operation.Path)]
+	e.schemasMu.RUnlock()
+
+	if bodyType := reflect.TypeOf(registered.BodySchema); bodyType != nil {
+		if bodyType.Kind() == reflect.Pointer {
+			bodyType = bodyType.Elem()
+		}
+		if bodyType.Kind() == reflect.Struct {
+			data, err := sonic.Marshal(bodyData)
+			if err != nil {
+				return nil, err
+			}
+			body := reflect.New(bodyType).Interface()
+			if err := sonic.Unmarshal(data, body); err != nil {
+				return nil, err
+			}
+			return xml.Marshal(body)
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := xml.NewEncoder(&buf)
+	if err := encodeXMLElement(enc, xmlRootElement, bodyData); err != nil {
+		return nil, err
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeXMLElement writes value as an element named name: maps become child elements in
+// key order, slices become repeated elements and everything else becomes text
+func encodeXMLElement(enc *xml.Encoder, name string, value any) error {
+	if items, isList := value.([]any); isList {
+		for _, item := range items {
+			if err := encodeXMLElement(enc, name, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			if err := encodeXMLElement(enc, key, v[key]); err != nil {
+				return err
+			}
+		}
+	case nil:
+	default:
+		if err := enc.EncodeToken(xml.CharData(fmt.Sprintf("%v", v))); err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+// decodeXML parses an XML document into a map keyed by its root element name. Elements with
+// children become maps (repeated children become lists), attributes are keyed "@name" and
+// text-only elements become strings.
+func decodeXML(data []byte) (map[string]any, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, errors.New("no XML root element")
+			}
+			return nil, err
+		}
+
+		if start, isStart := token.(xml.StartElement); isStart {
+			value, err := decodeXMLElement(dec, start)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{start.Name.Local: value}, nil
+		}
+	}
+}
+
+// decodeXMLElement decodes the content of the element opened by start
+func decodeXMLElement(dec *xml.Decoder, start xml.StartElement) (any, error) {
+	element := make(map[string]any)
+	for _, attr := range start.Attr {
+		element["@"+attr.Name.Local] = attr.Value
+	}
+
+	var text strings.Builder
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			child, err := decodeXMLElement(dec, t)
+			if err != nil {
+				return nil, err
+			}
+			addXMLChild(element, t.Name.Local, child)
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			content := strings.TrimSpace(text.String())
+			if len(element) == 0 {
+				return content, nil
+			}
+			if content != "" {
+				element["#text"] = content
+			}
+			return element, nil
+		}
+	}
+}
+
+// addXMLChild adds a decoded child element, turning repeated elements into a list
+func addXMLChild(element map[string]any, name string, child any) {
+	existing, exists := element[name]
+	if !exists {
+		element[name] = child
+		return
+	}
+
+	if items, isList := existing.([]any); isList {
+		element[name] = append(items, child)
+		return
+	}
+	element[name] = []any{existing, child}
+}